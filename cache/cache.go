@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Entry is the cached output of generating the documentation of a chart.
+type Entry struct {
+	// Markdown is the rendered documentation, before it is injected.
+	Markdown string `json:"markdown"`
+	// Schema is the rendered JSON schema.
+	Schema string `json:"schema"`
+	// Linted is set if the chart was linted without issues.
+	Linted bool `json:"linted"`
+}
+
+// Cache stores entries on disk in a directory, keyed by the hash of the inputs
+// that were used to generate them.
+type Cache struct {
+	Dir string
+}
+
+// Get returns the entry for the key, if it exists.
+func (c Cache) Get(key *Key) (Entry, bool) {
+	contents, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return Entry{}, false
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		return Entry{}, false
+	}
+
+	return entry, true
+}
+
+// Put stores the entry for the key. The entry is written to a temporary file
+// first, so concurrent runs never read a partially written entry.
+func (c Cache) Put(key *Key, entry Entry) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return err
+	}
+
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.Dir, "entry-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := tmp.Write(contents); err != nil {
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+func (c Cache) path(key *Key) string {
+	return filepath.Join(c.Dir, key.String()+".json")
+}
+
+// Key is the hash of the inputs of a run, the same inputs always result in
+// the same key.
+type Key struct {
+	h hash.Hash
+}
+
+// NewKey returns an empty key.
+func NewKey() *Key {
+	return &Key{h: sha256.New()}
+}
+
+// AddString adds a setting to the key.
+func (k *Key) AddString(name, value string) {
+	fmt.Fprintf(k.h, "string %q %q\n", name, value)
+}
+
+// AddFile adds the contents of a file to the key, a missing file is recorded
+// as missing.
+func (k *Key) AddFile(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Fprintf(k.h, "missing %q\n", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	contentHash := sha256.New()
+	if _, err := io.Copy(contentHash, file); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(k.h, "file %q %x\n", path, contentHash.Sum(nil))
+	return nil
+}
+
+// AddDir adds the contents of all files in a directory to the key, except
+// for the excluded files (eg. the outputs of the run).
+func (k *Key) AddDir(dir string, exclude ...string) error {
+	excluded := map[string]bool{}
+	for _, path := range exclude {
+		excluded[filepath.Clean(path)] = true
+	}
+
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == dir {
+			fmt.Fprintf(k.h, "missing %q\n", path)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || excluded[filepath.Clean(path)] {
+			return nil
+		}
+
+		return k.AddFile(path)
+	})
+}
+
+// String returns the key as a hex string.
+func (k *Key) String() string {
+	return hex.EncodeToString(k.h.Sum(nil))
+}
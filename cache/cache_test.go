@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("a: 1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("readme"), 0644))
+
+	key := func() string {
+		k := NewKey()
+		k.AddString("template", "markdown-plain")
+		require.NoError(t, k.AddDir(dir, filepath.Join(dir, "README.md")))
+		return k.String()
+	}
+
+	original := key()
+	require.Equal(t, original, key())
+
+	// Excluded files do not change the key
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed"), 0644))
+	require.Equal(t, original, key())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte("a: 2\n"), 0644))
+	require.NotEqual(t, original, key())
+}
+
+func TestCache(t *testing.T) {
+	c := Cache{Dir: filepath.Join(t.TempDir(), "cache")}
+	key := NewKey()
+	key.AddString("values", "a: 1")
+
+	_, ok := c.Get(key)
+	require.False(t, ok)
+
+	entry := Entry{Markdown: "## a", Schema: "{}", Linted: true}
+	require.NoError(t, c.Put(key, entry))
+
+	cached, ok := c.Get(key)
+	require.True(t, ok)
+	require.Equal(t, entry, cached)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package completion creates compact completion data for the values of a
+// chart, which is used to complete the arguments of helm's --set flag.
+package completion
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// Value is a documented value that can be set using --set.
+type Value struct {
+	// Path is the path in the syntax of helm's --set flag.
+	Path string   `json:"path"`
+	Type string   `json:"type"`
+	Enum []string `json:"enum,omitempty"`
+	// Default is the default of values with a single line default that are
+	// not objects or arrays.
+	Default string `json:"default,omitempty"`
+}
+
+// Data is the completion data of a chart.
+type Data struct {
+	Chart  string  `json:"chart,omitempty"`
+	Values []Value `json:"values"`
+}
+
+// New returns the completion data of the document, hidden properties should
+// be excluded from the document as they are not meant to be set by users.
+func New(document *parser.Document) *Data {
+	data := &Data{Values: []Value{}}
+	if document.Chart != nil {
+		data.Chart = document.Chart.Name
+	}
+
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			value := Value{
+				Path: property.Path.SetString(),
+				Type: property.Type.String(),
+				Enum: property.Enum(),
+			}
+
+			if property.Type != parser.TypeObject && property.Type != parser.TypeArray {
+				value.Default = scalarDefault(property.Default)
+			}
+
+			data.Values = append(data.Values, value)
+		}
+	}
+
+	return data
+}
+
+// scalarDefault returns the default as it is passed to --set, eg. without the
+// quotes of a string. It returns an empty string for defaults that are not a
+// single line scalar.
+func scalarDefault(defaultValue string) string {
+	if strings.Contains(defaultValue, "\n") {
+		return ""
+	}
+
+	var value any
+	if err := yaml.Unmarshal([]byte(defaultValue), &value); err != nil {
+		return ""
+	}
+
+	switch value.(type) {
+	case string, int, float64, bool:
+		return fmt.Sprint(value)
+	default:
+		return ""
+	}
+}
+
+// Load reads completion data written as JSON.
+func Load(filename string) (*Data, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &Data{}
+	if err := json.Unmarshal(contents, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Complete returns the completions of an argument of the --set flag. Without
+// a "=" the paths starting with the word are returned, followed by "=".
+// After the "=" the allowed values (or true and false for booleans, or the
+// default) of the path are returned. Like helm, several values can be set in
+// one argument, separated by commas.
+func (d *Data) Complete(word string) []string {
+	var prefix string
+	if commas := unescaped(word, ','); len(commas) > 0 {
+		i := commas[len(commas)-1]
+		prefix, word = word[:i+1], word[i+1:]
+	}
+
+	var result []string
+	if equals := unescaped(word, '='); len(equals) > 0 {
+		i := equals[0]
+		path, current := word[:i], word[i+1:]
+		for _, value := range d.Values {
+			if value.Path != path {
+				continue
+			}
+
+			for _, candidate := range value.candidates() {
+				if strings.HasPrefix(candidate, current) {
+					result = append(result, prefix+path+"="+candidate)
+				}
+			}
+		}
+
+		return result
+	}
+
+	for _, value := range d.Values {
+		if strings.HasPrefix(value.Path, word) {
+			result = append(result, prefix+value.Path+"=")
+		}
+	}
+
+	return result
+}
+
+// candidates returns the values suggested for the value.
+func (v Value) candidates() []string {
+	switch {
+	case len(v.Enum) > 0:
+		return v.Enum
+	case v.Type == parser.TypeBool.String():
+		return []string{"true", "false"}
+	case v.Default != "":
+		return []string{v.Default}
+	default:
+		return nil
+	}
+}
+
+// unescaped returns the indices of the occurrences of the character that are
+// not escaped with a backslash.
+func unescaped(s string, c byte) []int {
+	var result []int
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case c:
+			result = append(result, i)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+const testValues = `image:
+  # The image pull policy
+  # +docs:enum=Always,IfNotPresent,Never
+  pullPolicy: IfNotPresent
+  # The image tag
+  tag: ""
+# The number of replicas
+replicas: 1
+# Create the CRDs
+installCRDs: false
+# Annotations of the pods
+podAnnotations:
+  # Inject the linkerd proxy
+  linkerd.io/inject: enabled
+# Extra arguments
+extraArgs: []
+`
+
+func TestNew(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(testValues), t.TempDir(), false)
+	require.NoError(t, err)
+
+	require.Equal(t, &Data{Values: []Value{
+		{Path: "image.pullPolicy", Type: "string", Enum: []string{"Always", "IfNotPresent", "Never"}, Default: "IfNotPresent"},
+		{Path: "image.tag", Type: "string"},
+		{Path: "replicas", Type: "number", Default: "1"},
+		{Path: "installCRDs", Type: "bool", Default: "false"},
+		{Path: `podAnnotations.linkerd\.io/inject`, Type: "string", Default: "enabled"},
+		{Path: "extraArgs", Type: "array"},
+	}}, New(document))
+}
+
+func TestComplete(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(testValues), t.TempDir(), false)
+	require.NoError(t, err)
+
+	// Completion data is read back from a file
+	output, err := json.Marshal(New(document))
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "completion.json")
+	require.NoError(t, os.WriteFile(filename, output, 0o644))
+	data, err := Load(filename)
+	require.NoError(t, err)
+
+	tests := []struct {
+		word     string
+		expected []string
+	}{
+		{word: "ima", expected: []string{"image.pullPolicy=", "image.tag="}},
+		{word: "image.pullPolicy=", expected: []string{"image.pullPolicy=Always", "image.pullPolicy=IfNotPresent", "image.pullPolicy=Never"}},
+		{word: "image.pullPolicy=I", expected: []string{"image.pullPolicy=IfNotPresent"}},
+		{word: "installCRDs=", expected: []string{"installCRDs=true", "installCRDs=false"}},
+		{word: "replicas=", expected: []string{"replicas=1"}},
+		{word: "image.tag=", expected: nil},
+		{word: "replicas=2,inst", expected: []string{"replicas=2,installCRDs="}},
+		{word: `podAnnotations.linkerd\.io/inject=`, expected: []string{`podAnnotations.linkerd\.io/inject=enabled`}},
+		{word: "missing", expected: nil},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, data.Complete(test.word), test.word)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the config file that is used when no config file is
+// explicitly provided, it is fine for this file to not exist.
+const DefaultPath = ".helm-tool.yaml"
+
+// Config contains the settings that can be provided using a config file.
+type Config struct {
+	// Values is the values file used to generate the documentation.
+	Values string `yaml:"values"`
+
+	// Template is the template used to render the documentation.
+	Template string `yaml:"template"`
+
+	// Audience is the audience the documentation is rendered for.
+	Audience string `yaml:"audience"`
+
+	// Output is the file the documentation is injected into.
+	Output string `yaml:"output"`
+
+	// TagPrefix is an alternative prefix for the tags in the values file
+	// comments, in addition to "+docs:".
+	TagPrefix string `yaml:"tagPrefix"`
+
+	// Dialect is the convention of the documentation comments in the values
+	// file, eg. "bitnami".
+	Dialect string `yaml:"dialect"`
+
+	// UnknownType is the type of the values whose type cannot be determined,
+	// eg. "object", instead of "unknown".
+	UnknownType string `yaml:"unknownType"`
+
+	// Owners is a CODEOWNERS-style file assigning owners to the values by
+	// path pattern.
+	Owners string `yaml:"owners"`
+
+	// LinkTypes enables rendering property types as links to their
+	// documentation.
+	LinkTypes *bool `yaml:"linkTypes"`
+
+	// SameAsAnchors renders the defaults of values that are a YAML alias as
+	// a link to the value defining the anchor.
+	SameAsAnchors *bool `yaml:"sameAsAnchors"`
+
+	// PropertyAnchors renders an HTML anchor before every property.
+	PropertyAnchors *bool `yaml:"propertyAnchors"`
+
+	// TypeLinks maps type names to the URL of their documentation, eg.
+	// cert-manager's API types to the cert-manager.io API reference. These
+	// extend the built-in Kubernetes type links.
+	TypeLinks map[string]string `yaml:"typeLinks"`
+
+	// FrontMatter contains the fields of the front matter written by the
+	// templates of documentation sites, eg. "sidebar_position: 3".
+	FrontMatter map[string]string `yaml:"frontMatter"`
+	// FrontMatterFormat is the format of the front matter, yaml or toml.
+	FrontMatterFormat string `yaml:"frontMatterFormat"`
+	// TableShortcode is the name of a Hugo shortcode the tables of the hugo
+	// template are wrapped in.
+	TableShortcode string `yaml:"tableShortcode"`
+	// Sort is the order of the properties within a section, file or
+	// alphabetical.
+	Sort string `yaml:"sort"`
+	// Columns are the columns rendered by the markdown-table template, eg.
+	// [description, default].
+	Columns []string `yaml:"columns"`
+	// Locale is the locale of the documentation, used to sort the
+	// properties and to format numbers.
+	Locale string `yaml:"locale"`
+	// BoolFormat are the names true and false defaults are displayed as,
+	// eg. "enabled,disabled".
+	BoolFormat string `yaml:"boolFormat"`
+	// PostRender are the commands the rendered documentation is passed
+	// through before it is written, eg. "prettier --write".
+	PostRender []string `yaml:"postRender"`
+
+	// Format contains the settings of the fmt command.
+	Format Format `yaml:"format"`
+
+	// Sync maps the paths of the defaults that are updated by the sync
+	// command to their source, eg. "image.tag: chart:appVersion".
+	Sync map[string]string `yaml:"sync"`
+
+	// Lint contains the settings of the lint command.
+	Lint Lint `yaml:"lint"`
+
+	// Profiles are named sets of settings that are applied over the other
+	// settings when selected using --profile, so multiple output pipelines
+	// can share a single config file.
+	Profiles map[string]Config `yaml:"profiles"`
+}
+
+// Lint contains the settings of the lint command.
+type Lint struct {
+	// Templates is the templates folder used to lint the values file.
+	Templates string `yaml:"templates"`
+	// Exceptions is the file containing exceptions to the linting rules.
+	Exceptions string `yaml:"exceptions"`
+	// Readme is the readme in which references to values are checked.
+	Readme string `yaml:"readme"`
+	// Spelling enables checking the spelling of the descriptions.
+	Spelling *bool `yaml:"spelling"`
+	// Dictionaries are the word lists used to check the spelling, eg. a list
+	// of project words.
+	Dictionaries []string `yaml:"dictionaries"`
+	// Policies are the commands evaluating policies against the
+	// documentation, eg. using opa eval.
+	Policies []string `yaml:"policies"`
+}
+
+// Format contains the settings of the fmt command.
+type Format struct {
+	// Wrap is the width at which comment lines are wrapped.
+	Wrap int `yaml:"wrap"`
+	// Structure enables normalizing the yaml, in addition to the comments.
+	Structure *bool `yaml:"structure"`
+	// QuoteStyle is the quote style used for quoted strings.
+	QuoteStyle string `yaml:"quoteStyle"`
+	// SortKeys are the paths of the subtrees in which keys are sorted.
+	SortKeys []string `yaml:"sortKeys"`
+}
+
+// Load reads the config file at path. If optional is true and the file does
+// not exist, an empty config is returned.
+func Load(path string, optional bool) (*Config, error) {
+	configBytes, err := os.ReadFile(path)
+	if optional && os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(configBytes))
+	decoder.KnownFields(true)
+	// An empty or comment-only config file has no documents, which is an
+	// empty config
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for name, profile := range config.Profiles {
+		if len(profile.Profiles) > 0 {
+			return nil, fmt.Errorf("profile %q: profiles cannot contain other profiles", name)
+		}
+	}
+
+	return &config, nil
+}
+
+// WithProfile returns the config with the settings of the named profile
+// applied. Settings that are set in the profile replace the top-level
+// settings, maps are merged.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for profileName := range c.Profiles {
+			names = append(names, profileName)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("unknown profile %q, available profiles: %s", name, strings.Join(names, ", "))
+	}
+
+	result := *c
+	result.Profiles = nil
+
+	setString(&result.Values, profile.Values)
+	setString(&result.Template, profile.Template)
+	setString(&result.Audience, profile.Audience)
+	setString(&result.Output, profile.Output)
+	setString(&result.TagPrefix, profile.TagPrefix)
+	setString(&result.UnknownType, profile.UnknownType)
+	setString(&result.Dialect, profile.Dialect)
+	setString(&result.Owners, profile.Owners)
+	setString(&result.FrontMatterFormat, profile.FrontMatterFormat)
+	setString(&result.TableShortcode, profile.TableShortcode)
+	setString(&result.Sort, profile.Sort)
+	setString(&result.Locale, profile.Locale)
+	setString(&result.BoolFormat, profile.BoolFormat)
+	if len(profile.Columns) > 0 {
+		result.Columns = profile.Columns
+	}
+	if len(profile.PostRender) > 0 {
+		result.PostRender = profile.PostRender
+	}
+	setBool(&result.LinkTypes, profile.LinkTypes)
+	setBool(&result.SameAsAnchors, profile.SameAsAnchors)
+	setBool(&result.PropertyAnchors, profile.PropertyAnchors)
+	result.TypeLinks = mergeMaps(result.TypeLinks, profile.TypeLinks)
+	result.FrontMatter = mergeMaps(result.FrontMatter, profile.FrontMatter)
+	result.Sync = mergeMaps(result.Sync, profile.Sync)
+
+	if profile.Format.Wrap != 0 {
+		result.Format.Wrap = profile.Format.Wrap
+	}
+	setBool(&result.Format.Structure, profile.Format.Structure)
+	setString(&result.Format.QuoteStyle, profile.Format.QuoteStyle)
+	if len(profile.Format.SortKeys) > 0 {
+		result.Format.SortKeys = profile.Format.SortKeys
+	}
+
+	setString(&result.Lint.Templates, profile.Lint.Templates)
+	setString(&result.Lint.Exceptions, profile.Lint.Exceptions)
+	setString(&result.Lint.Readme, profile.Lint.Readme)
+	setBool(&result.Lint.Spelling, profile.Lint.Spelling)
+	if len(profile.Lint.Dictionaries) > 0 {
+		result.Lint.Dictionaries = profile.Lint.Dictionaries
+	}
+	if len(profile.Lint.Policies) > 0 {
+		result.Lint.Policies = profile.Lint.Policies
+	}
+
+	return &result, nil
+}
+
+func setString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+func setBool(target **bool, value *bool) {
+	if value != nil {
+		*target = value
+	}
+}
+
+func mergeMaps(base, overlay map[string]string) map[string]string {
+	if len(overlay) == 0 {
+		return base
+	}
+
+	result := maps.Clone(base)
+	if result == nil {
+		result = map[string]string{}
+	}
+	maps.Copy(result, overlay)
+
+	return result
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`template: markdown-plain
+typeLinks:
+  A: https://example.com/a
+format:
+  wrap: 80
+  structure: true
+lint:
+  spelling: true
+profiles:
+  minimal:
+    format:
+      structure: false
+
+  website:
+    template: markdown-table
+    linkTypes: true
+    columns: [description, default]
+    sort: alphabetical
+    typeLinks:
+      B: https://example.com/b
+    frontMatter:
+      sidebar_position: "3"
+    lint:
+      readme: README.md
+`), 0644))
+
+	cfg, err := Load(path, false)
+	require.NoError(t, err)
+
+	yes := true
+	website, err := cfg.WithProfile("website")
+	require.NoError(t, err)
+	require.Equal(t, &Config{
+		Template:    "markdown-table",
+		LinkTypes:   &yes,
+		Columns:     []string{"description", "default"},
+		Sort:        "alphabetical",
+		TypeLinks:   map[string]string{"A": "https://example.com/a", "B": "https://example.com/b"},
+		FrontMatter: map[string]string{"sidebar_position": "3"},
+		Format:      Format{Wrap: 80, Structure: &yes},
+		Lint:        Lint{Readme: "README.md", Spelling: &yes},
+	}, website)
+
+	// A profile can disable a setting enabled at the top level, settings it
+	// does not set are kept
+	minimal, err := cfg.WithProfile("minimal")
+	require.NoError(t, err)
+	require.False(t, *minimal.Format.Structure)
+	require.True(t, *minimal.Lint.Spelling)
+	require.Nil(t, minimal.LinkTypes)
+
+	// The original config is not changed
+	require.Equal(t, "markdown-plain", cfg.Template)
+	require.Len(t, cfg.TypeLinks, 1)
+
+	_, err = cfg.WithProfile("missing")
+	require.ErrorContains(t, err, "available profiles: minimal, website")
+}
+
+func TestLoadEmpty(t *testing.T) {
+	for _, content := range []string{"", "# Only a comment\n"} {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		cfg, err := Load(path, false)
+		require.NoError(t, err)
+		require.Equal(t, &Config{}, cfg)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of the diff command.
+const (
+	FormatText      = "text"
+	FormatJSONPatch = "json-patch"
+	FormatJQ        = "jq"
+)
+
+// ChangeType is the kind of change to a property between two documents.
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeRenamed ChangeType = "renamed"
+	ChangeDefault ChangeType = "default"
+)
+
+// Change is a difference in a property between two versions of a values
+// file.
+type Change struct {
+	Type ChangeType
+	Path paths.Path
+	// OldPath is the previous path of a renamed property.
+	OldPath paths.Path
+
+	// OldDefault and NewDefault are the defaults as they are documented.
+	OldDefault string
+	NewDefault string
+	// OldValue and NewValue are the defaults as values, they are nil if the
+	// property has no value in the values file or if the documented default
+	// is set using a +docs:default tag.
+	OldValue *yaml.Node
+	NewValue *yaml.Node
+
+	// MissingParent is the highest parent of an added property that is not
+	// in the old values file, it is nil if the parent of the property is.
+	MissingParent paths.Path
+}
+
+// Documents returns the changes to the properties from the old to the new
+// document. Properties are considered renamed if the new property has a
+// +docs:alias tag with the old path.
+func Documents(old, new *parser.Document) []Change {
+	oldProperties := map[string]parser.Property{}
+	for _, section := range old.Sections {
+		for _, property := range section.Properties {
+			oldProperties[property.Path.String()] = property
+		}
+	}
+
+	newProperties := map[string]bool{}
+	for _, section := range new.Sections {
+		for _, property := range section.Properties {
+			newProperties[property.Path.String()] = true
+		}
+	}
+
+	var changes []Change
+	matched := map[string]bool{}
+	for _, section := range new.Sections {
+		for _, property := range section.Properties {
+			oldProperty, ok := oldProperties[property.Path.String()]
+			if !ok {
+				for _, alias := range property.Aliases() {
+					if _, found := oldProperties[alias]; found && !newProperties[alias] && !matched[alias] {
+						oldProperty, ok = oldProperties[alias], true
+						changes = append(changes, Change{Type: ChangeRenamed, Path: property.Path, OldPath: oldProperty.Path})
+						break
+					}
+				}
+			}
+
+			if !ok {
+				changes = append(changes, Change{
+					Type:          ChangeAdded,
+					Path:          property.Path,
+					NewDefault:    property.Default,
+					NewValue:      value(property),
+					MissingParent: missingParent(property.Path, oldProperties),
+				})
+				continue
+			}
+
+			matched[oldProperty.Path.String()] = true
+			if oldProperty.Default != property.Default {
+				changes = append(changes, Change{
+					Type:       ChangeDefault,
+					Path:       property.Path,
+					OldDefault: oldProperty.Default,
+					NewDefault: property.Default,
+					OldValue:   value(oldProperty),
+					NewValue:   value(property),
+				})
+			}
+		}
+	}
+
+	for _, section := range old.Sections {
+		for _, property := range section.Properties {
+			if !matched[property.Path.String()] {
+				changes = append(changes, Change{
+					Type:       ChangeRemoved,
+					Path:       property.Path,
+					OldDefault: property.Default,
+					OldValue:   value(property),
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+// missingParent returns the highest parent of path that has no properties in
+// the old document, or nil if the parent of path has.
+func missingParent(path paths.Path, oldProperties map[string]parser.Property) paths.Path {
+	for length := 1; length < len(path); length++ {
+		parent := path[:length]
+
+		found := false
+		for _, property := range oldProperties {
+			if parent.IsSubPathOf(property.Path) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return append(paths.Path{}, parent...)
+		}
+	}
+
+	return nil
+}
+
+// value returns the default of the property as a yaml node, or nil if the
+// default is not a value.
+func value(property parser.Property) *yaml.Node {
+	if property.Line == 0 || property.Description.Tags.GetString(parser.TagDefault) != "" {
+		return nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(property.Default), &node); err != nil || len(node.Content) == 0 {
+		return nil
+	}
+
+	return node.Content[0]
+}
+
+// WriteText writes the changes in a human readable form, one per line.
+func WriteText(w io.Writer, changes []Change) {
+	for _, change := range changes {
+		switch change.Type {
+		case ChangeAdded:
+			fmt.Fprintf(w, "added    %s (default %s)\n", change.Path, singleLine(change.NewDefault))
+		case ChangeRemoved:
+			fmt.Fprintf(w, "removed  %s\n", change.Path)
+		case ChangeRenamed:
+			fmt.Fprintf(w, "renamed  %s to %s\n", change.OldPath, change.Path)
+		case ChangeDefault:
+			fmt.Fprintf(w, "default  %s changed from %s to %s\n", change.Path, singleLine(change.OldDefault), singleLine(change.NewDefault))
+		}
+	}
+}
+
+func singleLine(text string) string {
+	if text == "" {
+		return `""`
+	}
+
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+const oldValues = `# Number of replicas
+replicaCount: 1
+
+# Timeout of the webhook
+timeoutSeconds: 10
+
+# Removed value
+legacy: true
+
+# Image tag
+# +docs:default=The chart appVersion
+tag: ""
+`
+
+const newValues = `# Number of replicas
+replicaCount: 2
+
+# Timeout of the webhook
+# +docs:alias=timeoutSeconds
+timeout: 10
+
+# Image tag
+# +docs:default=The chart version
+tag: ""
+
+# New value
+labels:
+  app: example
+`
+
+func parse(t *testing.T, values string) *parser.Document {
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), true)
+	require.NoError(t, err)
+	return document
+}
+
+func TestDocuments(t *testing.T) {
+	changes := Documents(parse(t, oldValues), parse(t, newValues))
+
+	var text strings.Builder
+	WriteText(&text, changes)
+	require.Equal(t, `default  replicaCount changed from 1 to 2
+renamed  timeoutSeconds to timeout
+default  tag changed from The chart appVersion to The chart version
+added    labels.app (default example)
+removed  legacy
+`, text.String())
+}
+
+func TestJSONPatch(t *testing.T) {
+	operations, err := JSONPatch(Documents(parse(t, oldValues), parse(t, newValues)))
+	require.NoError(t, err)
+
+	patch, err := json.Marshal(operations)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"op": "replace", "path": "/replicaCount", "value": 2},
+		{"op": "move", "from": "/timeoutSeconds", "path": "/timeout"},
+		{"op": "add", "path": "/labels", "value": {"app": "example"}},
+		{"op": "remove", "path": "/legacy"}
+	]`, string(patch))
+}
+
+func TestJSONPatchMissingParent(t *testing.T) {
+	old := `# Enable the webhook
+webhook:
+  enabled: true
+`
+	new := `# Enable the webhook
+webhook:
+  enabled: true
+  # Port of the webhook
+  port: 10250
+
+# Enable the cainjector
+cainjector:
+  enabled: true
+  # Settings of the cainjector config
+  config:
+    # Leader election
+    leaderElection: true
+    # Extra arguments
+    extraArgs:
+      - --v=2
+`
+	operations, err := JSONPatch(Documents(parse(t, old), parse(t, new)))
+	require.NoError(t, err)
+
+	// The parent of webhook.port exists, cainjector is added as a whole
+	patch, err := json.Marshal(operations)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"op": "add", "path": "/webhook/port", "value": 10250},
+		{"op": "add", "path": "/cainjector", "value": {"enabled": true, "config": {"leaderElection": true, "extraArgs": ["--v=2"]}}}
+	]`, string(patch))
+}
+
+func TestJQScript(t *testing.T) {
+	script, err := JQScript(Documents(parse(t, oldValues), parse(t, newValues)))
+	require.NoError(t, err)
+	require.Equal(t, `if .replicaCount == 1 then .replicaCount = 2 else . end |
+if .timeoutSeconds != null then .timeout = .timeoutSeconds | del(.timeoutSeconds) else . end |
+del(.legacy)
+`, script)
+
+	script, err = JQScript(nil)
+	require.NoError(t, err)
+	require.Equal(t, ".\n", script)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// Operation is an operation of an RFC 6902 JSON Patch.
+type Operation struct {
+	Op    string          `json:"op"`
+	From  string          `json:"from,omitempty"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch returns an RFC 6902 JSON Patch that updates the old defaults to
+// the new defaults. Defaults that are not values (see Change) are left out.
+// Added properties whose parent is missing in the old values file are added
+// using a single operation that adds the whole value of the missing parent,
+// as an add operation requires the parent to exist.
+func JSONPatch(changes []Change) ([]Operation, error) {
+	operations := []Operation{}
+
+	// parents maps the missing parents to the index of their operation, the
+	// values of the operations are encoded once all properties are known
+	parents := map[string]int{}
+	parentValues := map[int]any{}
+	for _, change := range changes {
+		switch change.Type {
+		case ChangeAdded:
+			if change.NewValue == nil {
+				continue
+			}
+
+			if change.MissingParent != nil {
+				var value any
+				if err := change.NewValue.Decode(&value); err != nil {
+					return nil, fmt.Errorf("default of %q: %w", change.Path, err)
+				}
+
+				pointer := change.MissingParent.JSONPointer()
+				i, ok := parents[pointer]
+				if !ok {
+					i = len(operations)
+					parents[pointer] = i
+					operations = append(operations, Operation{Op: "add", Path: pointer})
+				}
+
+				parentValues[i] = setValue(parentValues[i], change.Path[len(change.MissingParent):], value)
+				continue
+			}
+
+			value, err := toJSON(change.NewValue)
+			if err != nil {
+				return nil, fmt.Errorf("default of %q: %w", change.Path, err)
+			}
+			operations = append(operations, Operation{Op: "add", Path: change.Path.JSONPointer(), Value: value})
+		case ChangeRemoved:
+			operations = append(operations, Operation{Op: "remove", Path: change.Path.JSONPointer()})
+		case ChangeRenamed:
+			operations = append(operations, Operation{Op: "move", From: change.OldPath.JSONPointer(), Path: change.Path.JSONPointer()})
+		case ChangeDefault:
+			if change.OldValue == nil || change.NewValue == nil {
+				continue
+			}
+
+			value, err := toJSON(change.NewValue)
+			if err != nil {
+				return nil, fmt.Errorf("default of %q: %w", change.Path, err)
+			}
+			operations = append(operations, Operation{Op: "replace", Path: change.Path.JSONPointer(), Value: value})
+		}
+	}
+
+	for i, value := range parentValues {
+		encoded, err := encodeJSON(value)
+		if err != nil {
+			return nil, fmt.Errorf("default of %q: %w", operations[i].Path, err)
+		}
+		operations[i].Value = encoded
+	}
+
+	return operations, nil
+}
+
+// setValue sets the value at path in tree, which is built from maps and
+// slices like decoded json, and returns the updated tree.
+func setValue(tree any, path paths.Path, value any) any {
+	if len(path) == 0 {
+		// A documented object can also have documented properties
+		existing, ok := tree.(map[string]any)
+		if values, isMap := value.(map[string]any); ok && isMap {
+			for key, v := range values {
+				if _, found := existing[key]; !found {
+					existing[key] = v
+				}
+			}
+			return existing
+		}
+
+		return value
+	}
+
+	segment := paths.SegmentString(path[0])
+	if paths.IsArrayPathComponent(path[0]) {
+		index, _ := strconv.Atoi(strings.Trim(segment, "[]"))
+		list, _ := tree.([]any)
+		for len(list) <= index {
+			list = append(list, nil)
+		}
+		list[index] = setValue(list[index], path[1:], value)
+		return list
+	}
+
+	values, _ := tree.(map[string]any)
+	if values == nil {
+		values = map[string]any{}
+	}
+	values[segment] = setValue(values[segment], path[1:], value)
+	return values
+}
+
+// JQScript returns a jq expression (that can also be used with yq) that
+// updates a user's values file: renamed values are moved, removed values are
+// deleted and values that are set to the old default are set to the new
+// default. Values that are not in the file are not added.
+func JQScript(changes []Change) (string, error) {
+	var steps []string
+	for _, change := range changes {
+		path := change.Path.JQString()
+		switch change.Type {
+		case ChangeRemoved:
+			steps = append(steps, fmt.Sprintf("del(%s)", path))
+		case ChangeRenamed:
+			oldPath := change.OldPath.JQString()
+			steps = append(steps, fmt.Sprintf("if %s != null then %s = %s | del(%s) else . end", oldPath, path, oldPath, oldPath))
+		case ChangeDefault:
+			if change.OldValue == nil || change.NewValue == nil {
+				continue
+			}
+
+			oldValue, err := toJSON(change.OldValue)
+			if err != nil {
+				return "", fmt.Errorf("default of %q: %w", change.Path, err)
+			}
+			newValue, err := toJSON(change.NewValue)
+			if err != nil {
+				return "", fmt.Errorf("default of %q: %w", change.Path, err)
+			}
+			steps = append(steps, fmt.Sprintf("if %s == %s then %s = %s else . end", path, oldValue, path, newValue))
+		}
+	}
+
+	if len(steps) == 0 {
+		return ".\n", nil
+	}
+
+	return strings.Join(steps, " |\n") + "\n", nil
+}
+
+// toJSON encodes a yaml node as compact JSON.
+func toJSON(node *yaml.Node) (json.RawMessage, error) {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return encodeJSON(value)
+}
+
+// encodeJSON encodes a value as compact JSON.
+func encodeJSON(value any) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
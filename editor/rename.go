@@ -0,0 +1,411 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	identifierExp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	plainKeyExp   = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_./-]*$`)
+)
+
+// Rename moves the value at from to the path to, and records the old path
+// using a +docs:alias tag. When both paths share the same parent only the key
+// is changed in place, otherwise the lines of the value (and its comment) are
+// moved to the end of the mapping at the parent of to, which is created if it
+// does not exist. The rest of the file is left untouched.
+func Rename(content []byte, from, to paths.Path) ([]byte, error) {
+	if err := checkRename(from, to); err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	if Find(&root, to) != nil {
+		return nil, fmt.Errorf("value %q already exists", to)
+	}
+
+	key, value := findKey(&root, from)
+	if key == nil {
+		return nil, fmt.Errorf("value %q not found", from)
+	}
+
+	var result []byte
+	var err error
+	if from.Parent().Equal(to.Parent()) {
+		result, err = renameInPlace(content, key, from, to)
+	} else {
+		result, err = move(content, &root, key, value, from, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the value ended up at its new path unchanged
+	var original, updated yaml.Node
+	if err := yaml.Unmarshal(content, &original); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(result, &updated); err != nil {
+		return nil, fmt.Errorf("renaming %q produced invalid yaml: %w", from, err)
+	}
+	if Find(&updated, from) != nil || !equalNodes(Find(&original, from), Find(&updated, to)) {
+		return nil, fmt.Errorf("could not rename %q to %q", from, to)
+	}
+
+	return result, nil
+}
+
+func checkRename(from, to paths.Path) error {
+	if len(from) == 0 || len(to) == 0 {
+		return fmt.Errorf("cannot rename the root of the values file")
+	}
+
+	if paths.IsArrayPathComponent(from.Property()) || paths.IsArrayPathComponent(to.Property()) {
+		return fmt.Errorf("cannot rename array items")
+	}
+
+	if to.IsSubPathOf(from) || from.IsSubPathOf(to) {
+		return fmt.Errorf("cannot rename %q to %q, one is a sub path of the other", from, to)
+	}
+
+	return nil
+}
+
+// findKey returns the key and value nodes of the mapping entry at path.
+func findKey(root *yaml.Node, path paths.Path) (*yaml.Node, *yaml.Node) {
+	parent := Find(root, path.Parent())
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if path.Parent().WithProperty(parent.Content[i].Value).Equal(path) {
+			return parent.Content[i], parent.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+// renameInPlace replaces the bytes of the key and inserts the alias tag on
+// the line above it.
+func renameInPlace(content []byte, key *yaml.Node, from, to paths.Path) ([]byte, error) {
+	if key.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return nil, fmt.Errorf("key of %q cannot be renamed in place", from)
+	}
+
+	start, err := offsetOf(content, key.Line, key.Column)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := endOfKey(content, start, key)
+	if err != nil {
+		return nil, fmt.Errorf("key of %q: %w", from, err)
+	}
+
+	lineStart := bytes.LastIndexByte(content[:start], '\n') + 1
+
+	var result bytes.Buffer
+	result.Write(content[:lineStart])
+	result.WriteString(strings.Repeat(" ", key.Column-1))
+	result.WriteString(aliasComment(from))
+	result.WriteString("\n")
+	result.Write(content[lineStart:start])
+	result.WriteString(encodeKey(key, propertyName(to)))
+	result.Write(content[end:])
+
+	return result.Bytes(), nil
+}
+
+// endOfKey returns the offset of the end of the key starting at start.
+func endOfKey(content []byte, start int, key *yaml.Node) (int, error) {
+	switch key.Style {
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		return endOfScalar(content, start, key.Style)
+	default:
+		return start + len(key.Value), nil
+	}
+}
+
+// move cuts the lines of the entry out of its parent mapping, and inserts them
+// re-indented after the last entry of the mapping at the parent of to. Missing
+// mappings are inserted as well, indented by two spaces.
+func move(content []byte, root *yaml.Node, key, value *yaml.Node, from, to paths.Path) ([]byte, error) {
+	if key.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return nil, fmt.Errorf("key of %q cannot be moved", from)
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := key.Column - 1
+	if strings.TrimLeft(lines[key.Line-1][:indent], " ") != "" {
+		return nil, fmt.Errorf("cannot move %q, it is on the same line as its parent", from)
+	}
+
+	start, end := entryLines(lines, key, value)
+
+	// A mapping that becomes empty is kept as an empty map instead of null
+	if parentKey, parent := findKey(root, from.Parent()); parentKey != nil && len(parent.Content) == 2 && parentKey.Line != key.Line {
+		line := lines[parentKey.Line-1]
+		keyEnd, err := endOfKey([]byte(line), parentKey.Column-1, parentKey)
+		if err != nil {
+			return nil, fmt.Errorf("key of %q: %w", from.Parent(), err)
+		}
+
+		if colon := strings.IndexByte(line[keyEnd:], ':'); colon != -1 {
+			colon += keyEnd
+			lines[parentKey.Line-1] = line[:colon+1] + " {}" + line[colon+1:]
+		}
+	}
+
+	// Find the mapping the entry is moved into, and the missing mappings
+	// between it and the parent of to
+	existing := to.Parent()
+	for len(existing) > 0 && Find(root, existing) == nil {
+		existing = existing.Parent()
+	}
+	missing := to.Parent()[len(existing):]
+	for _, component := range missing {
+		if paths.IsArrayPathComponent(component) {
+			return nil, fmt.Errorf("array %q does not exist", to.Parent())
+		}
+	}
+
+	target := Find(root, existing)
+	if target == nil || target.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("value %q is not a map", existing)
+	}
+	if target.Style&yaml.FlowStyle != 0 || len(target.Content) == 0 {
+		return nil, fmt.Errorf("cannot move %q into the flow style map %q", from, existing)
+	}
+
+	_, insertAfter := entryLines(lines, target.Content[len(target.Content)-2], target.Content[len(target.Content)-1])
+	targetIndent := target.Content[0].Column - 1
+
+	var block []string
+	for i := range missing {
+		name := encodeKey(&yaml.Node{}, propertyName(to.Parent()[:len(existing)+i+1]))
+		block = append(block, strings.Repeat(" ", targetIndent+2*i)+name+":\n")
+	}
+	targetIndent += 2 * len(missing)
+
+	for i := start; i <= end; i++ {
+		line := lines[i]
+		if i == key.Line-1 {
+			keyEnd, err := endOfKey([]byte(line), indent, key)
+			if err != nil {
+				return nil, fmt.Errorf("key of %q: %w", from, err)
+			}
+
+			block = append(block, reindent(strings.Repeat(" ", indent)+aliasComment(from)+"\n", indent, targetIndent))
+			line = line[:indent] + encodeKey(key, propertyName(to)) + line[keyEnd:]
+		}
+
+		block = append(block, reindent(line, indent, targetIndent))
+	}
+	if last := block[len(block)-1]; !strings.HasSuffix(last, "\n") {
+		block[len(block)-1] = last + "\n"
+	}
+
+	var result strings.Builder
+	for i, line := range lines {
+		if i < start || i > end {
+			result.WriteString(line)
+			if i == insertAfter && !strings.HasSuffix(line, "\n") {
+				result.WriteString("\n")
+			}
+		}
+
+		if i == insertAfter {
+			result.WriteString(strings.Join(block, ""))
+		}
+	}
+
+	return []byte(result.String()), nil
+}
+
+// entryLines returns the first and last line index of a mapping entry: the
+// comment lines directly above the key, the key and the lines of the value
+// (the following lines that are indented further, blank lines in between
+// included).
+func entryLines(lines []string, key, value *yaml.Node) (int, int) {
+	indent := key.Column - 1
+
+	start := key.Line - 1
+	for start > 0 && strings.HasPrefix(lines[start-1], strings.Repeat(" ", indent)+"#") {
+		start--
+	}
+
+	end := key.Line - 1
+	for i := end + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		lineIndent := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+
+		switch {
+		case trimmed == "":
+			continue
+		case lineIndent > indent:
+		// Sequences can have the same indentation as their key
+		case value.Kind == yaml.SequenceNode && lineIndent == indent && strings.HasPrefix(trimmed, "-"):
+		default:
+			return start, end
+		}
+
+		end = i
+	}
+
+	return start, end
+}
+
+// reindent changes the indentation of a line from indent to targetIndent,
+// blank lines are kept as-is.
+func reindent(line string, indent, targetIndent int) string {
+	if strings.TrimSpace(line) == "" {
+		return line
+	}
+
+	if targetIndent >= indent {
+		return strings.Repeat(" ", targetIndent-indent) + line
+	}
+
+	remove := indent - targetIndent
+	if spaces := len(line) - len(strings.TrimLeft(line, " ")); spaces < remove {
+		remove = spaces
+	}
+
+	return line[remove:]
+}
+
+// RenameTemplateReferences rewrites the .Values references to from (or any
+// of its children) in the templates folder to to. The updated contents of the
+// files that changed are returned, mapped by their path.
+func RenameTemplateReferences(templatesFolder string, from, to paths.Path) (map[string][]byte, error) {
+	fromReference, err := valuesReference(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toReference, err := valuesReference(to)
+	if err != nil {
+		return nil, err
+	}
+	toReference = strings.ReplaceAll(toReference, `\`, "")
+	fromExp := regexp.MustCompile(fromReference + `\b`)
+
+	changed := map[string][]byte{}
+	err = filepath.Walk(templatesFolder, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if updated := fromExp.ReplaceAllLiteral(contents, []byte(toReference)); !bytes.Equal(contents, updated) {
+			changed[path] = updated
+		}
+
+		return nil
+	})
+
+	return changed, err
+}
+
+// valuesReference returns a regular expression matching the .Values
+// reference of the path, only paths consisting of identifiers can be
+// referenced this way.
+func valuesReference(path paths.Path) (string, error) {
+	reference := `\.Values`
+	for _, component := range path {
+		name := paths.SegmentString(component)
+		if paths.IsArrayPathComponent(component) || !identifierExp.MatchString(name) {
+			return "", fmt.Errorf("cannot update template references to %q", path)
+		}
+		reference += `\.` + name
+	}
+
+	return reference, nil
+}
+
+func aliasComment(from paths.Path) string {
+	return "# +docs:alias=" + from.String()
+}
+
+// propertyName returns the key of the last component of the path.
+func propertyName(path paths.Path) string {
+	name := paths.SegmentString(path.Property())
+	if strings.HasPrefix(name, "[") {
+		if unquoted, err := strconv.Unquote(name[1 : len(name)-1]); err == nil {
+			return unquoted
+		}
+	}
+
+	return name
+}
+
+func encodeKey(key *yaml.Node, name string) string {
+	switch key.Style {
+	case yaml.DoubleQuotedStyle:
+		return doubleQuote(name)
+	case yaml.SingleQuotedStyle:
+		return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+	}
+
+	if plainKeyExp.MatchString(name) {
+		return name
+	}
+
+	return doubleQuote(name)
+}
+
+func equalNodes(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	var aValue, bValue any
+	if err := a.Decode(&aValue); err != nil {
+		return false
+	}
+	if err := b.Decode(&bValue); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(aValue, bValue)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRename(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		from     string
+		to       string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "SameParent",
+			input:    "image:\n  # The tag\n  tag: v1 # comment\n  pullPolicy: Always\n",
+			from:     "image.tag",
+			to:       "image.version",
+			expected: "image:\n  # The tag\n  # +docs:alias=image.tag\n  version: v1 # comment\n  pullPolicy: Always\n",
+		},
+		{
+			name:     "QuotedKey",
+			input:    "\"tag\": v1\n",
+			from:     "tag",
+			to:       "version",
+			expected: "# +docs:alias=tag\n\"version\": v1\n",
+		},
+		{
+			name:     "Mapping",
+			input:    "# The webhook\nwebhook:\n  replicas: 1\n",
+			from:     "webhook",
+			to:       "admission",
+			expected: "# The webhook\n# +docs:alias=webhook\nadmission:\n  replicas: 1\n",
+		},
+		{
+			name:     "DifferentParent",
+			input:    "# The replicas\nreplicas: 1\nwebhook:\n    enabled: true\n",
+			from:     "replicas",
+			to:       "webhook.replicas",
+			expected: "webhook:\n    enabled: true\n    # The replicas\n    # +docs:alias=replicas\n    replicas: 1\n",
+		},
+		{
+			name:     "KeepsFormatting",
+			input:    "# +docs:section=General\n\nimage:\n  # The tag\n  tag: \"v1\"   # pinned\n\n  pullPolicy: Always\n\nwebhook:\n  args: [--v=2]\n  # The webhook config\n  config:\n    apiVersion: v1\n    items:\n    - a\n\n    - b\n\n# Trailing comment\n",
+			from:     "webhook.config",
+			to:       "image.config",
+			expected: "# +docs:section=General\n\nimage:\n  # The tag\n  tag: \"v1\"   # pinned\n\n  pullPolicy: Always\n  # The webhook config\n  # +docs:alias=webhook.config\n  config:\n    apiVersion: v1\n    items:\n    - a\n\n    - b\n\nwebhook:\n  args: [--v=2]\n\n# Trailing comment\n",
+		},
+		{
+			name:     "Outdent",
+			input:    "webhook:\n  config:\n    text: |\n      line 1\n        line 2\nreplicas: 1",
+			from:     "webhook.config",
+			to:       "config",
+			expected: "webhook: {}\nreplicas: 1\n# +docs:alias=webhook.config\nconfig:\n  text: |\n    line 1\n      line 2\n",
+		},
+		{
+			name:     "NewNestedParent",
+			input:    "tag: v1\nimage: {}\n",
+			from:     "tag",
+			to:       "images.app.tag",
+			expected: "image: {}\nimages:\n  app:\n    # +docs:alias=tag\n    tag: v1\n",
+		},
+		{
+			name:    "FlowParent",
+			input:   "tag: v1\nimage: {}\n",
+			from:    "tag",
+			to:      "image.tag",
+			wantErr: true,
+		},
+		{
+			name:     "NewParent",
+			input:    "tag: v1\n",
+			from:     "tag",
+			to:       "image.tag",
+			expected: "image:\n  # +docs:alias=tag\n  tag: v1\n",
+		},
+		{
+			name:    "Missing",
+			input:   "tag: v1\n",
+			from:    "image.tag",
+			to:      "image.version",
+			wantErr: true,
+		},
+		{
+			name:    "Exists",
+			input:   "tag: v1\nversion: v2\n",
+			from:    "tag",
+			to:      "version",
+			wantErr: true,
+		},
+		{
+			name:    "SubPath",
+			input:   "image:\n  tag: v1\n",
+			from:    "image",
+			to:      "image.config",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			from, err := paths.Parse(test.from)
+			require.NoError(t, err)
+			to, err := paths.Parse(test.to)
+			require.NoError(t, err)
+
+			result, err := Rename([]byte(test.input), from, to)
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(result))
+		})
+	}
+}
+
+func TestRenameTemplateReferences(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "deployment.yaml")
+	other := filepath.Join(dir, "service.yaml")
+
+	require.NoError(t, os.WriteFile(template, []byte(`image: {{ .Values.image.tag }}
+{{- with $.Values.image.tagSuffix }}{{ . }}{{ end }}
+{{- toYaml .Values.image.tag.extra }}
+`), 0644))
+	require.NoError(t, os.WriteFile(other, []byte("port: {{ .Values.port }}\n"), 0644))
+
+	changed, err := RenameTemplateReferences(dir, paths.Path{}.WithProperty("image").WithProperty("tag"), paths.Path{}.WithProperty("image").WithProperty("version"))
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		template: []byte(`image: {{ .Values.image.version }}
+{{- with $.Values.image.tagSuffix }}{{ . }}{{ end }}
+{{- toYaml .Values.image.version.extra }}
+`),
+	}, changed)
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// SetScalar replaces the value of the scalar at path in the yaml content.
+// Only the bytes of the value itself are changed, so comments, formatting
+// and the quote style of the value are preserved.
+func SetScalar(content []byte, path paths.Path, value string) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	node := Find(&root, path)
+	if node == nil {
+		return nil, fmt.Errorf("value %q not found", path)
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("value %q is not a scalar", path)
+	}
+
+	if node.Anchor != "" || node.Style == yaml.LiteralStyle || node.Style == yaml.FoldedStyle {
+		return nil, fmt.Errorf("value %q cannot be updated in place", path)
+	}
+
+	encoded, tag := encodeScalar(node, value)
+
+	var start, end int
+	if node.Value == "" && node.Style == 0 && node.ShortTag() == "!!null" {
+		// An empty value has no token of its own, the value is inserted
+		// after the colon of its key instead
+		key := keyOf(&root, path)
+		if key == nil {
+			return nil, fmt.Errorf("value %q cannot be updated in place", path)
+		}
+
+		colon, err := colonAfterKey(content, key)
+		if err != nil {
+			return nil, fmt.Errorf("value %q: %w", path, err)
+		}
+
+		start, end = colon+1, colon+1
+		encoded = " " + encoded
+	} else {
+		var err error
+		start, err = offsetOf(content, node.Line, node.Column)
+		if err != nil {
+			return nil, err
+		}
+
+		end, err = endOfScalar(content, start, node.Style)
+		if err != nil {
+			return nil, fmt.Errorf("value %q: %w", path, err)
+		}
+	}
+
+	var result bytes.Buffer
+	result.Write(content[:start])
+	result.WriteString(encoded)
+	result.Write(content[end:])
+
+	// Make sure we updated the right value
+	var updated yaml.Node
+	if err := yaml.Unmarshal(result.Bytes(), &updated); err != nil {
+		return nil, fmt.Errorf("updating %q produced invalid yaml: %w", path, err)
+	}
+	if n := Find(&updated, path); n == nil || n.Value != value || n.ShortTag() != tag {
+		return nil, fmt.Errorf("could not update %q", path)
+	}
+
+	return result.Bytes(), nil
+}
+
+// Find returns the yaml node at path, or nil if there is no such node.
+func Find(root *yaml.Node, path paths.Path) *yaml.Node {
+	var found *yaml.Node
+	walk(root, paths.Path{}, func(p paths.Path, node *yaml.Node) bool {
+		if found != nil || !p.IsSubPathOf(path) {
+			return false
+		}
+
+		if p.Equal(path) {
+			found = node
+			return false
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// keyOf returns the key node of the mapping entry at path, or nil if the
+// value at path is not in a mapping.
+func keyOf(root *yaml.Node, path paths.Path) *yaml.Node {
+	if len(path) == 0 {
+		return nil
+	}
+
+	parent := Find(root, path.Parent())
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if path.Parent().WithProperty(parent.Content[i].Value).Equal(path) {
+			return parent.Content[i]
+		}
+	}
+
+	return nil
+}
+
+// colonAfterKey returns the offset of the colon separating the key from its
+// value.
+func colonAfterKey(content []byte, key *yaml.Node) (int, error) {
+	start, err := offsetOf(content, key.Line, key.Column)
+	if err != nil {
+		return 0, err
+	}
+
+	end := start
+	if key.Style == yaml.DoubleQuotedStyle || key.Style == yaml.SingleQuotedStyle {
+		if end, err = endOfScalar(content, start, key.Style); err != nil {
+			return 0, err
+		}
+	}
+
+	for i := end; i < len(content) && content[i] != '\n'; i++ {
+		if content[i] == ':' && (i+1 == len(content) || strings.ContainsRune(" \t\r\n", rune(content[i+1]))) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("could not find the value of key %q", key.Value)
+}
+
+// walk calls fn for every value node, fn returns whether the children of the
+// node should be walked.
+func walk(node *yaml.Node, path paths.Path, fn func(path paths.Path, node *yaml.Node) bool) {
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			walk(child, path, fn)
+		}
+		return
+	}
+
+	if !fn(path, node) {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			walk(child, path.WithIndex(i), fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walk(node.Content[i+1], path.WithProperty(node.Content[i].Value), fn)
+		}
+	}
+}
+
+// offsetOf converts a 1-based line and column (in characters) to a byte
+// offset.
+func offsetOf(content []byte, line, column int) (int, error) {
+	offset := 0
+	for l := 1; l < line; l++ {
+		idx := bytes.IndexByte(content[offset:], '\n')
+		if idx == -1 {
+			return 0, fmt.Errorf("line %d out of range", line)
+		}
+		offset += idx + 1
+	}
+
+	for c := 1; c < column; c++ {
+		if offset >= len(content) {
+			return 0, fmt.Errorf("column %d out of range", column)
+		}
+		_, size := utf8.DecodeRune(content[offset:])
+		offset += size
+	}
+
+	return offset, nil
+}
+
+// endOfScalar returns the offset just after the scalar token that starts at
+// start.
+func endOfScalar(content []byte, start int, style yaml.Style) (int, error) {
+	switch style {
+	case yaml.DoubleQuotedStyle:
+		for i := start + 1; i < len(content); i++ {
+			switch content[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1, nil
+			}
+		}
+		return 0, fmt.Errorf("unterminated double quoted string")
+	case yaml.SingleQuotedStyle:
+		for i := start + 1; i < len(content); i++ {
+			if content[i] != '\'' {
+				continue
+			}
+			if i+1 < len(content) && content[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+		return 0, fmt.Errorf("unterminated single quoted string")
+	default:
+		end := len(content)
+		if idx := bytes.IndexByte(content[start:], '\n'); idx != -1 {
+			end = start + idx
+		}
+
+		token := string(content[start:end])
+		if idx := strings.Index(token, " #"); idx != -1 {
+			token = token[:idx]
+		}
+
+		return start + len(strings.TrimRight(token, " \t\r")), nil
+	}
+}
+
+// encodeScalar encodes the value in the style of the existing node and
+// returns it with the tag it is read back as. Plain strings and empty values
+// are quoted if the new value would otherwise not be a string.
+func encodeScalar(node *yaml.Node, value string) (string, string) {
+	switch node.Style {
+	case yaml.DoubleQuotedStyle:
+		return doubleQuote(value), "!!str"
+	case yaml.SingleQuotedStyle:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'", "!!str"
+	}
+
+	var parsed yaml.Node
+	if err := yaml.Unmarshal([]byte("v: "+value), &parsed); err != nil || strings.ContainsAny(value, "\n#") {
+		return doubleQuote(value), "!!str"
+	}
+
+	v := Find(&parsed, paths.Path{}.WithProperty("v"))
+	if v == nil || v.Value != value {
+		return doubleQuote(value), "!!str"
+	}
+
+	if tag := node.ShortTag(); tag != "!!str" && tag != "!!null" {
+		return value, v.ShortTag()
+	}
+
+	if v.ShortTag() != "!!str" {
+		return doubleQuote(value), "!!str"
+	}
+
+	return value, "!!str"
+}
+
+func doubleQuote(value string) string {
+	// JSON strings are valid yaml double quoted strings
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package editor
+
+import (
+	"testing"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetScalar(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		path     string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Plain",
+			input:    "# The image\nimage:\n  # The tag\n  tag: v1.0.0 # comment\n",
+			path:     "image.tag",
+			value:    "v1.1.0",
+			expected: "# The image\nimage:\n  # The tag\n  tag: v1.1.0 # comment\n",
+		},
+		{
+			name:     "DoubleQuoted",
+			input:    "tag: \"v1\"\nother: 1\n",
+			path:     "tag",
+			value:    "sha256:abc",
+			expected: "tag: \"sha256:abc\"\nother: 1\n",
+		},
+		{
+			name:     "SingleQuoted",
+			input:    "tag: 'v1'\n",
+			path:     "tag",
+			value:    "it's",
+			expected: "tag: 'it''s'\n",
+		},
+		{
+			name:     "PlainStringNeedingQuotes",
+			input:    "tag: v1\n",
+			path:     "tag",
+			value:    "true",
+			expected: "tag: \"true\"\n",
+		},
+		{
+			name:     "Number",
+			input:    "replicas: 1\n",
+			path:     "replicas",
+			value:    "3",
+			expected: "replicas: 3\n",
+		},
+		{
+			name:     "Sequence",
+			input:    "args:\n  - --a\n  - --b\n",
+			path:     "args[1]",
+			value:    "--c",
+			expected: "args:\n  - --a\n  - --c\n",
+		},
+		{
+			name:     "Empty",
+			input:    "image:\n  tag:\n  pullPolicy: Always\n",
+			path:     "image.tag",
+			value:    "v1.0.0",
+			expected: "image:\n  tag: v1.0.0\n  pullPolicy: Always\n",
+		},
+		{
+			name:     "EmptyWithComment",
+			input:    "image:\n  \"tag\": # The tag\n",
+			path:     "image.tag",
+			value:    "v1.0.0",
+			expected: "image:\n  \"tag\": v1.0.0 # The tag\n",
+		},
+		{
+			name:     "Tilde",
+			input:    "image:\n  tag: ~\n  pullPolicy: Always\n",
+			path:     "image.tag",
+			value:    "v1.0.0",
+			expected: "image:\n  tag: v1.0.0\n  pullPolicy: Always\n",
+		},
+		{
+			name:     "Null",
+			input:    "tag: null\n",
+			path:     "tag",
+			value:    "v1.0.0",
+			expected: "tag: v1.0.0\n",
+		},
+		{
+			name:     "NullNeedingQuotes",
+			input:    "tag: ~\n",
+			path:     "tag",
+			value:    "1.10",
+			expected: "tag: \"1.10\"\n",
+		},
+		{
+			name:     "EmptyNeedingQuotes",
+			input:    "tag:\n",
+			path:     "tag",
+			value:    "1.10",
+			expected: "tag: \"1.10\"\n",
+		},
+		{
+			name:    "Missing",
+			input:   "tag: v1\n",
+			path:    "image.tag",
+			value:   "v2",
+			wantErr: true,
+		},
+		{
+			name:    "NotAScalar",
+			input:   "image:\n  tag: v1\n",
+			path:    "image",
+			value:   "v2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := paths.Parse(tt.path)
+			require.NoError(t, err)
+
+			result, err := SetScalar([]byte(tt.input), path, tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(result))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// ResolveSource returns the value described by source. Supported sources are:
+//
+//   - "chart:<field>" - a field of the chart's Chart.yaml (name, version,
+//     appVersion or description)
+//   - "json:<file>#<path>" - the value at path in a JSON (or YAML) file
+//   - "env:<name>" - an environment variable
+//   - "value:<value>" - a literal value
+//
+// Relative file paths are resolved against chartDir.
+func ResolveSource(source string, chartDir string) (string, error) {
+	kind, ref, found := strings.Cut(source, ":")
+	if !found {
+		return "", fmt.Errorf("invalid source %q, expected <kind>:<reference>", source)
+	}
+
+	switch kind {
+	case "chart":
+		return chartField(chartDir, ref)
+	case "json":
+		file, pathString, _ := strings.Cut(ref, "#")
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(chartDir, file)
+		}
+		return fileValue(file, pathString)
+	case "env":
+		value, ok := os.LookupEnv(ref)
+		if !ok {
+			return "", fmt.Errorf("environment variable %q is not set", ref)
+		}
+		return value, nil
+	case "value":
+		return ref, nil
+	default:
+		return "", fmt.Errorf("unknown source kind %q", kind)
+	}
+}
+
+func chartField(chartDir string, field string) (string, error) {
+	chart, err := parser.LoadChart(chartDir)
+	if err != nil {
+		return "", err
+	}
+
+	if chart == nil {
+		return "", fmt.Errorf("no Chart.yaml found in %q", chartDir)
+	}
+
+	var value string
+	switch field {
+	case "name":
+		value = chart.Name
+	case "version":
+		value = chart.Version
+	case "appVersion":
+		value = chart.AppVersion
+	case "description":
+		value = chart.Description
+	default:
+		return "", fmt.Errorf("unknown field %q of Chart.yaml", field)
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("field %q of Chart.yaml is empty", field)
+	}
+
+	return value, nil
+}
+
+func fileValue(file string, pathString string) (string, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	// YAML is a superset of JSON, using the yaml parser lets us reuse the
+	// path lookup.
+	var root yaml.Node
+	if err := yaml.Unmarshal(contents, &root); err != nil {
+		return "", fmt.Errorf("could not parse %q: %w", file, err)
+	}
+
+	path, err := paths.Parse(pathString)
+	if err != nil {
+		return "", err
+	}
+
+	node := Find(&root, path)
+	if node == nil {
+		return "", fmt.Errorf("%q not found in %q", pathString, file)
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("%q in %q is not a scalar", pathString, file)
+	}
+
+	return node.Value, nil
+}
@@ -0,0 +1,429 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"gopkg.in/yaml.v3"
+)
+
+// Options configure how comments are formatted.
+type Options struct {
+	// Width is the maximum width of a comment line, longer lines of text
+	// are wrapped. Zero disables wrapping.
+	Width int
+
+	// Structure enables normalizing the yaml itself, in addition to the
+	// documentation comments.
+	Structure bool
+	// QuoteStyle is the quote style used for quoted strings when formatting
+	// the structure, by default the quote style is preserved.
+	QuoteStyle string
+	// SortKeys are the paths of the subtrees in which keys are sorted when
+	// formatting the structure, use "." for the whole file.
+	SortKeys []string
+}
+
+// tagOrder is the canonical order of tags within a run of tag lines, tags
+// that are not listed are sorted after these.
+var tagOrder = []string{
+	"docs:title",
+	"docs:intro",
+	"docs:version-note",
+	"docs:section-end",
+	"docs:section",
+	"docs:property",
+	"docs:ignore",
+	"docs:hidden",
+	"docs:audience",
+	"docs:advanced",
+	"docs:owner",
+	"docs:name",
+	"docs:weight",
+	"docs:deprecated",
+	"docs:removed-in",
+	"docs:read-only",
+	"docs:type",
+	"docs:default",
+	"docs:default-from",
+	"docs:enum",
+	"docs:example",
+	"docs:see",
+	"docs:alias",
+	"docs:include",
+}
+
+// blockScalarExp matches lines that start a literal or folded block scalar,
+// the following more indented lines are part of the value.
+var blockScalarExp = regexp.MustCompile(`:\s*[|>][-+0-9]*\s*(#.*)?$|^\s*-\s+[|>][-+0-9]*\s*(#.*)?$`)
+
+type line struct {
+	text   string
+	ending string
+}
+
+// FormatComments rewrites the documentation comments of a values.yaml file
+// into their canonical form. Documentation comments are the comments directly
+// above a key and comments containing tags. All other content, including
+// other comments and blank lines, is preserved as-is.
+func FormatComments(content []byte, options Options) ([]byte, error) {
+	lines := splitLines(string(content))
+
+	var result []line
+	inBlockScalar, blockScalarIndent := false, 0
+	for i := 0; i < len(lines); {
+		current := lines[i]
+		trimmed := strings.TrimSpace(current.text)
+
+		if inBlockScalar {
+			if trimmed == "" || countIndent(current.text) > blockScalarIndent {
+				result = append(result, current)
+				i++
+				continue
+			}
+			inBlockScalar = false
+		}
+
+		if !strings.HasPrefix(trimmed, "#") {
+			if blockScalarExp.MatchString(current.text) {
+				inBlockScalar, blockScalarIndent = true, countIndent(current.text)
+			}
+
+			result = append(result, current)
+			i++
+			continue
+		}
+
+		// Collect the consecutive comment lines
+		end := i
+		for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end].text), "#") {
+			end++
+		}
+
+		block := lines[i:end]
+		next := ""
+		if end < len(lines) {
+			next = lines[end].text
+		}
+
+		if isDocsBlock(block, next) {
+			block = formatBlock(block, next, options)
+		}
+
+		result = append(result, block...)
+		i = end
+	}
+
+	var sb strings.Builder
+	for _, l := range result {
+		sb.WriteString(l.text)
+		sb.WriteString(l.ending)
+	}
+	formatted := []byte(sb.String())
+
+	if err := verifyValuesUnchanged(content, formatted); err != nil {
+		return nil, err
+	}
+
+	if bytes.Count(content, []byte("\n")) > bytes.Count(formatted, []byte("\n")) {
+		return nil, fmt.Errorf("formatting removed lines from the document")
+	}
+
+	return formatted, nil
+}
+
+func splitLines(content string) []line {
+	var lines []line
+	for content != "" {
+		text, rest, found := strings.Cut(content, "\n")
+		l := line{text: text}
+		if found {
+			l.ending = "\n"
+			if strings.HasSuffix(text, "\r") {
+				l.text, l.ending = strings.TrimSuffix(text, "\r"), "\r\n"
+			}
+		}
+
+		lines = append(lines, l)
+		content = rest
+	}
+
+	return lines
+}
+
+// isDocsBlock returns true if the comment block is documentation, either
+// because it contains a tag or because it directly precedes a value.
+func isDocsBlock(block []line, next string) bool {
+	if strings.TrimSpace(next) != "" {
+		return true
+	}
+
+	for _, l := range block {
+		if isTagContent(commentContent(l.text)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatBlock(block []line, next string, options Options) []line {
+	// Comments directly above a value are indented the same as the value,
+	// other comments keep the indentation of their first line.
+	indent := leadingWhitespace(block[0].text)
+	if strings.TrimSpace(next) != "" {
+		indent = leadingWhitespace(next)
+	}
+
+	// The smallest amount of spaces between the '#' characters and the
+	// content, the relative indentation of nested content (eg. yaml
+	// examples) is preserved.
+	baseSpaces := -1
+	for _, l := range block {
+		content := commentContent(l.text)
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		if spaces := countIndent(content); baseSpaces == -1 || spaces < baseSpaces {
+			baseSpaces = spaces
+		}
+	}
+
+	var formatted []line
+	for _, l := range block {
+		hashes := commentHashes(l.text)
+		content := strings.TrimRight(commentContent(l.text), " \t")
+		if strings.TrimSpace(content) == "" {
+			formatted = append(formatted, line{text: indent + hashes, ending: l.ending})
+			continue
+		}
+
+		formatted = append(formatted, line{text: indent + hashes + " " + content[baseSpaces:], ending: l.ending})
+	}
+
+	sortTagRuns(formatted)
+
+	if options.Width > 0 {
+		formatted = wrapBlock(formatted, options.Width)
+	}
+
+	return formatted
+}
+
+// wrapBlock wraps the lines of text in the block that are longer than width.
+// A line is only wrapped if that doesn't change how the comment is parsed,
+// the heuristics could otherwise treat the wrapped lines as eg. a list or a
+// yaml example.
+func wrapBlock(block []line, width int) []line {
+	expected := parsedText(block)
+
+	var sniffer heuristics.ContentSniffer
+	for i := 0; i < len(block); i++ {
+		hashes := commentHashes(block[i].text)
+		content := commentContent(block[i].text)
+		typ, _ := sniffer.SniffContentType(content)
+		if typ != heuristics.ContentTypeText || len(block[i].text) <= width || !isProse(content) {
+			continue
+		}
+
+		indent := leadingWhitespace(block[i].text)
+		var wrapped []line
+		for _, text := range wrap(content[1:], width-len(indent)-len(hashes)-1) {
+			wrapped = append(wrapped, line{text: indent + hashes + " " + text, ending: block[i].ending})
+		}
+
+		candidate := append(append(append([]line{}, block[:i]...), wrapped...), block[i+1:]...)
+		if parsedText(candidate) != expected {
+			continue
+		}
+
+		block = candidate
+		i += len(wrapped) - 1
+	}
+
+	return block
+}
+
+// parsedText returns the text of the comment block as it is parsed by the
+// documentation generator.
+func parsedText(block []line) string {
+	var sb strings.Builder
+	for _, l := range block {
+		sb.WriteString(strings.TrimLeft(l.text, " \t"))
+		sb.WriteString("\n")
+	}
+
+	var parsed strings.Builder
+	for _, commentBlock := range heuristics.ParseCommentIntoBlocks(sb.String()) {
+		for _, segment := range commentBlock.Segments {
+			parsed.WriteString(string(segment.Type))
+			parsed.WriteString(":")
+			parsed.WriteString(segment.String())
+			parsed.WriteString("\n")
+		}
+	}
+
+	return parsed.String()
+}
+
+// sortTagRuns sorts each run of consecutive tag lines into the canonical tag
+// order.
+func sortTagRuns(lines []line) {
+	for start := 0; start < len(lines); {
+		if !isTagContent(commentContent(lines[start].text)) {
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(lines) && isTagContent(commentContent(lines[end].text)) {
+			end++
+		}
+
+		run := lines[start:end]
+		endings := make([]string, len(run))
+		for i, l := range run {
+			endings[i] = l.ending
+		}
+
+		slices.SortStableFunc(run, func(a, b line) int {
+			return tagRank(commentContent(a.text)) - tagRank(commentContent(b.text))
+		})
+
+		// Line endings belong to the position, not to the tag
+		for i := range run {
+			run[i].ending = endings[i]
+		}
+
+		start = end
+	}
+}
+
+func tagRank(content string) int {
+	key, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(content), "+"), "=")
+	if idx := slices.Index(tagOrder, key); idx != -1 {
+		return idx
+	}
+
+	return len(tagOrder)
+}
+
+func isTagContent(content string) bool {
+	return strings.HasPrefix(strings.TrimSpace(content), "+docs:")
+}
+
+// wrap splits text into lines of at most width characters, words longer
+// than the width are never split.
+func wrap(text string, width int) []string {
+	if width <= 0 || len(text) <= width {
+		return []string{text}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		switch {
+		case current == "":
+			current = word
+		case len(current)+1+len(word) > width:
+			lines = append(lines, current)
+			current = word
+		default:
+			current += " " + word
+		}
+	}
+
+	return append(lines, current)
+}
+
+// isProse returns true if the content is a regular line of text that can be
+// wrapped, rather than eg. an indented block or a list item.
+func isProse(content string) bool {
+	if countIndent(content) != 1 {
+		return false
+	}
+
+	first, _ := utf8.DecodeRuneInString(content[1:])
+	return unicode.IsLetter(first) || unicode.IsNumber(first)
+}
+
+// commentHashes returns the '#' characters that start the comment line.
+func commentHashes(text string) string {
+	trimmed := strings.TrimLeft(text, " \t")
+	return trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, "#"))]
+}
+
+// commentContent returns the comment line without indentation and the '#'
+// characters.
+func commentContent(text string) string {
+	return strings.TrimLeft(strings.TrimLeft(text, " \t"), "#")
+}
+
+func leadingWhitespace(text string) string {
+	return text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+}
+
+func countIndent(text string) int {
+	return len(leadingWhitespace(text))
+}
+
+// verifyValuesUnchanged makes sure formatting did not change the values of
+// the yaml documents, which would indicate a bug in the formatter.
+func verifyValuesUnchanged(original, formatted []byte) error {
+	originalValues, err := decodeDocuments(original)
+	if err != nil {
+		return fmt.Errorf("could not parse values: %w", err)
+	}
+
+	formattedValues, err := decodeDocuments(formatted)
+	if err != nil {
+		return fmt.Errorf("formatting produced invalid yaml: %w", err)
+	}
+
+	if !reflect.DeepEqual(originalValues, formattedValues) {
+		return fmt.Errorf("formatting changed the values of the document")
+	}
+
+	return nil
+}
+
+// decodeDocuments decodes the values of every document of the yaml content.
+func decodeDocuments(content []byte) ([]any, error) {
+	var values []any
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var value any
+		if err := decoder.Decode(&value); errors.Is(err, io.EOF) {
+			return values, nil
+		} else if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		input    string
+		expected string
+	}{
+		{
+			name:     "HashSpacing",
+			input:    "#Replica count   \nreplicas: 1\n",
+			expected: "# Replica count\nreplicas: 1\n",
+		},
+		{
+			name:     "Indentation",
+			input:    "image:\n# The tag\n    #   of the image\n  tag: v1\n",
+			expected: "image:\n  # The tag\n  #   of the image\n  tag: v1\n",
+		},
+		{
+			name:     "NestedYamlIsPreserved",
+			input:    "#  For example:\n#  foo:\n#    bar: baz\nfoo: {}\n",
+			expected: "# For example:\n# foo:\n#   bar: baz\nfoo: {}\n",
+		},
+		{
+			name:     "TagOrder",
+			input:    "# +docs:default=x\n# +docs:type=string\n# +docs:property\n# Description\n# foo: x\n",
+			expected: "# +docs:property\n# +docs:type=string\n# +docs:default=x\n# Description\n# foo: x\n",
+		},
+		{
+			name:     "NonDocsCommentsArePreserved",
+			input:    "#Copyright notice   \n\nfoo: bar #inline\n\n  #   dangling\n",
+			expected: "#Copyright notice   \n\nfoo: bar #inline\n\n  #   dangling\n",
+		},
+		{
+			name:     "BlockScalarsArePreserved",
+			input:    "config: |\n  #not a comment\n  value: 1\n#Real comment\nother: 1\n",
+			expected: "config: |\n  #not a comment\n  value: 1\n# Real comment\nother: 1\n",
+		},
+		{
+			name:     "LineEndings",
+			input:    "#One\r\n#+docs:type=string\r\nfoo: bar\r\n",
+			expected: "# One\r\n# +docs:type=string\r\nfoo: bar\r\n",
+		},
+		{
+			name:     "Wrapping",
+			options:  Options{Width: 20},
+			input:    "# the quick brown fox jumps over the lazy dog\n# - list items are kept\nfoo: bar\n",
+			expected: "# the quick brown\n# fox jumps over the\n# lazy dog\n# - list items are kept\nfoo: bar\n",
+		},
+		{
+			name:     "WrappingKeepsIntentionalNewLines",
+			options:  Options{Width: 20},
+			input:    "# This line is longer than twenty characters\nfoo: bar\n",
+			expected: "# This line is longer than twenty characters\nfoo: bar\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatted, err := FormatComments([]byte(tt.input), tt.options)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(formatted))
+
+			// Formatting must be idempotent
+			again, err := FormatComments(formatted, tt.options)
+			require.NoError(t, err)
+			require.Equal(t, string(formatted), string(again))
+		})
+	}
+}
@@ -0,0 +1,458 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	QuoteStylePreserve = ""
+	QuoteStyleDouble   = "double"
+	QuoteStyleSingle   = "single"
+)
+
+// Format formats the values file, if structure formatting is enabled the
+// yaml itself is normalized before the documentation comments are formatted.
+func Format(content []byte, options Options) ([]byte, error) {
+	if options.Structure {
+		var err error
+		content, err = FormatStructure(content, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return FormatComments(content, options)
+}
+
+// FormatStructure normalizes the yaml of a values file: indentation is set to
+// two spaces, quoted strings use the configured quote style and the keys of
+// the configured subtrees are sorted. The file is round-tripped through
+// yaml.Node, so comments and anchors are kept, and blank lines are restored
+// after encoding.
+func FormatStructure(content []byte, options Options) ([]byte, error) {
+	switch options.QuoteStyle {
+	case QuoteStylePreserve, QuoteStyleDouble, QuoteStyleSingle:
+	default:
+		return nil, fmt.Errorf("unknown quote style %q", options.QuoteStyle)
+	}
+
+	sortKeys := make([]paths.Path, 0, len(options.SortKeys))
+	for _, sortKey := range options.SortKeys {
+		path, err := paths.Parse(strings.TrimPrefix(sortKey, "."))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse sort path %q: %w", sortKey, err)
+		}
+		sortKeys = append(sortKeys, path)
+	}
+
+	// Every document of the file is formatted
+	var documents []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		documents = append(documents, &document)
+	}
+
+	// An empty file has nothing to format
+	if len(documents) == 0 {
+		return content, nil
+	}
+
+	// order holds the indices of the original lines, with the entries of
+	// sorted mappings moved the same way as in the formatted output
+	lines := splitLines(string(content))
+	order := make([]int, len(lines))
+	for i := range order {
+		order[i] = i
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, document := range documents {
+		normalize(document, paths.Path{}, options, sortKeys, lines, order)
+		if err := encoder.Encode(document); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	var original strings.Builder
+	for _, i := range order {
+		original.WriteString(lines[i].text + "\n")
+	}
+
+	formatted := restoreBlankLines([]byte(original.String()), buf.Bytes())
+
+	if err := verifyValuesUnchanged(content, formatted); err != nil {
+		return nil, err
+	}
+
+	return formatted, nil
+}
+
+func normalize(node *yaml.Node, path paths.Path, options Options, sortKeys []paths.Path, lines []line, order []int) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			normalize(child, path, options, sortKeys, lines, order)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			normalize(child, path.WithIndex(i), options, sortKeys, lines, order)
+		}
+	case yaml.MappingNode:
+		if shouldSortKeys(path, sortKeys) {
+			sortMapping(node, lines, order)
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			normalizeQuotes(node.Content[i], options.QuoteStyle)
+			normalize(node.Content[i+1], path.WithProperty(node.Content[i].Value), options, sortKeys, lines, order)
+		}
+	case yaml.ScalarNode:
+		normalizeQuotes(node, options.QuoteStyle)
+	}
+}
+
+func shouldSortKeys(path paths.Path, sortKeys []paths.Path) bool {
+	for _, sortKey := range sortKeys {
+		if sortKey.IsSubPathOf(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortMapping sorts the key/value pairs of a mapping node by key, the
+// comments of a key are moved together with the key. The lines of the
+// entries are moved the same way in order, the blank lines separating the
+// entries stay at their position.
+func sortMapping(node *yaml.Node, lines []line, order []int) {
+	type pair struct {
+		key, value  *yaml.Node
+		first, last int
+	}
+
+	pairs := make([]pair, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		pairs = append(pairs, pair{key: key, value: node.Content[i+1], first: firstEntryLine(key, lines)})
+	}
+
+	for i := range pairs {
+		if i+1 < len(pairs) {
+			pairs[i].last = pairs[i+1].first - 1
+		} else {
+			pairs[i].last = lastEntryLine(pairs[i].key, lines)
+		}
+		for pairs[i].last > pairs[i].first && isBlank(lines[pairs[i].last].text) {
+			pairs[i].last--
+		}
+	}
+
+	// The lines of each entry and the separators between the entries, the
+	// entries can already have been moved by sorting a parent mapping
+	start := -1
+	entries := map[*yaml.Node][]int{}
+	var separators [][]int
+	if len(pairs) > 0 && pairs[0].key.Line > 0 && node.Style&yaml.FlowStyle == 0 {
+		start = indexOf(order, pairs[0].first)
+		position := start
+		for i, p := range pairs {
+			end := indexOf(order, p.last) + 1
+			if end <= position {
+				// The entries don't have lines of their own
+				start = -1
+				break
+			}
+			entries[p.key] = append([]int(nil), order[position:end]...)
+			position = end
+			if i+1 < len(pairs) {
+				next := indexOf(order, pairs[i+1].first)
+				if next < position {
+					start = -1
+					break
+				}
+				separators = append(separators, append([]int(nil), order[position:next]...))
+				position = next
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].key.Value < pairs[j].key.Value
+	})
+
+	if start >= 0 {
+		reordered := order[start:start]
+		for i, p := range pairs {
+			reordered = append(reordered, entries[p.key]...)
+			if i < len(separators) {
+				reordered = append(reordered, separators[i]...)
+			}
+		}
+	}
+
+	node.Content = node.Content[:0]
+	for _, p := range pairs {
+		node.Content = append(node.Content, p.key, p.value)
+	}
+}
+
+// firstEntryLine returns the index of the first line of the entry of key,
+// including the lines of its head comment. The head comment can contain
+// blank lines separating comment paragraphs.
+func firstEntryLine(key *yaml.Node, lines []line) int {
+	comments := 0
+	for _, c := range strings.Split(key.HeadComment, "\n") {
+		if strings.TrimSpace(c) != "" {
+			comments++
+		}
+	}
+
+	first := key.Line - 1
+	for first > 0 && first <= len(lines) && comments > 0 {
+		text := lines[first-1].text
+		if isComment(text) {
+			comments--
+		} else if !isBlank(text) {
+			break
+		}
+		first--
+	}
+
+	for first < key.Line-1 && isBlank(lines[first].text) {
+		first++
+	}
+
+	return first
+}
+
+// lastEntryLine returns the index of the last line of the last entry of a
+// mapping, which is followed by lines indented less than its key.
+func lastEntryLine(key *yaml.Node, lines []line) int {
+	last := key.Line - 1
+	for last+1 < len(lines) {
+		text := lines[last+1].text
+		if !isBlank(text) && indentation(text) < key.Column &&
+			!(indentation(text) == key.Column-1 && strings.HasPrefix(strings.TrimSpace(text), "-")) {
+			break
+		}
+		last++
+	}
+
+	return last
+}
+
+func indexOf(order []int, line int) int {
+	for i, l := range order {
+		if l == line {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isBlank(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
+
+func isComment(text string) bool {
+	return strings.HasPrefix(strings.TrimSpace(text), "#")
+}
+
+func indentation(text string) int {
+	return len(text) - len(strings.TrimLeft(text, " "))
+}
+
+func normalizeQuotes(node *yaml.Node, quoteStyle string) {
+	if quoteStyle == QuoteStylePreserve || node.Kind != yaml.ScalarNode {
+		return
+	}
+
+	if node.Style != yaml.DoubleQuotedStyle && node.Style != yaml.SingleQuotedStyle {
+		return
+	}
+
+	switch quoteStyle {
+	case QuoteStyleDouble:
+		node.Style = yaml.DoubleQuotedStyle
+	case QuoteStyleSingle:
+		// Single quoted strings cannot contain escape sequences
+		if strings.IndexFunc(node.Value, func(r rune) bool { return !unicode.IsPrint(r) }) == -1 {
+			node.Style = yaml.SingleQuotedStyle
+		}
+	}
+}
+
+// restoreBlankLines inserts the blank lines of the original document, which
+// are dropped when encoding yaml.Node, into the formatted document. Lines of
+// both documents are matched using their longest common subsequence, so blank
+// lines are restored even when keys are reordered or reindented.
+func restoreBlankLines(original, formatted []byte) []byte {
+	originalLines := nonBlankLines(splitLines(string(original)))
+	formattedLines := splitLines(string(formatted))
+
+	a := make([]string, len(originalLines))
+	for i, l := range originalLines {
+		a[i] = l.text
+	}
+	b := make([]string, len(formattedLines))
+	for j, l := range formattedLines {
+		b[j] = strings.TrimSpace(l.text)
+	}
+
+	// matches[j] is the index of the original line matching formatted line j,
+	// or -1 if there is none
+	matches := make([]int, len(b))
+	for j := range matches {
+		matches[j] = -1
+	}
+	longestCommonSubsequence(a, b, 0, 0, matches)
+
+	var sb strings.Builder
+	previous := ""
+	for j, l := range formattedLines {
+		if i := matches[j]; i >= 0 && originalLines[i].blankBefore && strings.TrimSpace(previous) != "" {
+			sb.WriteString(l.ending)
+		}
+
+		sb.WriteString(l.text)
+		sb.WriteString(l.ending)
+		previous = l.text
+	}
+
+	return []byte(sb.String())
+}
+
+// longestCommonSubsequence matches the lines of a and b, which start at
+// offsets aOffset and bOffset, along their longest common subsequence and
+// records the matches in matches. It uses Hirschberg's algorithm, so only
+// linear space is needed for large files.
+func longestCommonSubsequence(a, b []string, aOffset, bOffset int, matches []int) {
+	// Most lines are unchanged, so matching the common prefix and suffix
+	// first keeps the quadratic part small
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		matches[bOffset] = aOffset
+		a, b = a[1:], b[1:]
+		aOffset, bOffset = aOffset+1, bOffset+1
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		matches[bOffset+len(b)-1] = aOffset + len(a) - 1
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		return
+	case len(a) == 1:
+		for j := range b {
+			if b[j] == a[0] {
+				matches[bOffset+j] = aOffset
+				return
+			}
+		}
+		return
+	}
+
+	// Split a in half and b where the lengths of the subsequences of both
+	// halves add up to the longest
+	middle := len(a) / 2
+	forward := subsequenceLengths(a[:middle], b, false)
+	backward := subsequenceLengths(a[middle:], b, true)
+
+	split := 0
+	for k := range forward {
+		if forward[k]+backward[len(b)-k] > forward[split]+backward[len(b)-split] {
+			split = k
+		}
+	}
+
+	longestCommonSubsequence(a[:middle], b[:split], aOffset, bOffset, matches)
+	longestCommonSubsequence(a[middle:], b[split:], aOffset+middle, bOffset+split, matches)
+}
+
+// subsequenceLengths returns the lengths of the longest common subsequences
+// of a and the first k lines of b for every k, or of the last k lines of
+// both if reverse is set.
+func subsequenceLengths(a, b []string, reverse bool) []int {
+	at := func(lines []string, i int) string {
+		if reverse {
+			return lines[len(lines)-1-i]
+		}
+		return lines[i]
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if at(a, i) == at(b, j) {
+				current[j+1] = previous[j] + 1
+			} else {
+				current[j+1] = max(previous[j+1], current[j])
+			}
+		}
+		previous, current = current, previous
+	}
+
+	return previous
+}
+
+type nonBlankLine struct {
+	text        string
+	blankBefore bool
+}
+
+// nonBlankLines returns the trimmed non-blank lines, noting which lines were
+// preceded by a blank line.
+func nonBlankLines(lines []line) []nonBlankLine {
+	var result []nonBlankLine
+	blank := false
+	for _, l := range lines {
+		text := strings.TrimSpace(l.text)
+		if text == "" {
+			blank = len(result) > 0
+			continue
+		}
+
+		result = append(result, nonBlankLine{text: text, blankBefore: blank})
+		blank = false
+	}
+
+	return result
+}
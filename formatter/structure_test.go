@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatStructure(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		input    string
+		expected string
+	}{
+		{
+			name:     "Indentation",
+			input:    "a:\n    b:\n        c: 1\n    list:\n    - x\n",
+			expected: "a:\n  b:\n    c: 1\n  list:\n    - x\n",
+		},
+		{
+			name:     "QuoteStyle",
+			options:  Options{QuoteStyle: QuoteStyleDouble},
+			input:    "a: 'x'\nb: y\n'c': \"z\"\n",
+			expected: "a: \"x\"\nb: y\n\"c\": \"z\"\n",
+		},
+		{
+			name:     "SortKeys",
+			options:  Options{SortKeys: []string{"a"}},
+			input:    "z: 1\na:\n  # Comment of y\n  y: 2\n\n  x:\n    d: 1\n    c: 2\n",
+			expected: "z: 1\na:\n  x:\n    c: 2\n    d: 1\n\n  # Comment of y\n  y: 2\n",
+		},
+		{
+			name:     "SortKeysBlankLines",
+			options:  Options{SortKeys: []string{"."}},
+			input:    "c: 3\n\n# Comment of b\nb:\n  y: 1\n\n  x: 2\na: 1\n\n# Trailing comment\n",
+			expected: "a: 1\n\n# Comment of b\nb:\n  x: 2\n\n  y: 1\nc: 3\n\n# Trailing comment\n",
+		},
+		{
+			name:     "MultipleDocuments",
+			options:  Options{SortKeys: []string{"."}},
+			input:    "b: 1\n\na: 2\n---\nz: 3\n\n# Comment of y\ny: 4\n",
+			expected: "a: 2\n\nb: 1\n---\n# Comment of y\ny: 4\n\nz: 3\n",
+		},
+		{
+			name:     "SortKeysCommentParagraphs",
+			options:  Options{SortKeys: []string{"w"}},
+			input:    "w:\n  b:\n    x: 1\n\n    # x: 2\n\n  # Paragraph\n\n  # Comment of a\n  a: 1\n\n  c: 3\n",
+			expected: "w:\n  # Paragraph\n\n  # Comment of a\n  a: 1\n\n  b:\n    x: 1\n\n    # x: 2\n\n  c: 3\n",
+		},
+		{
+			name:     "BlankLinesAndAnchors",
+			input:    "# Header\n\n# Comment\na: &anchor\n  b: 1\n\nc: *anchor\n",
+			expected: "# Header\n\n# Comment\na: &anchor\n  b: 1\n\nc: *anchor\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatted, err := FormatStructure([]byte(tt.input), tt.options)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(formatted))
+		})
+	}
+}
@@ -0,0 +1,343 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package helmfile reads the releases of a helmfile.yaml and the values they
+// set, so the values can be documented using the documentation of the charts.
+package helmfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultEnvironment is the environment helmfile uses if none is selected,
+// it does not have to be defined in the helmfile.
+const DefaultEnvironment = "default"
+
+// Helmfile contains the environments and releases of a helmfile.yaml.
+type Helmfile struct {
+	Environments map[string]Environment `yaml:"environments"`
+	Releases     []Release              `yaml:"releases"`
+
+	// Dir is the directory of the helmfile, the paths in the helmfile are
+	// relative to it.
+	Dir string `yaml:"-"`
+	// Environment is the selected environment and EnvironmentValues are its
+	// merged values, these are available in templates as .Environment.Values
+	// and .Values.
+	Environment       string         `yaml:"-"`
+	EnvironmentValues map[string]any `yaml:"-"`
+}
+
+// Environment is an entry of the environments of a helmfile, its values are
+// values files or inline values.
+type Environment struct {
+	Values []any `yaml:"values"`
+}
+
+// Release is a release of a chart in a helmfile, its values are values files
+// or inline values, which are merged in order before Set is applied.
+type Release struct {
+	Name      string     `yaml:"name"`
+	Namespace string     `yaml:"namespace"`
+	Chart     string     `yaml:"chart"`
+	Installed *bool      `yaml:"installed"`
+	Values    []any      `yaml:"values"`
+	Set       []SetValue `yaml:"set"`
+}
+
+// SetValue is a value set using the set list of a release, the name is a
+// path in the syntax of helm's --set flag.
+type SetValue struct {
+	Name  string `yaml:"name"`
+	Value any    `yaml:"value"`
+}
+
+// IsInstalled returns whether the release is installed, releases are
+// installed unless installed is set to false.
+func (r Release) IsInstalled() bool {
+	return r.Installed == nil || *r.Installed
+}
+
+// Load reads the helmfile using the given environment. Like helmfile itself,
+// the helmfile is rendered as a template twice: first to read the
+// environments, then again with the values of the selected environment to
+// read the releases.
+func Load(filename string, environment string) (*Helmfile, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if environment == "" {
+		environment = DefaultEnvironment
+	}
+
+	helmfile := &Helmfile{
+		Dir:               filepath.Dir(filename),
+		Environment:       environment,
+		EnvironmentValues: map[string]any{},
+	}
+
+	environments, err := helmfile.decode(filename, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	env, ok := environments.Environments[environment]
+	if !ok && environment != DefaultEnvironment {
+		return nil, fmt.Errorf("environment %q is not defined in %s", environment, filename)
+	}
+
+	for _, values := range env.Values {
+		values, err := helmfile.loadValues(values, nil)
+		if err != nil {
+			return nil, fmt.Errorf("environment %q: %w", environment, err)
+		}
+
+		mergeValues(helmfile.EnvironmentValues, values)
+	}
+
+	result, err := helmfile.decode(filename, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	helmfile.Environments = result.Environments
+	helmfile.Releases = result.Releases
+	return helmfile, nil
+}
+
+// decode renders the helmfile with the current environment values and
+// decodes all of its documents, which are merged.
+func (h *Helmfile) decode(filename string, contents []byte) (*Helmfile, error) {
+	rendered, err := h.render(filename, contents, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Helmfile{Environments: map[string]Environment{}}
+	decoder := yaml.NewDecoder(bytes.NewReader(rendered))
+	for {
+		var document Helmfile
+		if err := decoder.Decode(&document); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+
+		for name, environment := range document.Environments {
+			result.Environments[name] = environment
+		}
+		result.Releases = append(result.Releases, document.Releases...)
+	}
+
+	return result, nil
+}
+
+// render executes a helmfile or values file template, the release is nil
+// when rendering the helmfile or environment values.
+func (h *Helmfile) render(filename string, contents []byte, release *Release) ([]byte, error) {
+	data := map[string]any{
+		"Environment": map[string]any{
+			"Name":   h.Environment,
+			"Values": h.EnvironmentValues,
+		},
+		"Values":      h.EnvironmentValues,
+		"StateValues": h.EnvironmentValues,
+	}
+	if release != nil {
+		data["Release"] = map[string]any{
+			"Name":      release.Name,
+			"Namespace": release.Namespace,
+			"Chart":     release.Chart,
+		}
+	}
+
+	funcMap := sprig.TxtFuncMap()
+	funcMap["toYaml"] = func(v any) (string, error) {
+		output, err := yaml.Marshal(v)
+		return strings.TrimSuffix(string(output), "\n"), err
+	}
+	funcMap["requiredEnv"] = func(name string) (string, error) {
+		if value := os.Getenv(name); value != "" {
+			return value, nil
+		}
+
+		return "", fmt.Errorf("required env var %q is not set", name)
+	}
+
+	tmpl, err := template.New(filepath.Base(filename)).Funcs(funcMap).Parse(string(contents))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// loadValues returns the values of an entry of a values list, which is
+// either the path of a values file or inline values. Values files ending in
+// .gotmpl are rendered as templates first.
+func (h *Helmfile) loadValues(entry any, release *Release) (map[string]any, error) {
+	switch entry := entry.(type) {
+	case map[string]any:
+		return entry, nil
+	case string:
+		filename := entry
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(h.Dir, filename)
+		}
+
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasSuffix(filename, ".gotmpl") {
+			contents, err = h.render(filename, contents, release)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		values := map[string]any{}
+		if err := yaml.Unmarshal(contents, &values); err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported values entry of type %T", entry)
+	}
+}
+
+// ReleaseValues returns the values the release sets: its values files and
+// inline values merged in order, with the set list applied on top.
+func (h *Helmfile) ReleaseValues(release Release) (map[string]any, error) {
+	result := map[string]any{}
+	for _, entry := range release.Values {
+		values, err := h.loadValues(entry, &release)
+		if err != nil {
+			return nil, fmt.Errorf("release %q: %w", release.Name, err)
+		}
+
+		mergeValues(result, values)
+	}
+
+	for _, set := range release.Set {
+		path, err := paths.Parse(set.Name)
+		if err != nil {
+			return nil, fmt.Errorf("release %q: set %q: %w", release.Name, set.Name, err)
+		}
+
+		if _, err := setValue(result, path, set.Value); err != nil {
+			return nil, fmt.Errorf("release %q: set %q: %w", release.Name, set.Name, err)
+		}
+	}
+
+	return result, nil
+}
+
+// ChartDir returns the directory of the chart of the release, or an empty
+// string if the chart is not a local chart (eg. "jetstack/cert-manager").
+func (h *Helmfile) ChartDir(release Release) string {
+	dir := release.Chart
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(h.Dir, dir)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "values.yaml")); err != nil {
+		return ""
+	}
+
+	return dir
+}
+
+// mergeValues merges the values of src into dst, maps are merged
+// recursively and all other values replace the value in dst.
+func mergeValues(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, srcOk := value.(map[string]any)
+		dstMap, dstOk := dst[key].(map[string]any)
+		if srcOk && dstOk {
+			mergeValues(dstMap, srcMap)
+			continue
+		}
+
+		dst[key] = value
+	}
+}
+
+// setValue returns the values with the value set at the path, the maps and
+// lists along the path are created or grown as needed.
+func setValue(values any, path paths.Path, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	segment := paths.SegmentString(path[0])
+	if paths.IsArrayPathComponent(path[0]) {
+		list, ok := values.([]any)
+		if values != nil && !ok {
+			return nil, fmt.Errorf("cannot set index %s of a %T", segment, values)
+		}
+
+		index, err := strconv.Atoi(strings.Trim(segment, "[]"))
+		if err != nil {
+			return nil, err
+		}
+
+		for len(list) <= index {
+			list = append(list, nil)
+		}
+
+		list[index], err = setValue(list[index], path[1:], value)
+		return list, err
+	}
+
+	m, ok := values.(map[string]any)
+	if values != nil && !ok {
+		return nil, fmt.Errorf("cannot set key %q of a %T", segment, values)
+	}
+
+	if m == nil {
+		m = map[string]any{}
+	}
+
+	child, err := setValue(m[segment], path[1:], value)
+	if err != nil {
+		return nil, err
+	}
+
+	m[segment] = child
+	return m, nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helmfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+const testHelmfile = `environments:
+  default:
+    values:
+      - replicas: 1
+  production:
+    values:
+      - production.yaml
+---
+releases:
+  - name: app
+    namespace: apps
+    chart: ./chart
+    values:
+      - app.yaml.gotmpl
+      - image:
+          tag: v1
+    set:
+      - name: podAnnotations.linkerd\.io/inject
+        value: enabled
+      - name: args[1]
+        value: --v=2
+  - name: disabled
+    chart: example/disabled
+    installed: false
+`
+
+const testValues = `# The number of replicas
+replicas: {{ .Values.replicas }}
+{{- if eq .Environment.Name "production" }}
+# The image
+image:
+  tag: latest
+{{- end }}
+release: {{ .Release.Name }}
+`
+
+func writeHelmfile(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"helmfile.yaml":     testHelmfile,
+		"production.yaml":   "replicas: 3\n",
+		"app.yaml.gotmpl":   testValues,
+		"chart/values.yaml": "# The number of replicas\nreplicas: 1\n# The image\nimage:\n  # The image tag\n  tag: v0\n",
+	}
+
+	for name, contents := range files {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, []byte(contents), 0o644))
+	}
+
+	return filepath.Join(dir, "helmfile.yaml")
+}
+
+func TestLoad(t *testing.T) {
+	filename := writeHelmfile(t)
+
+	for _, test := range []struct {
+		environment string
+		expected    map[string]any
+	}{
+		{
+			environment: "",
+			expected: map[string]any{
+				"replicas":       1,
+				"image":          map[string]any{"tag": "v1"},
+				"release":        "app",
+				"podAnnotations": map[string]any{"linkerd.io/inject": "enabled"},
+				"args":           []any{nil, "--v=2"},
+			},
+		},
+		{
+			environment: "production",
+			expected: map[string]any{
+				"replicas":       3,
+				"image":          map[string]any{"tag": "v1"},
+				"release":        "app",
+				"podAnnotations": map[string]any{"linkerd.io/inject": "enabled"},
+				"args":           []any{nil, "--v=2"},
+			},
+		},
+	} {
+		file, err := Load(filename, test.environment)
+		require.NoError(t, err, test.environment)
+		require.Len(t, file.Releases, 2)
+		require.True(t, file.Releases[0].IsInstalled())
+		require.False(t, file.Releases[1].IsInstalled())
+
+		values, err := file.ReleaseValues(file.Releases[0])
+		require.NoError(t, err, test.environment)
+		require.Equal(t, test.expected, values, test.environment)
+
+		require.Equal(t, filepath.Join(filepath.Dir(filename), "chart"), file.ChartDir(file.Releases[0]))
+		require.Empty(t, file.ChartDir(file.Releases[1]))
+	}
+
+	_, err := Load(filename, "staging")
+	require.ErrorContains(t, err, `environment "staging" is not defined`)
+}
+
+func TestOverrides(t *testing.T) {
+	filename := writeHelmfile(t)
+	file, err := Load(filename, "production")
+	require.NoError(t, err)
+
+	values, err := file.ReleaseValues(file.Releases[0])
+	require.NoError(t, err)
+
+	document, err := parser.Load(filepath.Join(file.ChartDir(file.Releases[0]), "values.yaml"), false)
+	require.NoError(t, err)
+
+	overrides := Overrides(document, values)
+	var overridden []string
+	for _, override := range overrides {
+		overridden = append(overridden, override.Path.String())
+	}
+	require.Equal(t, []string{"args", "image.tag", `podAnnotations["linkerd.io/inject"]`, "release", "replicas"}, overridden)
+
+	tag, err := paths.Parse("image.tag")
+	require.NoError(t, err)
+	require.True(t, overrides[1].Property.Path.Equal(tag))
+	require.Nil(t, overrides[0].Property)
+
+	var sb strings.Builder
+	WriteMarkdown(&sb, []ReleaseOverrides{{Release: file.Releases[0], Documented: true, Overrides: overrides}})
+	require.Contains(t, sb.String(), "## app\n\nChart: `./chart`, namespace: `apps`\n")
+	require.Contains(t, sb.String(), "| `image.tag` | The image tag | `\"v0\"` | `\"v1\"` |\n")
+	require.Contains(t, sb.String(), "| `replicas` | The number of replicas | `1` | `3` |\n")
+	require.Contains(t, sb.String(), "| `release` | *Not documented by the chart* |  | `\"app\"` |\n")
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helmfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// Override is a value a release sets on top of the chart defaults.
+type Override struct {
+	Path  paths.Path
+	Value any
+	// Property is the documented property the value belongs to, this is
+	// either the property with the same path or a documented parent (eg.
+	// resources for resources.limits.cpu). It is nil if the value is not
+	// documented by the chart.
+	Property *parser.Property
+}
+
+// ReleaseOverrides are the values set by a release.
+type ReleaseOverrides struct {
+	Release Release
+	// Documented is set if the documentation of the chart is available,
+	// which is only the case for local charts.
+	Documented bool
+	Overrides  []Override
+}
+
+// Overrides returns the leaf values of the values set by a release, sorted
+// by path, with the properties of the document they belong to. Lists are not
+// split into their items, as helm replaces lists instead of merging them.
+// The document can be nil if the chart is not documented.
+func Overrides(document *parser.Document, values map[string]any) []Override {
+	var properties []*parser.Property
+	if document != nil {
+		for i := range document.Sections {
+			for j := range document.Sections[i].Properties {
+				properties = append(properties, &document.Sections[i].Properties[j])
+			}
+		}
+	}
+
+	var result []Override
+	var walk func(path paths.Path, value any)
+	walk = func(path paths.Path, value any) {
+		if m, ok := value.(map[string]any); ok && len(m) > 0 {
+			keys := make([]string, 0, len(m))
+			for key := range m {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				walk(path.WithProperty(key), m[key])
+			}
+			return
+		}
+
+		result = append(result, Override{
+			Path:     path,
+			Value:    value,
+			Property: propertyOf(properties, path),
+		})
+	}
+	walk(paths.Path{}, values)
+
+	return result
+}
+
+// propertyOf returns the property with the path, or the documented parent
+// closest to the path.
+func propertyOf(properties []*parser.Property, path paths.Path) *parser.Property {
+	var result *parser.Property
+	for _, property := range properties {
+		if !property.Path.IsSubPathOf(path) {
+			continue
+		}
+
+		if result == nil || len(property.Path) > len(result.Path) {
+			result = property
+		}
+	}
+
+	return result
+}
+
+// WriteMarkdown writes a table per release with the values it sets, their
+// description and the chart defaults they replace.
+func WriteMarkdown(w io.Writer, releases []ReleaseOverrides) {
+	for i, release := range releases {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "## %s\n\n", release.Release.Name)
+		fmt.Fprintf(w, "Chart: `%s`", release.Release.Chart)
+		if release.Release.Namespace != "" {
+			fmt.Fprintf(w, ", namespace: `%s`", release.Release.Namespace)
+		}
+		fmt.Fprintln(w)
+
+		if !release.Documented {
+			fmt.Fprintf(w, "\nThe chart is not a local chart, so its values are not documented.\n")
+		}
+
+		if len(release.Overrides) == 0 {
+			fmt.Fprintf(w, "\nThe release uses the chart defaults.\n")
+			continue
+		}
+
+		fmt.Fprintf(w, "\n| Value | Description | Chart default | Release value |\n")
+		fmt.Fprintf(w, "| --- | --- | --- | --- |\n")
+		for _, override := range release.Overrides {
+			var description, defaultValue string
+			if override.Property != nil {
+				description = descriptionOf(*override.Property)
+				if override.Property.Path.Equal(override.Path) {
+					if value := defaultOf(*override.Property); value != "" {
+						defaultValue = code(value)
+					}
+				}
+			} else if release.Documented {
+				description = "*Not documented by the chart*"
+			}
+
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+				code(override.Path.String()),
+				tableCell(description),
+				defaultValue,
+				code(compactJSON(override.Value)),
+			)
+		}
+	}
+}
+
+// descriptionOf returns the text of the description of the property on a
+// single line.
+func descriptionOf(property parser.Property) string {
+	var parts []string
+	for _, segment := range property.Description.Segments {
+		if segment.Type == heuristics.ContentTypeText {
+			parts = append(parts, strings.Fields(segment.String())...)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// defaultOf returns the default of the property as compact JSON, which fits
+// in a table cell.
+func defaultOf(property parser.Property) string {
+	if strings.TrimSpace(property.Default) == "" {
+		return ""
+	}
+
+	var value any
+	if err := yaml.Unmarshal([]byte(property.Default), &value); err != nil {
+		return strings.Join(strings.Fields(property.Default), " ")
+	}
+
+	return compactJSON(value)
+}
+
+func compactJSON(value any) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func code(value string) string {
+	return "`" + tableCell(value) + "`"
+}
+
+func tableCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"bufio"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is the commit that last changed a line.
+type Commit struct {
+	Hash    string
+	Date    time.Time
+	Summary string
+}
+
+// Committed returns false for lines that have not been committed yet.
+func (c Commit) Committed() bool {
+	return strings.Trim(c.Hash, "0") != ""
+}
+
+// pullRequestExp matches the pull request number in the summary of squashed
+// ("Fix typo (#123)") and merge ("Merge pull request #123 from ...") commits.
+var pullRequestExp = regexp.MustCompile(`\(#(\d+)\)\s*$|^Merge pull request #(\d+)`)
+
+// PullRequest returns the number of the pull request the commit was merged
+// in, or an empty string if the summary does not mention it.
+func (c Commit) PullRequest() string {
+	match := pullRequestExp.FindStringSubmatch(c.Summary)
+	if match == nil {
+		return ""
+	}
+
+	return match[1] + match[2]
+}
+
+// Blame returns the commit that last changed each line of the file, the
+// commit of line n is at index n-1.
+func Blame(file string) ([]Commit, error) {
+	output, err := run(filepath.Dir(file), "blame", "--line-porcelain", "--", filepath.Base(file))
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	var current Commit
+	header := true
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(nil, len(output)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// The content of the line ends the entry
+			commits = append(commits, current)
+			current, header = Commit{}, true
+		case header:
+			current.Hash, _, _ = strings.Cut(line, " ")
+			header = false
+		case strings.HasPrefix(line, "committer-time "):
+			seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "committer-time "), 10, 64)
+			if err == nil {
+				current.Date = time.Unix(seconds, 0).UTC()
+			}
+		case strings.HasPrefix(line, "summary "):
+			current.Summary = strings.TrimPrefix(line, "summary ")
+		}
+	}
+
+	return commits, scanner.Err()
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ChangedSince returns true if any of the paths (files or directories) was
+// changed since the git ref, including uncommitted and untracked changes.
+// Paths that are not inside the git repository of the first path are
+// considered changed, as their history is not known.
+func ChangedSince(ref string, paths ...string) (bool, error) {
+	if len(paths) == 0 {
+		return false, nil
+	}
+
+	dir, err := filepath.Abs(paths[0])
+	if err != nil {
+		return false, err
+	}
+	if !isDir(dir) {
+		dir = filepath.Dir(dir)
+	}
+
+	root, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return false, err
+	}
+	root = strings.TrimSpace(root)
+
+	var relative []string
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return false, err
+		}
+
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return true, nil
+		}
+
+		relative = append(relative, filepath.ToSlash(rel))
+	}
+
+	changed, err := run(root, append([]string{"diff", "--name-only", ref, "--"}, relative...)...)
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(changed) != "" {
+		return true, nil
+	}
+
+	untracked, err := run(root, append([]string{"ls-files", "--others", "--exclude-standard", "--"}, relative...)...)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(untracked) != "", nil
+}
+
+func run(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	for _, chart := range []string{"a", "b"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, chart), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, chart, "values.yaml"), []byte("a: 1\n"), 0644))
+	}
+
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	changed, err := ChangedSince("HEAD", filepath.Join(dir, "a"))
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// Uncommitted changes
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a", "values.yaml"), []byte("a: 2\n"), 0644))
+	changed, err = ChangedSince("HEAD", filepath.Join(dir, "a"))
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	changed, err = ChangedSince("HEAD", filepath.Join(dir, "b", "values.yaml"))
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// Untracked files
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b", "README.md"), []byte("b\n"), 0644))
+	changed, err = ChangedSince("HEAD", filepath.Join(dir, "b"))
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	_, err = ChangedSince("unknown-ref", filepath.Join(dir, "a"))
+	require.Error(t, err)
+}
+
+func TestBlame(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	file := filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("a: 1\nb: 1\n"), 0644))
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	require.NoError(t, os.WriteFile(file, []byte("a: 1\nb: 2\n"), 0644))
+	git("commit", "-q", "-a", "-m", "Change b (#12)")
+
+	require.NoError(t, os.WriteFile(file, []byte("a: 1\nb: 2\nc: 3\n"), 0644))
+
+	commits, err := Blame(file)
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+
+	require.Equal(t, "initial", commits[0].Summary)
+	require.True(t, commits[0].Committed())
+	require.Empty(t, commits[0].PullRequest())
+
+	require.Equal(t, "Change b (#12)", commits[1].Summary)
+	require.Equal(t, "12", commits[1].PullRequest())
+	require.NotEqual(t, commits[0].Hash, commits[1].Hash)
+
+	require.False(t, commits[2].Committed())
+}
+
+func TestPullRequest(t *testing.T) {
+	require.Equal(t, "34", Commit{Summary: "Merge pull request #34 from user/branch"}.PullRequest())
+	require.Equal(t, "5", Commit{Summary: "Fix typo (#5)"}.PullRequest())
+	require.Empty(t, Commit{Summary: "Fix #5 and more"}.PullRequest())
+}
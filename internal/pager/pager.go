@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/cert-manager/helm-tool/internal/shellwords"
+)
+
+// DefaultCommand is the pager used if $PAGER is not set.
+const DefaultCommand = "less"
+
+// Command returns the pager command, which is $PAGER or the DefaultCommand.
+func Command() string {
+	if pager := os.Getenv("PAGER"); pager != "" {
+		return pager
+	}
+
+	return DefaultCommand
+}
+
+// Run pipes the output of write into the pager command, which writes to
+// stdout. Like git, $LESS is set to "FRX" unless it is set already, so less
+// passes colors through and quits if the output fits on a single screen. If
+// the pager cannot be started (eg. because it is not installed), the output
+// is written to stdout directly.
+func Run(command string, stdout io.Writer, write func(w io.Writer)) error {
+	args, err := shellwords.Split(command)
+	if err != nil {
+		return fmt.Errorf("pager %q: %w", command, err)
+	}
+	if len(args) == 0 || args[0] == "cat" {
+		write(stdout)
+		return nil
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		write(stdout)
+		return nil
+	}
+
+	// The pager closes its input when the user quits before reading all of
+	// it, so write errors are expected and ignored
+	write(stdin)
+	stdin.Close()
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("pager %q failed: %w", command, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pager
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	write := func(w io.Writer) {
+		fmt.Fprintln(w, "replicaCount number 1")
+	}
+
+	for _, command := range []string{"", "cat", "cat -u", "helm-tool-missing-pager"} {
+		var sb strings.Builder
+		require.NoError(t, Run(command, &sb, write), command)
+		require.Equal(t, "replicaCount number 1\n", sb.String(), command)
+	}
+
+	require.Error(t, Run("false", io.Discard, write))
+}
+
+func TestCommand(t *testing.T) {
+	t.Setenv("PAGER", "")
+	require.Equal(t, DefaultCommand, Command())
+
+	t.Setenv("PAGER", "most")
+	require.Equal(t, "most", Command())
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shellwords
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	tests := map[string][]string{
+		"":                                nil,
+		"  prettier   --write ":           {"prettier", "--write"},
+		`sed -i 's/a b/c d/'`:             {"sed", "-i", "s/a b/c d/"},
+		`opa eval -d "my policy.rego" ""`: {"opa", "eval", "-d", "my policy.rego", ""},
+		`echo "a \"b\" \n" 'c\d' e\ f`:    {"echo", `a "b" \n`, `c\d`, "e f"},
+		`markdownlint --config=".my rc"`:  {"markdownlint", "--config=.my rc"},
+	}
+
+	for command, expected := range tests {
+		args, err := Split(command)
+		require.NoError(t, err, command)
+		require.Equal(t, expected, args, command)
+	}
+
+	for _, command := range []string{`sed 's/a/b/`, `echo "a`, `echo a\`} {
+		_, err := Split(command)
+		require.Error(t, err, command)
+	}
+}
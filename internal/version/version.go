@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import "runtime/debug"
+
+// These are set at build time using ldflags, see make/00_mod.mk.
+var (
+	AppVersion = ""
+	GitCommit  = ""
+)
+
+// Version returns the version of helm-tool. Builds that were not made using
+// the Makefile (eg. go install) fall back to the module version.
+func Version() string {
+	if AppVersion != "" {
+		return AppVersion
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	return "devel"
+}
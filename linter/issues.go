@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// FormatText prints every issue on its own line, this is also the format
+	// used in the exceptions file.
+	FormatText = "text"
+
+	// FormatGitHub prints the issues as GitHub Actions workflow commands, so
+	// they are shown inline on pull requests.
+	FormatGitHub = "github"
+)
+
+// Issue is a single problem found by the linter.
+type Issue struct {
+	// File is the file that contains the problem, this is empty for problems
+	// in the values file.
+	File string
+	// Line is the line of the problem within the file, or 0 if unknown.
+	Line    int
+	Message string
+}
+
+// PrintIssues writes the issues to w in the given format, issues without a
+// file are attributed to defaultFile.
+func PrintIssues(w io.Writer, format string, defaultFile string, issues []Issue) error {
+	for _, issue := range issues {
+		if issue.File == "" {
+			issue.File = defaultFile
+		}
+
+		switch format {
+		case FormatText, "":
+			fmt.Fprintln(w, issue.Message)
+		case FormatGitHub:
+			fmt.Fprintln(w, issue.githubCommand())
+		default:
+			return fmt.Errorf("unknown format %q", format)
+		}
+	}
+
+	return nil
+}
+
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// githubCommand returns the issue as an error workflow command, see
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
+func (i Issue) githubCommand() string {
+	properties := "file=" + githubPropertyEscaper.Replace(i.File)
+	if i.Line > 0 {
+		properties += fmt.Sprintf(",line=%d", i.Line)
+	}
+
+	return fmt.Sprintf("::error %s::%s", properties, githubDataEscaper.Replace(i.Message))
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintIssues(t *testing.T) {
+	issues := []Issue{
+		{Line: 3, Message: "value missing from templates: a.b"},
+		{File: "docs/README,v2.md", Message: "100% stale\nreference"},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, PrintIssues(&text, FormatText, "values.yaml", issues))
+	require.Equal(t, "value missing from templates: a.b\n100% stale\nreference\n", text.String())
+
+	var github bytes.Buffer
+	require.NoError(t, PrintIssues(&github, FormatGitHub, "values.yaml", issues))
+	require.Equal(t, "::error file=values.yaml,line=3::value missing from templates: a.b\n"+
+		"::error file=docs/README%2Cv2.md::100%25 stale%0Areference\n", github.String())
+
+	require.Error(t, PrintIssues(&text, "xml", "values.yaml", issues))
+}
@@ -27,58 +27,70 @@ import (
 	"github.com/cert-manager/helm-tool/parser"
 )
 
+// Lint returns the differences between the values file and the templates
+// that are not listed in the exceptions file.
 func Lint(
 	templatesFolder string,
 	exceptionsPath string,
 	document *parser.Document,
-) error {
+) ([]Issue, error) {
 	templatePaths, err := parsetemplates.ListTemplatePaths(templatesFolder)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	valuePaths := sets.Set[string]{}
+	lines := map[string]int{}
 	for _, section := range document.Sections {
 		for _, property := range section.Properties {
 			valuePaths.Insert(property.Path.PatternString())
+			if _, ok := lines[property.Path.PatternString()]; !ok {
+				lines[property.Path.PatternString()] = property.Line
+			}
 		}
 	}
 	valuePaths = sets.RemovePrefixes(valuePaths)
 
-	exceptionStrings := []string{}
-	if exceptionsPath != "" {
-		exceptionsPathsRaw, err := os.ReadFile(exceptionsPath)
-		if err != nil {
-			return err
-		}
-
-		exceptionStrings = strings.Split(string(exceptionsPathsRaw), "\n")
+	exceptionStrings, err := loadExceptions(exceptionsPath)
+	if err != nil {
+		return nil, err
 	}
 
 	missingValues, missingTemplates := DiffPaths(valuePaths, templatePaths)
 
-	succeeded := true
-	for missingValue := range missingValues {
-		exceptionString := fmt.Sprintf("value missing from values.yaml: %s", missingValue)
-
-		if !slices.Contains(exceptionStrings, exceptionString) {
-			fmt.Println(exceptionString)
-			succeeded = false
+	var issues []Issue
+	report := func(line int, message string) {
+		if !slices.Contains(exceptionStrings, message) {
+			issues = append(issues, Issue{Line: line, Message: message})
 		}
 	}
 
+	for _, missing := range MissingReferences(document) {
+		report(missing.Line, fmt.Sprintf("see reference to missing value: %s -> %s", missing.Path, missing.Reference))
+	}
+
+	for missingValue := range missingValues {
+		report(0, fmt.Sprintf("value missing from values.yaml: %s", missingValue))
+	}
+
 	for missingTemplate := range missingTemplates {
-		exceptionString := fmt.Sprintf("value missing from templates: %s", missingTemplate)
+		report(lines[missingTemplate], fmt.Sprintf("value missing from templates: %s", missingTemplate))
+	}
 
-		if !slices.Contains(exceptionStrings, exceptionString) {
-			fmt.Println(exceptionString)
-			succeeded = false
-		}
+	return issues, nil
+}
+
+// loadExceptions reads the exceptions file, every line contains the output
+// of a lint error that should be ignored.
+func loadExceptions(exceptionsPath string) ([]string, error) {
+	if exceptionsPath == "" {
+		return nil, nil
 	}
 
-	if !succeeded {
-		return fmt.Errorf("values.yaml and templates are not in sync")
+	exceptionsPathsRaw, err := os.ReadFile(exceptionsPath)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return strings.Split(string(exceptionsPathsRaw), "\n"), nil
 }
@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+
+	"github.com/cert-manager/helm-tool/internal/shellwords"
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/cert-manager/helm-tool/render"
+)
+
+// Violation is a value that does not comply with a policy, eg. "resources
+// must be set for every container" or "hostNetwork must not default to true".
+type Violation struct {
+	// Path is the path of the property that violates the policy, it is empty
+	// for violations that are not about a single property.
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// Policy checks the documented values and their defaults against rules of the
+// chart maintainers. Programs using helm-tool as a library can implement
+// policies in Go, ExecPolicy evaluates policies written in other languages,
+// eg. Rego or CEL.
+type Policy interface {
+	Evaluate(document *parser.Document) ([]Violation, error)
+}
+
+// PolicyFunc is a Policy implemented by a function.
+type PolicyFunc func(document *parser.Document) ([]Violation, error)
+
+func (f PolicyFunc) Evaluate(document *parser.Document) ([]Violation, error) {
+	return f(document)
+}
+
+// ExecPolicy evaluates a policy using an external program, eg.
+// "opa eval --stdin-input --data policy.rego --format raw data.values.deny".
+// The JSON representation of the document (see render.JSONDocument) is piped
+// to its stdin, and it writes a JSON array of violations to its stdout. The
+// violations are either messages or objects with a path and a message.
+type ExecPolicy struct {
+	Command string
+}
+
+func (p ExecPolicy) Evaluate(document *parser.Document) ([]Violation, error) {
+	args, err := shellwords.Split(p.Command)
+	if err != nil {
+		return nil, fmt.Errorf("policy %q: %w", p.Command, err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no policy command provided")
+	}
+
+	input, err := render.MarshalDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("policy %q failed: %w", p.Command, err)
+	}
+
+	violations, err := parseViolations(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("policy %q: %w", p.Command, err)
+	}
+
+	return violations, nil
+}
+
+// Files returns the arguments of the command that are existing files, eg. the
+// policy.rego file of an opa command, so that changes of the policy can be
+// detected. Commands that cannot be parsed have no files, Evaluate reports
+// them.
+func (p ExecPolicy) Files() []string {
+	args, err := shellwords.Split(p.Command)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.Mode().IsRegular() {
+			files = append(files, arg)
+		}
+	}
+
+	return files
+}
+
+// parseViolations parses the output of a policy program, an empty output or
+// null means there are no violations.
+func parseViolations(output []byte) ([]Violation, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(output, &items); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of violations: %w", err)
+	}
+
+	violations := make([]Violation, 0, len(items))
+	for _, item := range items {
+		var violation Violation
+		if err := json.Unmarshal(item, &violation.Message); err != nil {
+			if err := json.Unmarshal(item, &violation); err != nil {
+				return nil, fmt.Errorf("expected a message or an object with a path and a message: %s", item)
+			}
+		}
+
+		violations = append(violations, violation)
+	}
+
+	return violations, nil
+}
+
+// LintPolicies evaluates the policies against the document and returns the
+// violations as issues, issues of properties point to their line in the
+// values file.
+func LintPolicies(document *parser.Document, policies []Policy, exceptionsPath string) ([]Issue, error) {
+	exceptionStrings, err := loadExceptions(exceptionsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := map[string]int{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			lines[property.Path.String()] = property.Line
+		}
+	}
+
+	var issues []Issue
+	for _, policy := range policies {
+		violations, err := policy.Evaluate(document)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, violation := range violations {
+			// Paths are reported in the same form as the paths of the
+			// documentation, eg. podAnnotations["linkerd.io/inject"]
+			if path, err := paths.Parse(violation.Path); err == nil && violation.Path != "" {
+				violation.Path = path.String()
+			}
+
+			message := "policy violation: " + violation.Message
+			if violation.Path != "" {
+				message = fmt.Sprintf("policy violation in %s: %s", violation.Path, violation.Message)
+			}
+
+			if !slices.Contains(exceptionStrings, message) {
+				issues = append(issues, Issue{Line: lines[violation.Path], Message: message})
+			}
+		}
+	}
+
+	return issues, nil
+}
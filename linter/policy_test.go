@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+const policyValues = `# Run the pods in the host network
+hostNetwork: true
+# The resources of the pods
+resources: {}
+`
+
+func TestLintPolicies(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(policyValues), t.TempDir(), false)
+	require.NoError(t, err)
+
+	hostNetwork := PolicyFunc(func(document *parser.Document) ([]Violation, error) {
+		var violations []Violation
+		for _, property := range document.Sections[0].Properties {
+			if property.Path.String() == "hostNetwork" && property.Default == "true" {
+				violations = append(violations, Violation{Path: "hostNetwork", Message: "must not default to true"})
+			}
+		}
+		return violations, nil
+	})
+
+	// The external policy receives the JSON document on stdin
+	dir := t.TempDir()
+	script := filepath.Join(dir, "policy.sh")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+if grep -q '"path": "resources"' -; then
+  echo '[{"path": "resources", "message": "must be set"}, "chart has a violation"]'
+fi
+`), 0o755))
+
+	issues, err := LintPolicies(document, []Policy{hostNetwork, ExecPolicy{Command: "sh " + script}}, "")
+	require.NoError(t, err)
+	require.Equal(t, []Issue{
+		{Line: 2, Message: "policy violation in hostNetwork: must not default to true"},
+		{Line: 4, Message: "policy violation in resources: must be set"},
+		{Message: "policy violation: chart has a violation"},
+	}, issues)
+
+	exceptions := filepath.Join(dir, "exceptions.txt")
+	require.NoError(t, os.WriteFile(exceptions, []byte("policy violation: chart has a violation\n"), 0o644))
+	issues, err = LintPolicies(document, []Policy{ExecPolicy{Command: "sh " + script}}, exceptions)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+
+	_, err = LintPolicies(document, []Policy{ExecPolicy{Command: "false"}}, "")
+	require.Error(t, err)
+}
+
+func TestParseViolations(t *testing.T) {
+	violations, err := parseViolations([]byte("\n"))
+	require.NoError(t, err)
+	require.Empty(t, violations)
+
+	violations, err = parseViolations([]byte(`["a", {"path": "b", "message": "c"}]`))
+	require.NoError(t, err)
+	require.Equal(t, []Violation{{Message: "a"}, {Path: "b", Message: "c"}}, violations)
+
+	_, err = parseViolations([]byte(`{"message": "a"}`))
+	require.Error(t, err)
+
+	_, err = parseViolations([]byte(`[1]`))
+	require.Error(t, err)
+}
+
+func TestExecPolicyFiles(t *testing.T) {
+	dir := t.TempDir()
+	policy := filepath.Join(dir, "policy.rego")
+	require.NoError(t, os.WriteFile(policy, []byte("package values\n"), 0644))
+
+	files := ExecPolicy{Command: "opa eval --stdin-input --data " + policy + " --format raw data.values.deny"}.Files()
+	require.Equal(t, []string{policy}, files)
+
+	// Directories are not files of the policy
+	require.Empty(t, ExecPolicy{Command: "conftest test " + dir}.Files())
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+var (
+	// codeSpanExp matches inline code spans, these are where value paths are
+	// usually mentioned in prose.
+	codeSpanExp = regexp.MustCompile("`([^`\\s]+)`")
+
+	// valuePathExp matches code spans that look like a value path, eg.
+	// "webhook.replicas", ".Values.image.tag" or "extraArgs[0]".
+	valuePathExp = regexp.MustCompile(`^(?:\$?\.Values\.)?[A-Za-z_][\w-]*(?:\.[A-Za-z_][\w-]*|\[\d+\])*$`)
+)
+
+// ReadmeReference is a reference to a value in a readme.
+type ReadmeReference struct {
+	Path string
+	Line int
+}
+
+// StaleReadmeReferences returns the value paths mentioned in code spans in the
+// readme that do not exist in the document. The injected region (between the
+// header and footer match) and fenced code blocks are skipped. To avoid
+// reporting unrelated code spans (eg. file names), only paths that start with
+// a top-level value are considered. Every path is only reported once.
+func StaleReadmeReferences(readme []byte, headerMatch, footerMatch *regexp.Regexp, document *parser.Document) []ReadmeReference {
+	var allPaths []paths.Path
+	topLevel := map[string]bool{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			allPaths = append(allPaths, property.Path)
+			if len(property.Path) > 0 {
+				topLevel[paths.SegmentString(property.Path[0])] = true
+			}
+		}
+	}
+
+	var stale []ReadmeReference
+	reported := map[string]bool{}
+	for _, line := range proseLines(readme, headerMatch, footerMatch) {
+		for _, match := range codeSpanExp.FindAllStringSubmatch(line.text, -1) {
+			reference := match[1]
+			if !valuePathExp.MatchString(reference) {
+				continue
+			}
+
+			isValuesReference := strings.Contains(reference, ".Values.")
+			reference = strings.TrimPrefix(strings.TrimPrefix(reference, "$"), ".Values.")
+
+			path, err := paths.Parse(reference)
+			if err != nil || len(path) == 0 {
+				continue
+			}
+
+			// Single words and unknown top-level names are most likely not
+			// value paths, unless they are explicit .Values references
+			if !isValuesReference && (len(path) == 1 || !topLevel[paths.SegmentString(path[0])]) {
+				continue
+			}
+
+			if !referenceExists(reference, allPaths) && !reported[reference] {
+				reported[reference] = true
+				stale = append(stale, ReadmeReference{Path: reference, Line: line.number})
+			}
+		}
+	}
+
+	return stale
+}
+
+type proseLine struct {
+	number int
+	text   string
+}
+
+// proseLines returns the lines of the readme outside of the injected region
+// and outside of fenced code blocks.
+func proseLines(readme []byte, headerMatch, footerMatch *regexp.Regexp) []proseLine {
+	content := string(readme)
+	start, end := len(content), len(content)
+	if startIdx := headerMatch.FindStringIndex(content); startIdx != nil {
+		start = startIdx[1]
+		if endIdx := footerMatch.FindStringIndex(content[start:]); endIdx != nil {
+			end = start + endIdx[0]
+		}
+	}
+
+	var lines []proseLine
+	inFence := false
+	offset := 0
+	for i, line := range strings.Split(content, "\n") {
+		lineStart, lineEnd := offset, offset+len(line)
+		offset = lineEnd + 1
+
+		if lineEnd > start && lineStart < end {
+			continue
+		}
+
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+
+		if !inFence {
+			lines = append(lines, proseLine{number: i + 1, text: line})
+		}
+	}
+
+	return lines
+}
+
+// LintReadme returns the stale value references in the hand-written parts of
+// the readme that are not listed in the exceptions file.
+func LintReadme(
+	readmePath string,
+	headerMatch, footerMatch *regexp.Regexp,
+	exceptionsPath string,
+	document *parser.Document,
+) ([]Issue, error) {
+	readme, err := os.ReadFile(readmePath)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptionStrings, err := loadExceptions(exceptionsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, reference := range StaleReadmeReferences(readme, headerMatch, footerMatch, document) {
+		message := fmt.Sprintf("readme reference to missing value: %s", reference.Path)
+
+		if !slices.Contains(exceptionStrings, message) {
+			issues = append(issues, Issue{File: readmePath, Line: reference.Line, Message: message})
+		}
+	}
+
+	return issues, nil
+}
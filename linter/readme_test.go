@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleReadmeReferences(t *testing.T) {
+	document := &parser.Document{Sections: []parser.Section{{
+		Properties: []parser.Property{
+			{Path: mustParse(t, "image.tag")},
+			{Path: mustParse(t, "webhook.replicas")},
+		},
+	}}}
+
+	readme := "# Chart\n" +
+		"Set `image.tag` or `webhook.replicas`, `image.digest` was removed.\n" +
+		"Templates use `.Values.webhook.enabled` and `.Values.replicaCount`.\n" +
+		"Edit `values.yaml` and `Chart.yaml`, see `image` and `webhook.replicas.extra`.\n" +
+		"```\n" +
+		"`image.fenced`\n" +
+		"```\n" +
+		"## Parameters\n" +
+		"`image.injected`\n" +
+		"## Other\n" +
+		"`image.digest` again\n"
+
+	require.Equal(t, []ReadmeReference{
+		{Path: "image.digest", Line: 2},
+		{Path: "webhook.enabled", Line: 3},
+		{Path: "replicaCount", Line: 3},
+		{Path: "webhook.replicas.extra", Line: 4},
+	}, StaleReadmeReferences([]byte(readme), regexp.MustCompile(`(?m)^##\s+Parameters *$`), regexp.MustCompile(`(?m)^##?\s+.*$`), document))
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// MissingReference is a +docs:see reference that does not point to a property
+// in the document.
+type MissingReference struct {
+	// Path is the path of the property containing the reference.
+	Path string
+	// Line is the line of the property in the values file.
+	Line int
+	// Reference is the path the reference points to.
+	Reference string
+}
+
+// MissingReferences returns the +docs:see references that do not point to a
+// property in the document, sorted by the path of the property containing
+// them and the reference. A reference to an object that contains documented
+// properties is valid.
+func MissingReferences(document *parser.Document) []MissingReference {
+	var allPaths []paths.Path
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			allPaths = append(allPaths, property.Path)
+		}
+	}
+
+	var missing []MissingReference
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			for _, reference := range property.SeeAlso() {
+				if !referenceExists(reference, allPaths) {
+					missing = append(missing, MissingReference{
+						Path:      property.Path.String(),
+						Line:      property.Line,
+						Reference: reference,
+					})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(missing, func(a, b MissingReference) int {
+		if c := cmp.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Reference, b.Reference)
+	})
+
+	return missing
+}
+
+func referenceExists(reference string, allPaths []paths.Path) bool {
+	referencePath, err := paths.Parse(reference)
+	if err != nil {
+		return false
+	}
+
+	for _, path := range allPaths {
+		if referencePath.IsSubPathOf(path) {
+			return true
+		}
+	}
+
+	return false
+}
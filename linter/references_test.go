@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissingReferences(t *testing.T) {
+	property := func(path string, see ...string) parser.Property {
+		p := parser.Property{Path: mustParse(t, path)}
+		for _, s := range see {
+			p.Description.Tags.Push("+docs:see=" + s)
+		}
+		return p
+	}
+
+	document := &parser.Document{Sections: []parser.Section{{
+		Properties: []parser.Property{
+			property("c", "a.b", "a["),
+			property("a.b", "c", "a", "missing", "a.b.c"),
+		},
+	}}}
+
+	require.Equal(t, []MissingReference{
+		{Path: "a.b", Reference: "a.b.c"},
+		{Path: "a.b", Reference: "missing"},
+		{Path: "c", Reference: "a["},
+	}, MissingReferences(document))
+}
+
+func mustParse(t *testing.T, path string) paths.Path {
+	p, err := paths.Parse(path)
+	require.NoError(t, err)
+	return p
+}
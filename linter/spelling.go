@@ -0,0 +1,315 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/linter/sets"
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// SystemDictionary is the word list that is used for spell-checking if no
+// dictionary is provided, it is available on most Linux and macOS systems.
+const SystemDictionary = "/usr/share/dict/words"
+
+var (
+	// nonProseExp matches the parts of a description that are not prose:
+	// code spans, URLs and the targets of Markdown links.
+	nonProseExp = regexp.MustCompile("`[^`]*`|\\bhttps?://\\S+|\\]\\([^)]*\\)")
+
+	// wordExp matches the words of a description, including apostrophes and
+	// digits so identifiers such as "v1" are matched as a whole.
+	wordExp = regexp.MustCompile(`[\p{L}\p{N}_'’]+`)
+)
+
+// commonMisspellings maps frequent misspellings to their correction, these
+// are reported even without a dictionary.
+var commonMisspellings = map[string]string{
+	"accessable":    "accessible",
+	"accross":       "across",
+	"acheive":       "achieve",
+	"adress":        "address",
+	"availabe":      "available",
+	"begining":      "beginning",
+	"beleive":       "believe",
+	"certficate":    "certificate",
+	"cerificate":    "certificate",
+	"comming":       "coming",
+	"commited":      "committed",
+	"compatability": "compatibility",
+	"configration":  "configuration",
+	"conifguration": "configuration",
+	"defualt":       "default",
+	"definately":    "definitely",
+	"dependancies":  "dependencies",
+	"dependancy":    "dependency",
+	"enviroment":    "environment",
+	"existance":     "existence",
+	"explicitely":   "explicitly",
+	"finaly":        "finally",
+	"garantee":      "guarantee",
+	"independant":   "independent",
+	"intial":        "initial",
+	"namesapce":     "namespace",
+	"neccessary":    "necessary",
+	"necesary":      "necessary",
+	"occured":       "occurred",
+	"occurence":     "occurrence",
+	"overriden":     "overridden",
+	"paramter":      "parameter",
+	"paramters":     "parameters",
+	"persistant":    "persistent",
+	"posible":       "possible",
+	"prefered":      "preferred",
+	"privledge":     "privilege",
+	"reccomend":     "recommend",
+	"recieve":       "receive",
+	"recieved":      "received",
+	"recomend":      "recommend",
+	"refered":       "referred",
+	"refrence":      "reference",
+	"retreive":      "retrieve",
+	"seperate":      "separate",
+	"seperately":    "separately",
+	"specfied":      "specified",
+	"succesful":     "successful",
+	"successfull":   "successful",
+	"sucess":        "success",
+	"supress":       "suppress",
+	"teh":           "the",
+	"tempalte":      "template",
+	"thier":         "their",
+	"threshhold":    "threshold",
+	"truely":        "truly",
+	"untill":        "until",
+	"usefull":       "useful",
+	"varaible":      "variable",
+	"verison":       "version",
+	"wether":        "whether",
+	"wich":          "which",
+	"writting":      "writing",
+}
+
+// LoadDictionary reads word lists with one word per line, eg. the system
+// dictionary and a per-repository list of project words such as "cainjector"
+// and "ACME". Empty lines and lines starting with "#" are ignored, words are
+// matched case-insensitively.
+func LoadDictionary(filenames ...string) (sets.Set[string], error) {
+	dictionary := sets.New[string]()
+
+	for _, filename := range filenames {
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range strings.Split(string(contents), "\n") {
+			word := strings.TrimSpace(line)
+			if word == "" || strings.HasPrefix(word, "#") {
+				continue
+			}
+
+			dictionary.Insert(strings.ToLower(word))
+		}
+	}
+
+	return dictionary, nil
+}
+
+// Misspelling is a word in a description that is likely misspelled.
+type Misspelling struct {
+	// Location is the property path or the section the description belongs
+	// to, eg. `webhook.replicas` or `section "Webhook"`.
+	Location string
+	Line     int
+	Word     string
+	// Suggestion is the likely correct spelling, it is empty if unknown.
+	Suggestion string
+}
+
+// FindMisspellings returns the misspelled words in the descriptions of the
+// sections and properties of the document. Common misspellings are always
+// reported, other words are only reported if the dictionary is not empty and
+// does not contain them. Code spans, URLs, identifiers (words containing
+// digits, underscores or upper case letters after the first letter) and the
+// keys of the values are never reported.
+func FindMisspellings(document *parser.Document, dictionary sets.Set[string]) []Misspelling {
+	keys := sets.New[string]()
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			for _, segment := range property.Path {
+				keys.Insert(strings.ToLower(paths.SegmentString(segment)))
+			}
+		}
+	}
+
+	// Misspelled words are often keys, eg. a misspelled component name
+	candidates := sets.Union(dictionary, keys)
+
+	var result []Misspelling
+	check := func(location string, line int, description parser.Comment) {
+		reported := sets.New[string]()
+		for _, segment := range description.Segments {
+			if segment.Type != heuristics.ContentTypeText {
+				continue
+			}
+
+			text := nonProseExp.ReplaceAllString(segment.String(), " ")
+			for _, word := range wordExp.FindAllString(text, -1) {
+				word = strings.TrimRight(strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "’s"), "'’")
+				lower := strings.ToLower(word)
+				if isIdentifier(word) || keys.Has(lower) || reported.Has(lower) {
+					continue
+				}
+
+				suggestion, misspelled := commonMisspellings[lower]
+				if !misspelled && len(dictionary) > 0 && len(lower) > 2 && !inDictionary(dictionary, lower) {
+					suggestion, misspelled = suggestWord(candidates, lower), true
+				}
+
+				if misspelled {
+					reported.Insert(lower)
+					result = append(result, Misspelling{Location: location, Line: line, Word: word, Suggestion: suggestion})
+				}
+			}
+		}
+	}
+
+	for _, section := range document.Sections {
+		if section.Name != "" {
+			check(fmt.Sprintf("section %q", section.Name), 0, section.Description)
+		}
+
+		for _, property := range section.Properties {
+			check(property.Path.String(), property.Line, property.Description)
+		}
+	}
+
+	return result
+}
+
+// LintSpelling returns the misspelled words in the descriptions of the
+// document (see FindMisspellings) that are not listed in the exceptions file.
+func LintSpelling(document *parser.Document, dictionary sets.Set[string], exceptionsPath string) ([]Issue, error) {
+	exceptionStrings, err := loadExceptions(exceptionsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, misspelling := range FindMisspellings(document, dictionary) {
+		message := fmt.Sprintf("misspelled word in description of %s: %q", misspelling.Location, misspelling.Word)
+		if misspelling.Suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", misspelling.Suggestion)
+		}
+
+		if !slices.Contains(exceptionStrings, message) {
+			issues = append(issues, Issue{Line: misspelling.Line, Message: message})
+		}
+	}
+
+	return issues, nil
+}
+
+// isIdentifier returns whether the word looks like an identifier or acronym
+// rather than an English word, eg. "v1", "imagePullSecrets" or "TLS".
+func isIdentifier(word string) bool {
+	for i, r := range word {
+		if unicode.IsDigit(r) || r == '_' || (i > 0 && unicode.IsUpper(r)) {
+			return true
+		}
+	}
+
+	return len(word) < 2
+}
+
+// inDictionary returns whether the word, or its singular form, is in the
+// dictionary.
+func inDictionary(dictionary sets.Set[string], word string) bool {
+	if dictionary.Has(word) {
+		return true
+	}
+
+	for _, suffix := range []string{"s", "es"} {
+		if stem, ok := strings.CutSuffix(word, suffix); ok && dictionary.Has(stem) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suggestWord returns the candidate closest to the word, if it is close enough
+// to likely be a typo.
+func suggestWord(candidates sets.Set[string], word string) string {
+	if len(word) < 4 {
+		return ""
+	}
+
+	suggestion, best := "", 3
+	for candidate := range candidates {
+		if abs(len(candidate)-len(word)) >= best {
+			continue
+		}
+
+		distance := editDistance(word, candidate)
+		if distance < best || (distance == best && candidate < suggestion) {
+			suggestion, best = candidate, distance
+		}
+	}
+
+	return suggestion
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/linter/sets"
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+const spellingValues = `# +docs:section=Webhook
+# Settings of the webhok.
+
+webhook:
+  # The numbr of replicas, see ` + "`webhook.replicaz`" + ` and https://exampel.com.
+  # Passed to the ACME server as imagePullSecrets and v1 resources.
+  replicas: 1
+
+cainjector:
+  # Teh cainjector's settings are seperate.
+  enabled: true
+`
+
+func TestLintSpelling(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(spellingValues), t.TempDir(), false)
+	require.NoError(t, err)
+
+	// Without a dictionary only common misspellings are reported
+	misspellings := FindMisspellings(document, nil)
+	require.Equal(t, []Misspelling{
+		{Location: "cainjector.enabled", Line: 11, Word: "Teh", Suggestion: "the"},
+		{Location: "cainjector.enabled", Line: 11, Word: "seperate", Suggestion: "separate"},
+	}, misspellings)
+
+	dir := t.TempDir()
+	words := filepath.Join(dir, "words")
+	require.NoError(t, os.WriteFile(words, []byte("the\nsetting\nof\nnumber\nreplica\nsee\nand\npassed\nto\nserver\nas\nresource\nare\n"), 0644))
+	project := filepath.Join(dir, "project")
+	require.NoError(t, os.WriteFile(project, []byte("# Project words\nACME\n"), 0644))
+	exceptions := filepath.Join(dir, "exceptions")
+	require.NoError(t, os.WriteFile(exceptions, []byte(`misspelled word in description of cainjector.enabled: "seperate", did you mean "separate"?`+"\n"), 0644))
+
+	dictionary, err := LoadDictionary(words, project)
+	require.NoError(t, err)
+
+	issues, err := LintSpelling(document, dictionary, exceptions)
+	require.NoError(t, err)
+	require.Equal(t, []Issue{
+		{Line: 0, Message: `misspelled word in description of section "Webhook": "webhok", did you mean "webhook"?`},
+		{Line: 7, Message: `misspelled word in description of webhook.replicas: "numbr", did you mean "number"?`},
+		{Line: 11, Message: `misspelled word in description of cainjector.enabled: "Teh", did you mean "the"?`},
+	}, issues)
+}
+
+func TestSuggestWord(t *testing.T) {
+	dictionary := sets.New("certificate", "certificates", "issuer")
+	require.Equal(t, "certificate", suggestWord(dictionary, "certifcate"))
+	require.Equal(t, "", suggestWord(dictionary, "unrelated"))
+	require.Equal(t, "", suggestWord(dictionary, "isr"))
+}
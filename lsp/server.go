@@ -0,0 +1,420 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// Server is a language server for values files. It provides hover
+// documentation, completion of documented paths and diagnostics for the
+// chart's values file, the documentation overrides file and any other yaml
+// file, which is treated as a user's values file.
+type Server struct {
+	// ValuesFile is the chart's values file the documentation is read from.
+	ValuesFile string
+	// OverridesFile is the optional file containing documentation overrides.
+	OverridesFile string
+	// TemplatesFolder is the templates folder used to lint the values file,
+	// linting is skipped if the folder does not exist.
+	TemplatesFolder string
+	// ExceptionsFile is the optional file containing exceptions to the
+	// linting rules.
+	ExceptionsFile string
+	// TagPrefix is the alternative prefix of the tags in the values file, see
+	// parser.Options.
+	TagPrefix string
+	// Dialect is the convention of the documentation comments in the values
+	// file, see parser.Options.
+	Dialect string
+
+	out       io.Writer
+	documents map[string]string
+	shutdown  bool
+}
+
+type fileKind int
+
+const (
+	fileValues fileKind = iota
+	fileOverrides
+	fileUserValues
+)
+
+// Serve handles messages read from in and writes the responses to out, until
+// the client sends the exit notification.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	s.documents = map[string]string{}
+
+	reader := bufio.NewReader(in)
+	for {
+		msg, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit without shutdown")
+			}
+			return nil
+		}
+
+		result, err := s.handle(msg)
+		if msg.ID == nil {
+			// Notifications have no response
+			continue
+		}
+
+		if err != nil {
+			var rpcErr *responseError
+			if !errors.As(err, &rpcErr) {
+				rpcErr = &responseError{Code: errorInvalidParams, Message: err.Error()}
+			}
+
+			if err := writeMessage(out, errorResponse{JSONRPC: "2.0", ID: msg.ID, Error: *rpcErr}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := writeMessage(out, response{JSONRPC: "2.0", ID: msg.ID, Result: result}); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *responseError) Error() string {
+	return e.Message
+}
+
+func (s *Server) handle(msg *message) (any, error) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync":   1, // full
+				"hoverProvider":      true,
+				"completionProvider": map[string]any{},
+			},
+			"serverInfo": map[string]any{"name": "helm-tool"},
+		}, nil
+
+	case "initialized", "$/cancelRequest", "textDocument/didSave":
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+
+		s.documents[params.TextDocument.URI] = params.TextDocument.Text
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+
+		for _, change := range params.ContentChanges {
+			s.documents[params.TextDocument.URI] = change.Text
+		}
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+
+	case "textDocument/didClose":
+		var params didCloseTextDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+
+		delete(s.documents, params.TextDocument.URI)
+		return nil, writeMessage(s.out, notification{
+			JSONRPC: "2.0",
+			Method:  "textDocument/publishDiagnostics",
+			Params:  publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []diagnostic{}},
+		})
+
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return s.hover(params)
+
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+
+		return s.completion(params)
+
+	default:
+		if msg.ID == nil {
+			return nil, nil
+		}
+
+		return nil, &responseError{Code: errorMethodNotFound, Message: fmt.Sprintf("method %q not found", msg.Method)}
+	}
+}
+
+// text returns the contents of the file, using the contents of the editor if
+// the file is open.
+func (s *Server) text(uri string) (string, error) {
+	if text, ok := s.documents[uri]; ok {
+		return text, nil
+	}
+
+	contents, err := os.ReadFile(uriToPath(uri))
+	return string(contents), err
+}
+
+func (s *Server) kind(uri string) fileKind {
+	switch {
+	case samePath(uriToPath(uri), s.ValuesFile):
+		return fileValues
+	case s.OverridesFile != "" && samePath(uriToPath(uri), s.OverridesFile):
+		return fileOverrides
+	default:
+		return fileUserValues
+	}
+}
+
+// document parses the chart's values file and applies the overrides, so the
+// documentation matches the rendered documentation. If the overrides cannot
+// be loaded, the document is returned along with the overrides error, which
+// is reported as a diagnostic.
+func (s *Server) document() (document *parser.Document, overridesErr error, err error) {
+	valuesURI := pathToURI(s.ValuesFile)
+	text, err := s.text(valuesURI)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	document, err = parser.ParseWithOptions(strings.NewReader(text), filepath.Dir(s.ValuesFile), parser.Options{
+		IncludeHidden: true,
+		TagPrefix:     s.TagPrefix,
+		Dialect:       s.Dialect,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.OverridesFile == "" {
+		return document, nil, nil
+	}
+
+	overridesText, err := s.text(pathToURI(s.OverridesFile))
+	if err != nil {
+		return document, err, nil
+	}
+
+	var overrides parser.Overrides
+	if err := unmarshalYAML(overridesText, &overrides); err != nil {
+		return document, err, nil
+	}
+
+	if err := document.ApplyOverrides(overrides); err != nil {
+		return document, err, nil
+	}
+
+	return document, nil, nil
+}
+
+func (s *Server) hover(params textDocumentPositionParams) (any, error) {
+	document, _, err := s.document()
+	if err != nil {
+		return nil, nil
+	}
+
+	text, err := s.text(params.TextDocument.URI)
+	if err != nil {
+		return nil, nil
+	}
+
+	path, ok := s.pathAt(params.TextDocument.URI, text, params.Position.Line)
+	if !ok {
+		return nil, nil
+	}
+
+	property, ok := findProperty(document, path)
+	if !ok {
+		return nil, nil
+	}
+
+	return hover{Contents: markupContent{Kind: "markdown", Value: propertyDocumentation(property)}}, nil
+}
+
+func (s *Server) completion(params textDocumentPositionParams) (any, error) {
+	document, _, err := s.document()
+	if err != nil {
+		return []completionItem{}, nil
+	}
+
+	if s.kind(params.TextDocument.URI) == fileOverrides {
+		// Overrides files are keyed by the full path of the properties
+		var items []completionItem
+		for _, section := range document.Sections {
+			for _, property := range section.Properties {
+				items = append(items, propertyCompletion(property.Path.String(), property))
+			}
+		}
+		return items, nil
+	}
+
+	text, err := s.text(params.TextDocument.URI)
+	if err != nil {
+		return []completionItem{}, nil
+	}
+
+	parent := parentPathAt(strings.Split(text, "\n"), params.Position)
+
+	items := []completionItem{}
+	seen := map[string]bool{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if len(property.Path) <= len(parent) || !parent.IsSubPathOf(property.Path) {
+				continue
+			}
+
+			child := property.Path[:len(parent)+1]
+			if paths.IsArrayPathComponent(child.Property()) {
+				continue
+			}
+
+			name := paths.SegmentString(child.Property())
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			if child.Equal(property.Path) {
+				items = append(items, propertyCompletion(name, property))
+			} else {
+				items = append(items, completionItem{Label: name, Kind: completionItemKindProperty, Detail: "object"})
+			}
+		}
+	}
+
+	return items, nil
+}
+
+func propertyCompletion(label string, property parser.Property) completionItem {
+	return completionItem{
+		Label:         label,
+		Kind:          completionItemKindProperty,
+		Detail:        property.Type.String(),
+		Documentation: &markupContent{Kind: "markdown", Value: propertyDocumentation(property)},
+	}
+}
+
+func (s *Server) publishDiagnostics(uri string) error {
+	uris := []string{uri}
+	if s.kind(uri) == fileValues {
+		// The documentation of the other files depends on the values file
+		uris = nil
+		for openURI := range s.documents {
+			uris = append(uris, openURI)
+		}
+		sort.Strings(uris)
+	}
+
+	for _, uri := range uris {
+		if err := writeMessage(s.out, notification{
+			JSONRPC: "2.0",
+			Method:  "textDocument/publishDiagnostics",
+			Params:  publishDiagnosticsParams{URI: uri, Diagnostics: s.diagnostics(uri)},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func propertyDocumentation(property parser.Property) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s** ~ `%s`", property.DisplayName(), property.Type)
+
+	if description := property.Description.String(); description != "" {
+		fmt.Fprintf(&sb, "\n\n%s", description)
+	}
+
+	if property.Default != "" {
+		fmt.Fprintf(&sb, "\n\nDefault value:\n```yaml\n%s\n```", property.Default)
+	}
+
+	return sb.String()
+}
+
+func findProperty(document *parser.Document, path paths.Path) (parser.Property, bool) {
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if property.Path.Equal(path) {
+				return property, true
+			}
+		}
+	}
+
+	return parser.Property{}, false
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+
+	return filepath.FromSlash(u.Path)
+}
+
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testValues = `image:
+  # The image tag
+  tag: v1.0.0
+  # The pull policy
+  pullPolicy: IfNotPresent
+# The number of replicas
+replicas: 1
+`
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	valuesFile := filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(valuesFile, []byte(testValues), 0644))
+
+	userURI := pathToURI(filepath.Join(dir, "my-values.yaml"))
+
+	var in bytes.Buffer
+	id := 0
+	send := func(method string, params any, isRequest bool) {
+		msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+		if isRequest {
+			id++
+			msg["id"] = id
+		}
+		require.NoError(t, writeMessage(&in, msg))
+	}
+
+	send("initialize", map[string]any{}, true)
+	send("textDocument/didOpen", map[string]any{"textDocument": map[string]any{
+		"uri":  userURI,
+		"text": "image:\n  tag: v2\n  unknown: true\nreplicas: 3\n",
+	}}, false)
+	send("textDocument/hover", map[string]any{"textDocument": map[string]any{"uri": userURI}, "position": map[string]any{"line": 1, "character": 3}}, true)
+	send("textDocument/completion", map[string]any{"textDocument": map[string]any{"uri": userURI}, "position": map[string]any{"line": 2, "character": 2}}, true)
+	send("shutdown", nil, true)
+	send("exit", nil, false)
+
+	var out bytes.Buffer
+	server := Server{ValuesFile: valuesFile, TemplatesFolder: filepath.Join(dir, "templates")}
+	require.NoError(t, server.Serve(&in, &out))
+
+	var messages []map[string]json.RawMessage
+	reader := bufio.NewReader(&out)
+	for {
+		body, err := readBody(reader)
+		if err != nil {
+			break
+		}
+
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(body, &fields))
+		messages = append(messages, fields)
+	}
+
+	// initialize, diagnostics, hover, completion and shutdown
+	require.Len(t, messages, 5)
+
+	var diagnostics publishDiagnosticsParams
+	require.NoError(t, json.Unmarshal(messages[1]["params"], &diagnostics))
+	require.Equal(t, userURI, diagnostics.URI)
+	require.Len(t, diagnostics.Diagnostics, 1)
+	require.Equal(t, 2, diagnostics.Diagnostics[0].Range.Start.Line)
+	require.Contains(t, diagnostics.Diagnostics[0].Message, `"image.unknown"`)
+
+	var hoverResult hover
+	require.NoError(t, json.Unmarshal(messages[2]["result"], &hoverResult))
+	require.Contains(t, hoverResult.Contents.Value, "**image.tag** ~ `string`")
+	require.Contains(t, hoverResult.Contents.Value, "The image tag")
+
+	var items []completionItem
+	require.NoError(t, json.Unmarshal(messages[3]["result"], &items))
+	require.Len(t, items, 2)
+	require.Equal(t, "tag", items[0].Label)
+	require.Equal(t, "pullPolicy", items[1].Label)
+}
+
+func TestParentPathAt(t *testing.T) {
+	lines := []string{
+		"image:",
+		"  # comment",
+		"  registry: docker.io",
+		"  ",
+		"resources:",
+		"  limits:",
+		"    ",
+		"args:",
+		"  - a: b",
+		"    ",
+	}
+
+	require.Equal(t, "image", parentPathAt(lines, position{Line: 3, Character: 2}).String())
+	require.Equal(t, "", parentPathAt(lines, position{Line: 3, Character: 0}).String())
+	require.Equal(t, "resources.limits", parentPathAt(lines, position{Line: 6, Character: 4}).String())
+	require.Equal(t, "[-1]", parentPathAt(lines, position{Line: 9, Character: 4}).String())
+}
+
+func TestOverridesDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	valuesFile := filepath.Join(dir, "values.yaml")
+	overridesFile := filepath.Join(dir, "overrides.yaml")
+	require.NoError(t, os.WriteFile(valuesFile, []byte(testValues), 0644))
+	require.NoError(t, os.WriteFile(overridesFile, []byte("image[:\n  description: The image\n"), 0644))
+
+	server := Server{ValuesFile: valuesFile, OverridesFile: overridesFile}
+	userURI := pathToURI(filepath.Join(dir, "my-values.yaml"))
+	server.documents = map[string]string{userURI: "replicas: 3\n"}
+
+	diagnostics := server.diagnostics(userURI)
+	require.Len(t, diagnostics, 1)
+	require.Contains(t, diagnostics[0].Message, "could not load "+overridesFile)
+	require.Contains(t, diagnostics[0].Message, `"image["`)
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/linter"
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlErrorLineExp matches the line number in yaml.v3 error messages.
+var yamlErrorLineExp = regexp.MustCompile(`line (\d+)`)
+
+type key struct {
+	path paths.Path
+	node *yaml.Node
+}
+
+func unmarshalYAML(text string, v any) error {
+	return yaml.Unmarshal([]byte(text), v)
+}
+
+// keys returns the mapping keys in the yaml file with their path. If nested is
+// false, only the keys of the top-level mapping are returned, with the key
+// itself parsed as path (this is the format of the overrides file).
+func keys(text string, nested bool) ([]key, error) {
+	var root yaml.Node
+	if err := unmarshalYAML(text, &root); err != nil {
+		return nil, err
+	}
+
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	if !nested {
+		var result []key
+		mapping := root.Content[0]
+		if mapping.Kind != yaml.MappingNode {
+			return nil, nil
+		}
+
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			path, err := paths.Parse(mapping.Content[i].Value)
+			if err != nil {
+				continue
+			}
+			result = append(result, key{path: path, node: mapping.Content[i]})
+		}
+
+		return result, nil
+	}
+
+	var result []key
+	var walk func(node *yaml.Node, path paths.Path)
+	walk = func(node *yaml.Node, path paths.Path) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				childPath := path.WithProperty(node.Content[i].Value)
+				result = append(result, key{path: childPath, node: node.Content[i]})
+				walk(node.Content[i+1], childPath)
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				walk(child, path.WithIndex(i))
+			}
+		}
+	}
+	walk(root.Content[0], paths.Path{})
+
+	return result, nil
+}
+
+// pathAt returns the path of the key on the line (starting at 0).
+func (s *Server) pathAt(uri string, text string, line int) (paths.Path, bool) {
+	fileKeys, err := keys(text, s.kind(uri) != fileOverrides)
+	if err != nil {
+		return nil, false
+	}
+
+	for _, k := range fileKeys {
+		if k.node.Line == line+1 {
+			return k.path, true
+		}
+	}
+
+	return nil, false
+}
+
+// parentPathAt returns the path of the mapping a key typed at the position
+// would be added to, based on the indentation of the preceding lines.
+// Sequences are not supported, in that case the returned path matches no
+// properties.
+func parentPathAt(lines []string, pos position) paths.Path {
+	if pos.Line >= len(lines) {
+		return paths.Path{}
+	}
+
+	prefix := lines[pos.Line][:min(pos.Character, len(lines[pos.Line]))]
+	indent := len(prefix) - len(strings.TrimLeft(prefix, " "))
+
+	var names []string
+	for l := pos.Line - 1; l >= 0 && indent > 0; l-- {
+		trimmed := strings.TrimLeft(lines[l], " ")
+		if strings.TrimSpace(trimmed) == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		lineIndent := len(lines[l]) - len(trimmed)
+		if lineIndent >= indent {
+			continue
+		}
+
+		name, _, ok := strings.Cut(trimmed, ":")
+		if !ok || strings.HasPrefix(trimmed, "-") {
+			return paths.Path{}.WithIndex(-1)
+		}
+
+		names = append([]string{strings.Trim(name, `"' `)}, names...)
+		indent = lineIndent
+	}
+
+	path := paths.Path{}
+	for _, name := range names {
+		path = path.WithProperty(name)
+	}
+
+	return path
+}
+
+func (s *Server) diagnostics(uri string) []diagnostic {
+	diagnostics := []diagnostic{}
+	report := func(line int, severity int, message string) {
+		line = max(line-1, 0)
+		diagnostics = append(diagnostics, diagnostic{
+			Range:    textRange{Start: position{Line: line}, End: position{Line: line, Character: 1 << 16}},
+			Severity: severity,
+			Source:   "helm-tool",
+			Message:  message,
+		})
+	}
+
+	text, err := s.text(uri)
+	if err != nil {
+		return diagnostics
+	}
+
+	kind := s.kind(uri)
+	fileKeys, err := keys(text, kind != fileOverrides)
+	if err != nil {
+		line := 0
+		if match := yamlErrorLineExp.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+		}
+		report(line, severityError, err.Error())
+		return diagnostics
+	}
+
+	document, overridesErr, err := s.document()
+	if err != nil {
+		if kind != fileValues {
+			report(0, severityError, fmt.Sprintf("could not load %s: %s", s.ValuesFile, err))
+		}
+		return diagnostics
+	}
+
+	// Syntax errors of the overrides file are reported above
+	if overridesErr != nil && kind != fileOverrides {
+		report(0, severityError, fmt.Sprintf("could not load %s: %s", s.OverridesFile, overridesErr))
+	}
+
+	switch kind {
+	case fileValues:
+		if _, err := os.Stat(s.TemplatesFolder); err != nil {
+			break
+		}
+
+		issues, err := linter.Lint(s.TemplatesFolder, s.ExceptionsFile, document)
+		if err != nil {
+			report(0, severityError, err.Error())
+			break
+		}
+
+		for _, issue := range issues {
+			report(issue.Line, severityWarning, issue.Message)
+		}
+
+	case fileOverrides:
+		for _, k := range fileKeys {
+			if _, ok := findProperty(document, k.path); !ok {
+				report(k.node.Line, severityWarning, fmt.Sprintf("override for %q does not match any property", k.path))
+			}
+		}
+
+	case fileUserValues:
+		var unknown []paths.Path
+		for _, k := range fileKeys {
+			if isUnder(k.path, unknown) {
+				continue
+			}
+
+			if !isDocumented(document, k.path) {
+				unknown = append(unknown, k.path)
+				report(k.node.Line, severityWarning, fmt.Sprintf("value %q is not a documented value of %s", k.path, s.ValuesFile))
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// isDocumented returns whether the path is a documented property, the parent
+// of one, or is part of one (eg. an item of a documented array).
+func isDocumented(document *parser.Document, path paths.Path) bool {
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if path.IsSubPathOf(property.Path) || property.Path.IsSubPathOf(path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isUnder(path paths.Path, parents []paths.Path) bool {
+	for _, parent := range parents {
+		if parent.IsSubPathOf(path) {
+			return true
+		}
+	}
+
+	return false
+}
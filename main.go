@@ -17,81 +17,330 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/cert-manager/helm-tool/cache"
+	"github.com/cert-manager/helm-tool/completion"
+	"github.com/cert-manager/helm-tool/config"
+	"github.com/cert-manager/helm-tool/diff"
+	"github.com/cert-manager/helm-tool/editor"
+	"github.com/cert-manager/helm-tool/formatter"
+	"github.com/cert-manager/helm-tool/helmfile"
+	"github.com/cert-manager/helm-tool/internal/git"
+	"github.com/cert-manager/helm-tool/internal/pager"
+	"github.com/cert-manager/helm-tool/internal/version"
 	"github.com/cert-manager/helm-tool/linter"
+	"github.com/cert-manager/helm-tool/lsp"
 	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
 	"github.com/cert-manager/helm-tool/render"
 	"github.com/cert-manager/helm-tool/schema"
+	"github.com/cert-manager/helm-tool/summary"
+	"github.com/cert-manager/helm-tool/telemetry"
+	"github.com/cert-manager/helm-tool/timings"
 	"github.com/spf13/cobra"
 )
 
 var (
-	valuesFile      string
-	templatesFolder string
-	exceptionsFile  string
-	targetFile      string
-	templateName    string
-	headerSearch    = regexValue{regexp.MustCompile(`(?m)^##\s+Parameters *$`)}
-	footerSearch    = regexValue{regexp.MustCompile(`(?m)^##?\s+.*$`)}
+	valuesFile       string
+	overridesFile    string
+	ownersFile       string
+	configFile       string
+	templatesFolder  string
+	exceptionsFile   string
+	targetFile       string
+	templateName     string
+	audience         string
+	sections         []string
+	renderOptions    render.Options
+	formatOptions    formatter.Options
+	formatWrite      bool
+	formatCheck      bool
+	syncSources      []string
+	dryRun           bool
+	updateTemplates  bool
+	readmeFile       string
+	summaryFile      string
+	provenance       bool
+	provenanceTime   bool
+	createTarget     bool
+	chartHeader      bool
+	chartRepo        string
+	verifyIdempotent bool
+	strictTags       bool
+	unknownType      string
+	strictTypes      bool
+	tagPrefix        string
+	dialect          string
+	migrateFrom      string
+	migrateTo        string
+	schemaFile       string
+	cacheDir         string
+	sinceRef         string
+	blameLink        string
+	userValuesFile   string
+	idsFile          string
+	redirectFormat   string
+	indexDocs        string
+	indexTitle       string
+	indexOutput      string
+	siteDir          string
+	mkdocsDir        string
+	comparePaths     []string
+	postRender       []string
+	siteTitle        string
+	navPrefix        string
+	outputDir        string
+	fileNameTemplate string
+	navFile          string
+	textWidth        int
+	textColor        string
+	pagerCommand     string
+	printTemplate    bool
+	useSample        bool
+	spelling         bool
+	dictionaries     []string
+	policies         []string
+	environment      string
+	completionData   string
+	lintValues       bool
+	outputFormat     string
+	profile          string
+	schemaOptions    schema.Options
+	runSummary       = summary.New("")
+	debugTimings     bool
+	runTimings       *timings.Timings
+	headerSearch     = regexValue{regexp.MustCompile(`(?m)^##\s+Parameters *$`)}
+	footerSearch     = regexValue{regexp.MustCompile(`(?m)^##?\s+.*$`)}
 )
 
+// helmDocsHeaderSearch matches the "## Values" header of READMEs generated by
+// helm-docs, it is the default header of the helm-docs dialect.
+var helmDocsHeaderSearch = regexp.MustCompile(`(?m)^##\s+Values *$`)
+
+// exitCodeReadOnly is the exit code of inject if the target file is read-only.
+const exitCodeReadOnly = 3
+
 var Cmd = cobra.Command{
 	Use: "helm-tool",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		runSummary = summary.New(cmd.Name())
+		if debugTimings {
+			runTimings = timings.New()
+		}
+		log.SetOutput(runSummary.WarningWriter(os.Stderr))
+
+		cfg, err := config.Load(configFile, !cmd.Flags().Changed("config"))
+		if err != nil {
+			return fmt.Errorf("could not load config %q: %w", configFile, err)
+		}
+
+		if profile != "" {
+			if cfg, err = cfg.WithProfile(profile); err != nil {
+				return fmt.Errorf("could not load config %q: %w", configFile, err)
+			}
+		}
+
+		return applyConfig(cmd, cfg)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		writeTimings()
+		writeSummary(0)
+	},
 }
 
 var Render = cobra.Command{
 	Use:   "render",
 	Short: "render documentation to stdout",
 	Run: func(cmd *cobra.Command, args []string) {
-		document, err := parser.Load(valuesFile, false)
+		document, err := loadDocument(false)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
-			os.Exit(1)
+			exit(1)
+		}
+
+		document, err = document.ForSections(sections)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not select sections: %s\n", err)
+			exit(1)
+		}
+
+		if outputDir != "" {
+			renderSections(document)
+			return
 		}
 
-		result, err := render.Render(templateName, document)
+		stop := runTimings.Start("render")
+		result, err := render.RenderWithOptions(templateName, document.ForAudience(audience), renderOptions)
+		stop()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error rendering template: %s\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Println(result)
 	},
 }
 
+// renderSections renders every section of the document to its own file in
+// the output directory, using the render flags.
+func renderSections(document *parser.Document) {
+	// The directory is created first, the post-render commands run in it
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not create %q: %s\n", outputDir, err)
+		exit(1)
+	}
+
+	stop := runTimings.Start("render")
+	files, err := render.RenderSections(fileNameTemplate, templateName, document.ForAudience(audience), renderOptions)
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering template: %s\n", err)
+		exit(1)
+	}
+
+	for _, file := range files {
+		path := filepath.Join(outputDir, file.FileName)
+		if err := writeFile(path, []byte(file.Contents+"\n")); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", path, err)
+			exit(1)
+		}
+	}
+}
+
+var Show = cobra.Command{
+	Use:   "show",
+	Short: "show the documentation in the terminal",
+	Long: `Show the documentation as plain text in the terminal: a line per value with its path, type and default in
+fixed-width columns, followed by its description wrapped to the width of the terminal. Types and deprecated values
+are highlighted using colors when writing to a terminal (--color).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		document, err = document.ForSections(sections)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not select sections: %s\n", err)
+			exit(1)
+		}
+
+		render.WriteText(os.Stdout, document.ForAudience(audience), textOptions())
+	},
+}
+
+var View = cobra.Command{
+	Use:   "view",
+	Short: "view the documentation in a pager",
+	Long: `View the documentation in the terminal like show, piped into a pager (like git log) in which / searches. The
+pager is --pager, $PAGER or less, which is run with LESS=FRX unless $LESS is set so colors are shown and it exits
+if the documentation fits on the screen. When not writing to a terminal, the documentation is written directly.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		document, err = document.ForSections(sections)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not select sections: %s\n", err)
+			exit(1)
+		}
+
+		document = document.ForAudience(audience)
+		options := textOptions()
+		command := pagerCommand
+		if !isTerminal(os.Stdout) {
+			command = ""
+		}
+
+		if err := pager.Run(command, os.Stdout, func(w io.Writer) { render.WriteText(w, document, options) }); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not view the documentation: %s\n", err)
+			exit(1)
+		}
+	},
+}
+
+// textOptions returns the options of the plain-text output of the show and
+// view commands, colors are used when writing to a terminal by default.
+func textOptions() render.TextOptions {
+	options := render.TextOptions{Width: textWidth}
+	if options.Width == 0 {
+		options.Width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+
+	switch textColor {
+	case "auto":
+		options.Color = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	case "always":
+		options.Color = true
+	case "never":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown color mode %q\n", textColor)
+		exit(1)
+	}
+
+	return options
+}
+
+// isTerminal returns whether the file is a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 var Inject = cobra.Command{
 	Use:   "inject",
 	Short: "generate documentation and inject into existing markdown file",
 	Run: func(cmd *cobra.Command, args []string) {
-		document, err := parser.Load(valuesFile, false)
+		document, err := loadDocument(false)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
-			os.Exit(1)
+			exit(1)
 		}
 
-		if err := render.Inject(targetFile, templateName, document, headerSearch.regexp, footerSearch.regexp); err != nil {
-			fmt.Fprintf(os.Stderr, "Could inject markdown into %q: %s\n", targetFile, err)
-			os.Exit(1)
+		document, err = document.ForSections(sections)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not select sections: %s\n", err)
+			exit(1)
 		}
+
+		injectDocumentation(document)
 	},
 }
 
 var Schema = cobra.Command{
 	Use: "schema",
 	Run: func(cmd *cobra.Command, args []string) {
-		document, err := parser.Load(valuesFile, true)
+		document, err := loadDocument(true)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
-			os.Exit(1)
+			exit(1)
 		}
 
-		renderedSchema, err := schema.Render(document)
+		stop := runTimings.Start("schema")
+		renderedSchema, err := schema.RenderWithOptions(document, schemaOptions)
+		stop()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Could not render schema: %s\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Println(renderedSchema)
@@ -101,56 +350,1927 @@ var Schema = cobra.Command{
 var Lint = cobra.Command{
 	Use: "lint",
 	Run: func(cmd *cobra.Command, args []string) {
-		document, err := parser.Load(valuesFile, true)
+		document, err := loadDocument(true)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
-			os.Exit(1)
+			exit(1)
 		}
 
-		err = linter.Lint(templatesFolder, exceptionsFile, document)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Could not lint: %s\n", err)
-			os.Exit(1)
+		if issues := lintDocument(document); issues > 0 {
+			fmt.Fprintf(os.Stderr, "Could not lint: found %d issues, values.yaml is not in sync\n", issues)
+			exit(1)
 		}
 
 		fmt.Println("No errors found")
 	},
 }
 
-func init() {
-	Cmd.PersistentFlags().StringVarP(&valuesFile, "values", "i", "values.yaml", "values file used to generate the documentation")
+var Generate = cobra.Command{
+	Use:   "generate",
+	Short: "inject the documentation, write the schema and lint the values file in a single run",
+	Long: `Parse the values file once and use it to inject the documentation into the README (same as inject), write
+the JSON schema (same as schema) and lint the values file and templates (same as lint). Set --output or
+--schema-output to an empty string, or use --lint=false, to skip a step.`,
+	Example: `  helm-tool generate -i values.yaml -o README.md --schema-output values.schema.json -d templates`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if sinceRef != "" {
+			changed, err := git.ChangedSince(sinceRef, generateInputs()...)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not detect changes since %q: %s\n", sinceRef, err)
+				exit(1)
+			}
 
-	Cmd.AddCommand(&Inject)
-	Inject.PersistentFlags().StringVarP(&templateName, "template", "t", "markdown-plain", "template to render documentation with")
-	Inject.PersistentFlags().StringVarP(&targetFile, "output", "o", "README.md", "file to inject the generated markdown into")
-	Inject.PersistentFlags().Var(&headerSearch, "header-search", "set the regex used to match the start of the injected markdown")
-	Inject.PersistentFlags().Var(&footerSearch, "footer-search", "set the regex used to match the end of the injected markdown")
+			if !changed {
+				fmt.Fprintf(os.Stderr, "Skipping %q, the chart did not change since %q\n", valuesFile, sinceRef)
+				return
+			}
+		}
 
-	Cmd.AddCommand(&Render)
-	Render.PersistentFlags().StringVarP(&templateName, "template", "t", "markdown-plain", "template to render documentation with")
+		var key *cache.Key
+		outputCache := cache.Cache{Dir: cacheDir}
+		if cacheDir != "" && renderOptions.Format == "" && !renderOptions.LastCommits && userValuesFile == "" {
+			var err error
+			if key, err = generateCacheKey(); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not hash the inputs: %s\n", err)
+				exit(1)
+			}
 
-	Cmd.AddCommand(&Schema)
+			if entry, ok := outputCache.Get(key); ok && (entry.Linted || !lintValues) {
+				writeGenerated(entry)
+				return
+			}
+		}
 
-	Cmd.AddCommand(&Lint)
-	Lint.PersistentFlags().StringVarP(&templatesFolder, "templates", "d", "templates", "templates folder used to lint the values file")
-	Lint.PersistentFlags().StringVarP(&exceptionsFile, "exceptions", "e", "", "file containing exceptions to the linting rules")
-}
+		document, err := loadDocument(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
 
-func main() {
-	Cmd.Execute()
+		var entry cache.Entry
+		if targetFile != "" {
+			stop := runTimings.Start("render")
+			entry.Markdown, err = render.RenderWithOptions(templateName, document.WithoutHidden().ForAudience(audience), renderOptions)
+			stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error rendering template: %s\n", err)
+				exit(1)
+			}
+		}
+
+		if schemaFile != "" {
+			stop := runTimings.Start("schema")
+			entry.Schema, err = schema.RenderWithOptions(document, schemaOptions)
+			stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not render schema: %s\n", err)
+				exit(1)
+			}
+		}
+
+		writeGenerated(entry)
+
+		if lintValues {
+			if issues := lintDocument(document); issues > 0 {
+				fmt.Fprintf(os.Stderr, "Could not lint: found %d issues, values.yaml is not in sync\n", issues)
+				exit(1)
+			}
+
+			entry.Linted = true
+		}
+
+		if key != nil {
+			if err := outputCache.Put(key, entry); err != nil {
+				log.Printf("could not write to the cache: %s\n", err)
+			}
+		}
+	},
 }
 
-type regexValue struct {
-	regexp *regexp.Regexp
+// writeGenerated injects the rendered documentation and writes the schema of
+// the generate command.
+func writeGenerated(entry cache.Entry) {
+	if targetFile != "" {
+		injectRendered(entry.Markdown)
+	}
+
+	if schemaFile != "" {
+		if err := writeFile(schemaFile, []byte(entry.Schema+"\n")); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", schemaFile, err)
+			exit(1)
+		}
+	}
 }
 
-func (r *regexValue) String() string {
-	return r.regexp.String()
+// generateInputs returns the files and directories that are used by the
+// generate command.
+func generateInputs() []string {
+	inputs := []string{filepath.Dir(valuesFile), valuesFile}
+	if lintValues {
+		inputs = append(inputs, templatesFolder)
+	}
+
+	for _, file := range []string{templateName, overridesFile, configFile, exceptionsFile, readmeFile} {
+		if _, err := os.Stat(file); file != "" && err == nil {
+			inputs = append(inputs, file)
+		}
+	}
+
+	return inputs
 }
 
-func (r *regexValue) Set(value string) error {
-	compiled, err := regexp.Compile("(?m)" + value)
+// generateCacheKey returns the hash of all inputs of the generate command:
+// the settings, the chart directory (except for the outputs) and the other
+// files that are used.
+func generateCacheKey() (*cache.Key, error) {
+	// The option structs are hashed as a whole, so that new options cannot be
+	// missed. The post-render hooks are functions, their commands are hashed
+	// instead, and the provenance is only set when injecting.
+	options := renderOptions
+	options.PostRender = nil
+	options.Provenance = nil
+	renderOptionsJSON, err := json.Marshal(options)
 	if err != nil {
+		return nil, err
+	}
+
+	schemaOptionsJSON, err := json.Marshal(schemaOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	key := cache.NewKey()
+	key.AddString("version", version.Version())
+	for _, setting := range []struct{ name, value string }{
+		{"template", templateName},
+		{"audience", audience},
+		{"renderOptions", string(renderOptionsJSON)},
+		{"postRender", strings.Join(postRender, "\x00")},
+		{"schemaOptions", string(schemaOptionsJSON)},
+		{"tagPrefix", tagPrefix},
+		{"dialect", dialect},
+		{"strictTags", fmt.Sprint(strictTags)},
+		{"unknownType", unknownType},
+		{"strictTypes", fmt.Sprint(strictTypes)},
+		{"headerSearch", headerSearch.String()},
+		{"footerSearch", footerSearch.String()},
+		{"templates", templatesFolder},
+		{"exceptions", exceptionsFile},
+		{"readme", readmeFile},
+		{"owners", ownersFile},
+		{"overrides", overridesFile},
+		{"lint", fmt.Sprint(lintValues)},
+		{"spelling", fmt.Sprint(spelling)},
+		{"dictionaries", strings.Join(dictionaries, "\x00")},
+		{"policies", strings.Join(policies, "\x00")},
+	} {
+		key.AddString(setting.name, setting.value)
+	}
+
+	if err := key.AddDir(filepath.Dir(valuesFile), targetFile, schemaFile); err != nil {
+		return nil, err
+	}
+
+	files := []string{valuesFile, templateName, overridesFile, ownersFile, configFile, exceptionsFile, readmeFile, idsFile}
+	if lintValues {
+		if spelling {
+			files = append(files, spellingDictionaries()...)
+		}
+
+		for _, command := range policies {
+			files = append(files, linter.ExecPolicy{Command: command}.Files()...)
+		}
+	}
+
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+
+		if err := key.AddFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	if lintValues {
+		if err := key.AddDir(templatesFolder); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+var TemplateLint = cobra.Command{
+	Use:   "template-lint <template>",
+	Short: "check a custom template against the documentation data model",
+	Long: `Check a custom template: syntax errors, fields that do not exist in the documentation data model, calls of
+templates that are not defined and templates that do not render the sections are reported. The template is then
+rendered against a built-in sample document, which reports errors in the data it uses, use --print to write the
+result to stdout.`,
+	Example: `  helm-tool template-lint docs/values.md.tmpl --print`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, issues, err := render.LintTemplate(args[0], renderOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not lint %q: %s\n", args[0], err)
+			exit(1)
+		}
+
+		if printTemplate {
+			fmt.Println(output)
+		}
+
+		for _, issue := range issues {
+			fmt.Fprintln(os.Stderr, issue)
+		}
+
+		if len(issues) > 0 {
+			exit(1)
+		}
+	},
+}
+
+var Fmt = cobra.Command{
+	Use:   "fmt",
+	Short: "format the documentation comments in the values file",
+	Run: func(cmd *cobra.Command, args []string) {
+		content, err := os.ReadFile(valuesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		formatted, err := formatter.Format(content, formatOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not format %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		switch {
+		case formatCheck:
+			if !bytes.Equal(content, formatted) {
+				if outputFormat == linter.FormatGitHub {
+					linter.PrintIssues(os.Stdout, outputFormat, valuesFile, []linter.Issue{{
+						Line:    firstDifferentLine(content, formatted),
+						Message: "values file is not formatted, run helm-tool fmt -w",
+					}})
+				}
+
+				fmt.Fprintf(os.Stderr, "%q is not formatted\n", valuesFile)
+				exit(1)
+			}
+		case formatWrite:
+			if err := writeFile(valuesFile, formatted); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", valuesFile, err)
+				exit(1)
+			}
+		default:
+			os.Stdout.Write(formatted)
+		}
+	},
+}
+
+var Sync = cobra.Command{
+	Use:   "sync",
+	Short: "update defaults in the values file from external sources",
+	Long: `Update defaults in the values file from external sources, while preserving comments and formatting.
+
+Sources are provided as <path>=<source>, where source is one of:
+  chart:<field>         a field of the chart's Chart.yaml (name, version, appVersion or description)
+  json:<file>#<path>    the value at path in a JSON or YAML file
+  env:<name>            an environment variable
+  value:<value>         a literal value`,
+	Example: `  helm-tool sync --set image.tag=chart:appVersion --set image.digest=json:digests.json#controller`,
+	Run: func(cmd *cobra.Command, args []string) {
+		content, err := os.ReadFile(valuesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		for _, sync := range syncSources {
+			pathString, source, found := strings.Cut(sync, "=")
+			if !found {
+				fmt.Fprintf(os.Stderr, "Invalid sync %q, expected <path>=<source>\n", sync)
+				exit(1)
+			}
+
+			path, err := paths.Parse(pathString)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not parse path %q: %s\n", pathString, err)
+				exit(1)
+			}
+
+			value, err := editor.ResolveSource(source, filepath.Dir(valuesFile))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not resolve %q: %s\n", source, err)
+				exit(1)
+			}
+
+			content, err = editor.SetScalar(content, path, value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not update %q: %s\n", valuesFile, err)
+				exit(1)
+			}
+		}
+
+		if dryRun {
+			os.Stdout.Write(content)
+			return
+		}
+
+		if err := writeFile(valuesFile, content); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+	},
+}
+
+var Rename = cobra.Command{
+	Use:   "rename <old path> <new path>",
+	Short: "rename a value in the values file",
+	Long: `Rename a value in the values file, while preserving its comments. The old path is recorded
+using a +docs:alias tag, and with --update-templates the .Values references in the templates are updated too.`,
+	Example: `  helm-tool rename webhook.hostNetwork webhook.networking.hostNetwork --update-templates`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, err := paths.Parse(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not parse path %q: %s\n", args[0], err)
+			exit(1)
+		}
+
+		to, err := paths.Parse(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not parse path %q: %s\n", args[1], err)
+			exit(1)
+		}
+
+		content, err := os.ReadFile(valuesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		content, err = editor.Rename(content, from, to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not rename %q: %s\n", from, err)
+			exit(1)
+		}
+
+		var changed map[string][]byte
+		if updateTemplates {
+			changed, err = editor.RenameTemplateReferences(templatesFolder, from, to)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not update templates: %s\n", err)
+				exit(1)
+			}
+		}
+
+		files := make([]string, 0, len(changed))
+		for file := range changed {
+			files = append(files, file)
+		}
+		slices.Sort(files)
+
+		// The updated values file is written to stdout, the changes of the
+		// templates to stderr
+		if dryRun {
+			os.Stdout.Write(content)
+
+			for _, file := range files {
+				original, err := os.ReadFile(file)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", file, err)
+					exit(1)
+				}
+
+				fmt.Fprintf(os.Stderr, "would update %s:\n", file)
+				originalLines, changedLines := strings.Split(string(original), "\n"), strings.Split(string(changed[file]), "\n")
+				for i := range originalLines {
+					if i < len(changedLines) && originalLines[i] != changedLines[i] {
+						fmt.Fprintf(os.Stderr, "%d: -%s\n%d: +%s\n", i+1, originalLines[i], i+1, changedLines[i])
+					}
+				}
+			}
+			return
+		}
+
+		if err := writeFile(valuesFile, content); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		for _, file := range files {
+			if err := writeFile(file, changed[file]); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", file, err)
+				exit(1)
+			}
+
+			fmt.Printf("updated %s\n", file)
+		}
+	},
+}
+
+var Migrate = cobra.Command{
+	Use:   "migrate",
+	Short: "convert the documentation comments between +docs: tags and other documentation tools",
+	Long: `Rewrite the annotations of another documentation tool in the values file into +docs: tags (--from), or
+the +docs: tags into the annotations of another tool (--to), while preserving the values and all other comments.
+When migrating to +docs: tags, descriptions are moved directly above the values they document.`,
+	Example: `  helm-tool migrate --from helm-docs
+  helm-tool migrate --to bitnami --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if (migrateFrom == "") == (migrateTo == "") {
+			fmt.Fprintf(os.Stderr, "Exactly one of --from and --to must be set\n")
+			exit(1)
+		}
+
+		content, err := os.ReadFile(valuesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		if migrateFrom != "" {
+			content, err = parser.Migrate(content, migrateFrom)
+		} else {
+			content, err = parser.Export(content, migrateTo)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not migrate %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		if dryRun {
+			os.Stdout.Write(content)
+			return
+		}
+
+		if err := writeFile(valuesFile, content); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+	},
+}
+
+var Owners = cobra.Command{
+	Use:   "owners",
+	Short: "list the documented values by owner",
+	Long: `List the documented values grouped by the teams that own them, these are set using +docs:owner tags (on a
+value, one of its parents or its section) or using a CODEOWNERS-style owners file (--owners). With --format json
+the report is written as JSON.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		type ownership struct {
+			Owner      string   `json:"owner"`
+			Properties []string `json:"properties"`
+		}
+
+		var report []ownership
+		for _, owned := range document.ByOwner() {
+			entry := ownership{Owner: owned.Owner, Properties: []string{}}
+			for _, property := range owned.Properties {
+				entry.Properties = append(entry.Properties, property.Path.String())
+			}
+			report = append(report, entry)
+		}
+
+		switch outputFormat {
+		case "text":
+			for i, entry := range report {
+				if i > 0 {
+					fmt.Println()
+				}
+
+				owner := entry.Owner
+				if owner == "" {
+					owner = "(no owner)"
+				}
+
+				fmt.Printf("%s (%d)\n", owner, len(entry.Properties))
+				for _, path := range entry.Properties {
+					fmt.Printf("  %s\n", path)
+				}
+			}
+		case "json":
+			output, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create report: %s\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("%s\n", output)
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown format %q\n", outputFormat)
+			exit(1)
+		}
+	},
+}
+
+var Deprecations = cobra.Command{
+	Use:   "deprecations",
+	Short: "list the deprecated values by the version they are removed in",
+	Long: `List the deprecated values (+docs:deprecated or +docs:removed-in tags) grouped by the version of the chart
+they are removed in, which is set using a +docs:removed-in tag, so release managers can see what must be removed in
+the next release. Values without a removal version are listed last. Use --format markdown for a report to publish
+or --format json for a JSON report.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		type deprecation struct {
+			Path    string `json:"path"`
+			Message string `json:"message,omitempty"`
+		}
+
+		type removal struct {
+			Version    string        `json:"version,omitempty"`
+			Properties []deprecation `json:"properties"`
+		}
+
+		report := []removal{}
+		for _, group := range document.ByRemovalVersion() {
+			entry := removal{Version: group.Version}
+			for _, property := range group.Properties {
+				entry.Properties = append(entry.Properties, deprecation{
+					Path:    property.Path.String(),
+					Message: property.Description.Tags.GetString(parser.TagDeprecated),
+				})
+			}
+			report = append(report, entry)
+		}
+
+		switch outputFormat {
+		case "text", "markdown":
+			for i, entry := range report {
+				if i > 0 {
+					fmt.Println()
+				}
+
+				version := "Removed in " + entry.Version
+				if entry.Version == "" {
+					version = "No removal version"
+				}
+
+				if outputFormat == "markdown" {
+					fmt.Printf("## %s\n\n", version)
+				} else {
+					fmt.Printf("%s (%d)\n", version, len(entry.Properties))
+				}
+
+				for _, property := range entry.Properties {
+					if outputFormat == "markdown" {
+						fmt.Printf("- `%s`", property.Path)
+					} else {
+						fmt.Printf("  %s", property.Path)
+					}
+
+					if property.Message != "" {
+						fmt.Printf(": %s", property.Message)
+					}
+					fmt.Println()
+				}
+			}
+		case "json":
+			output, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create report: %s\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("%s\n", output)
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown format %q\n", outputFormat)
+			exit(1)
+		}
+	},
+}
+
+var Redirects = cobra.Command{
+	Use:   "redirects",
+	Short: "write a map of the anchors of renamed values to their current anchors",
+	Long: `Write a map of the anchors of the previous paths of renamed values (+docs:alias tags, added by rename) to the
+anchors of their current paths, so documentation sites can keep deep links working across chart versions. With
+--format html a script is written that can be included in the page of the documentation to redirect the links.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		redirects, err := render.Redirects(document)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not create redirects: %s\n", err)
+			exit(1)
+		}
+
+		switch redirectFormat {
+		case "json":
+			anchors := map[string]string{}
+			for _, redirect := range redirects {
+				anchors[redirect.From] = redirect.To
+			}
+
+			output, err := json.MarshalIndent(anchors, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create redirects: %s\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("%s\n", output)
+		case "html":
+			if err := render.WriteRedirectsHTML(os.Stdout, redirects); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create redirects: %s\n", err)
+				exit(1)
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown format %q\n", redirectFormat)
+			exit(1)
+		}
+	},
+}
+
+var Tags = cobra.Command{
+	Use:   "tags",
+	Short: "report the tags used in the values file and the tags without effect",
+	Long: `Report the number of uses and the lines of every +docs: tag in the values file, followed by the tags that
+have no effect on the documentation, eg. a +docs:type tag above an ignored value or above an object that is
+documented as its children. With --format json the report is written as JSON.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		contents, err := os.ReadFile(valuesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not read %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		uses, err := parser.FindTagUses(contents, filepath.Dir(valuesFile), parser.Options{
+			TagPrefix: tagPrefix,
+			Dialect:   dialect,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not parse %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		type tagCount struct {
+			Tag   string `json:"tag"`
+			Count int    `json:"count"`
+			Lines []int  `json:"lines"`
+		}
+
+		var counts []tagCount
+		noEffect := []parser.TagUse{}
+		for _, use := range uses {
+			idx := slices.IndexFunc(counts, func(count tagCount) bool { return count.Tag == use.Tag })
+			if idx == -1 {
+				counts = append(counts, tagCount{Tag: use.Tag})
+				idx = len(counts) - 1
+			}
+			counts[idx].Count++
+			counts[idx].Lines = append(counts[idx].Lines, use.Line)
+
+			if use.NoEffect != "" {
+				noEffect = append(noEffect, use)
+			}
+		}
+		sort.Slice(counts, func(i, j int) bool { return counts[i].Tag < counts[j].Tag })
+
+		switch outputFormat {
+		case "text":
+			for _, count := range counts {
+				lines := make([]string, len(count.Lines))
+				for i, line := range count.Lines {
+					lines[i] = strconv.Itoa(line)
+				}
+
+				fmt.Printf("+%s (%d): lines %s\n", count.Tag, count.Count, strings.Join(lines, ", "))
+			}
+
+			if len(noEffect) > 0 {
+				fmt.Printf("\nTags without effect (%d)\n", len(noEffect))
+				for _, use := range noEffect {
+					tag := "+" + use.Tag
+					if use.Value != "" {
+						tag += "=" + use.Value
+					}
+
+					fmt.Printf("  line %d: %s: %s\n", use.Line, tag, use.NoEffect)
+				}
+			}
+		case "json":
+			report := struct {
+				Tags     []tagCount      `json:"tags"`
+				NoEffect []parser.TagUse `json:"noEffect"`
+			}{counts, noEffect}
+
+			output, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create report: %s\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("%s\n", output)
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown format %q\n", outputFormat)
+			exit(1)
+		}
+	},
+}
+
+var Diff = cobra.Command{
+	Use:   "diff <old values file>",
+	Short: "show how the documented values changed since a previous version of the values file",
+	Long: `Show the documented values that were added, removed or renamed and the defaults that changed since a previous
+version of the values file. With --format json-patch the changes to the defaults are written as an RFC 6902 JSON
+Patch, with --format jq as a jq (or yq) script that updates a user's values file.`,
+	Example: `  git show v1.14.0:deploy/charts/cert-manager/values.yaml > old-values.yaml
+  helm-tool diff old-values.yaml --format jq > upgrade.jq
+  yq -i "$(cat upgrade.jq)" my-values.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		oldDocument, err := parser.LoadWithOptions(args[0], parser.Options{
+			IncludeHidden: true,
+			TagPrefix:     tagPrefix,
+			Dialect:       dialect,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not load %q: %s\n", args[0], err)
+			exit(1)
+		}
+
+		document, err := loadDocument(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not load %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		changes := diff.Documents(oldDocument, document)
+
+		switch outputFormat {
+		case diff.FormatText:
+			diff.WriteText(os.Stdout, changes)
+		case diff.FormatJSONPatch:
+			operations, err := diff.JSONPatch(changes)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create JSON Patch: %s\n", err)
+				exit(1)
+			}
+
+			patch, err := json.MarshalIndent(operations, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create JSON Patch: %s\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("%s\n", patch)
+		case diff.FormatJQ:
+			script, err := diff.JQScript(changes)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create jq script: %s\n", err)
+				exit(1)
+			}
+
+			fmt.Print(script)
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown format %q\n", outputFormat)
+			exit(1)
+		}
+	},
+}
+
+var Index = cobra.Command{
+	Use:   "index <chart directory>...",
+	Short: "write an index page listing the charts of a repository",
+	Long: `Write a Markdown index page listing the charts in the given directories with their name, version, app version and
+description (from their Chart.yaml) and a link to their generated documentation (--docs, relative to the chart
+directory), eg. for the landing page of a repository containing many charts. The links are relative to the directory
+of --output, or to the current directory when writing to stdout.`,
+	Example: `  helm-tool index charts/*/ --output charts/README.md`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		linkBase := "."
+		if indexOutput != "" {
+			linkBase = filepath.Dir(indexOutput)
+		}
+
+		var entries []render.IndexEntry
+		for _, chartDir := range args {
+			chart, err := parser.LoadChart(chartDir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not load chart %q: %s\n", chartDir, err)
+				exit(1)
+			}
+
+			if chart == nil {
+				fmt.Fprintf(os.Stderr, "Could not load chart %q: no Chart.yaml file found\n", chartDir)
+				exit(1)
+			}
+
+			docs, err := relativePath(linkBase, filepath.Join(chartDir, indexDocs))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not link the documentation of chart %q: %s\n", chartDir, err)
+				exit(1)
+			}
+
+			entries = append(entries, render.IndexEntry{
+				Chart: *chart,
+				Docs:  docs,
+			})
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Chart.Name < entries[j].Chart.Name })
+
+		if indexOutput == "" {
+			render.WriteIndex(os.Stdout, indexTitle, entries)
+			return
+		}
+
+		var sb strings.Builder
+		render.WriteIndex(&sb, indexTitle, entries)
+		if err := writeFile(indexOutput, []byte(sb.String())); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", indexOutput, err)
+			exit(1)
+		}
+	},
+}
+
+var Consistency = cobra.Command{
+	Use:   "consistency <chart directory>...",
+	Short: "report values that are documented differently across charts",
+	Long: `Compare the values that exist in multiple charts (eg. image.pullPolicy, resources or nodeSelector) and report
+the types, defaults and descriptions that differ between the charts, so a fleet of charts stays uniform. Use --path to
+only compare the values under the given paths. The command fails if inconsistencies are found.`,
+	Example: `  helm-tool consistency charts/*/ --path image.pullPolicy --path resources --path nodeSelector`,
+	Args:    cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var filter []paths.Path
+		for _, value := range comparePaths {
+			path, err := paths.Parse(value)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not parse path %q: %s\n", value, err)
+				exit(1)
+			}
+
+			filter = append(filter, path)
+		}
+
+		var documents []parser.NamedDocument
+		for _, chartDir := range args {
+			document, err := parser.LoadWithOptions(filepath.Join(chartDir, "values.yaml"), parser.Options{
+				TagPrefix: tagPrefix,
+				Dialect:   dialect,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not load chart %q: %s\n", chartDir, err)
+				exit(1)
+			}
+
+			name := filepath.Base(filepath.Clean(chartDir))
+			if document.Chart != nil && document.Chart.Name != "" {
+				name = document.Chart.Name
+			}
+
+			documents = append(documents, parser.NamedDocument{Name: name, Document: document})
+		}
+
+		inconsistencies := parser.FindInconsistencies(documents, filter)
+
+		switch outputFormat {
+		case "text":
+			for i, inconsistency := range inconsistencies {
+				if i > 0 {
+					fmt.Println()
+				}
+
+				fmt.Printf("%s: the %s differs\n", inconsistency.Path, inconsistency.Field)
+				for _, value := range inconsistency.Values {
+					fmt.Printf("  %s: %s\n", value.Chart, strings.Join(strings.Fields(value.Value), " "))
+				}
+			}
+		case "json":
+			output, err := json.MarshalIndent(inconsistencies, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not create report: %s\n", err)
+				exit(1)
+			}
+
+			fmt.Printf("%s\n", output)
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown format %q\n", outputFormat)
+			exit(1)
+		}
+
+		if len(inconsistencies) > 0 {
+			exit(1)
+		}
+	},
+}
+
+var TelemetryIDs = cobra.Command{
+	Use:   "telemetry-ids",
+	Short: "write a map of the paths of the documented values to stable IDs",
+	Long: `Write a JSON map of the paths of the documented values to stable IDs, which do not change when a value is renamed
+using the rename command. Ship the map with the telemetry of the chart, and use the telemetry package to report the
+IDs of the values users override instead of their paths.`,
+	Example: `  helm-tool telemetry-ids > telemetry-ids.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		output, err := json.MarshalIndent(telemetry.NewMapping(document), "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not create IDs: %s\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("%s\n", output)
+	},
+}
+
+var UpdateIDs = cobra.Command{
+	Use:   "update-ids <file>",
+	Short: "update the sidecar file of stable property IDs",
+	Long: `Update the sidecar file of stable property IDs used by --ids (created if missing): properties seen for the first
+time are added with the version of the chart, and renamed properties keep their ID. The other commands only read the
+file, run this command when the values change and commit the file next to the values file.`,
+	Example: `  helm-tool update-ids -i values.yaml values.ids.json`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		ids, changed, err := setIDs(document, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not set the property IDs of %q: %s\n", args[0], err)
+			exit(1)
+		}
+
+		if !changed {
+			return
+		}
+
+		contents, err := ids.Marshal()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not marshal the property IDs: %s\n", err)
+			exit(1)
+		}
+
+		if err := writeFile(args[0], contents); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", args[0], err)
+			exit(1)
+		}
+	},
+}
+
+var Site = cobra.Command{
+	Use:   "site",
+	Short: "write a static site documenting the values",
+	Long: `Write a small static site documenting the values to a directory (--output-dir): an index page with the chart
+description, a page per section with navigation between the pages and a search box filtering the values. The site
+has no external dependencies, so it can be published as-is, eg. to GitHub Pages.`,
+	Example: `  helm-tool site -i values.yaml --output-dir public`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		if err := render.WriteSite(siteDir, siteTitle, document.ForAudience(audience), renderOptions); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write site: %s\n", err)
+			exit(1)
+		}
+	},
+}
+
+var MkDocs = cobra.Command{
+	Use:   "mkdocs",
+	Short: "write the documentation as pages of a MkDocs site",
+	Long: `Write the documentation as Markdown pages of a MkDocs site to a directory (--output-dir): an index.md page with
+the chart description and a page per section, rendered using the template. The nav fragment listing the pages is
+written to --nav-file (or stdout), so it can be included in the nav of mkdocs.yml without maintaining it by hand.`,
+	Example: `  helm-tool mkdocs -i values.yaml --output-dir docs/reference/values --nav-prefix reference/values --nav-file values-nav.yml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		nav, err := render.WriteMkDocs(mkdocsDir, navPrefix, siteTitle, templateName, document.ForAudience(audience), renderOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write the MkDocs pages: %s\n", err)
+			exit(1)
+		}
+
+		if navFile == "" {
+			fmt.Print(nav)
+			return
+		}
+
+		if err := writeFile(navFile, []byte(nav)); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not write %q: %s\n", navFile, err)
+			exit(1)
+		}
+	},
+}
+
+var Helmfile = cobra.Command{
+	Use:   "helmfile <helmfile.yaml>",
+	Short: "document the values the releases of a helmfile set",
+	Long: `Document the values each release of a helmfile sets on top of the chart defaults, as a Markdown table per
+release. The helmfile and the values files ending in .gotmpl are rendered using the values of the selected
+environment (--environment). The values of local charts are described using the documentation of the chart.`,
+	Example: `  helm-tool helmfile helmfile.yaml --environment production > docs/production.md`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := helmfile.Load(args[0], environment)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not load %q: %s\n", args[0], err)
+			exit(1)
+		}
+
+		var releases []helmfile.ReleaseOverrides
+		for _, release := range file.Releases {
+			if !release.IsInstalled() {
+				continue
+			}
+
+			values, err := file.ReleaseValues(release)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not load values: %s\n", err)
+				exit(1)
+			}
+
+			var document *parser.Document
+			if chartDir := file.ChartDir(release); chartDir != "" {
+				document, err = parser.LoadWithOptions(filepath.Join(chartDir, "values.yaml"), parser.Options{
+					TagPrefix: tagPrefix,
+					Dialect:   dialect,
+				})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Could not load the chart of release %q: %s\n", release.Name, err)
+					exit(1)
+				}
+			}
+
+			releases = append(releases, helmfile.ReleaseOverrides{
+				Release:    release,
+				Documented: document != nil,
+				Overrides:  helmfile.Overrides(document, values),
+			})
+		}
+
+		helmfile.WriteMarkdown(os.Stdout, releases)
+	},
+}
+
+var CompletionData = cobra.Command{
+	Use:   "completion-data",
+	Short: "write the documented values as compact completion data for helm's --set flag",
+	Long: `Write the paths, types and allowed values (+docs:enum) of the documented values as compact JSON, which is used
+by complete-set to complete the arguments of helm's --set flag. Hidden values are left out.`,
+	Example: `  helm-tool completion-data > completion.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := loadDocument(false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		output, err := json.Marshal(completion.New(document))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not create completion data: %s\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("%s\n", output)
+	},
+}
+
+var CompleteSet = cobra.Command{
+	Use:   "complete-set <word>",
+	Short: "suggest the paths and values for an argument of helm's --set flag",
+	Long: `Print the completions of an argument of helm's --set flag, one per line: the documented paths starting with
+the word, or the allowed values of the path if the word contains a "=". The completion data is read from --data
+(written by completion-data), or created from the values file. Shell completion scripts for helm install can
+use this to complete --set arguments.`,
+	Example: `  helm-tool complete-set --data completion.json webhook.repl
+  helm-tool complete-set --data completion.json image.pullPolicy=`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var data *completion.Data
+		if completionData != "" {
+			var err error
+			data, err = completion.Load(completionData)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not load %q: %s\n", completionData, err)
+				exit(1)
+			}
+		} else {
+			document, err := loadDocument(false)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+				exit(1)
+			}
+
+			data = completion.New(document)
+		}
+
+		for _, candidate := range data.Complete(args[0]) {
+			fmt.Println(candidate)
+		}
+	},
+}
+
+var LSP = cobra.Command{
+	Use:   "lsp",
+	Short: "run a language server providing documentation for values files over stdio",
+	Long: `Run a language server over stdio. It provides hover documentation, completion of documented paths and
+diagnostics for the values file, the overrides file and users' values files, based on the documentation in
+the values file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		server := lsp.Server{
+			ValuesFile:      valuesFile,
+			OverridesFile:   overridesFile,
+			TemplatesFolder: templatesFolder,
+			ExceptionsFile:  exceptionsFile,
+			TagPrefix:       tagPrefix,
+			Dialect:         dialect,
+		}
+
+		if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Language server failed: %s\n", err)
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	Cmd.PersistentFlags().StringVarP(&valuesFile, "values", "i", "values.yaml", "values file used to generate the documentation")
+	Cmd.PersistentFlags().BoolVar(&useSample, "sample", false, "use a built-in sample document containing all shapes of properties instead of the values file, eg. to test custom templates")
+	Cmd.PersistentFlags().StringVar(&configFile, "config", config.DefaultPath, "config file containing additional settings")
+	Cmd.PersistentFlags().StringVar(&profile, "profile", "", "name of the config file profile to use")
+	Cmd.PersistentFlags().BoolVar(&debugTimings, "debug-timings", false, "write the duration and memory use of each phase (parse, comments, render, inject, schema, lint) to stderr")
+	Cmd.PersistentFlags().StringVar(&summaryFile, "summary", "", "write a JSON summary of the run (files written, warnings, lint issues and duration) to this file")
+	Cmd.PersistentFlags().StringVar(&tagPrefix, "tag-prefix", "", "alternative prefix for the tags in the values file comments, eg. @ for @type=string (tags starting with +docs: are always recognized)")
+	Cmd.PersistentFlags().StringVar(&dialect, "dialect", parser.DialectDefault, "convention of the documentation comments in the values file, leave empty for +docs: tags, use bitnami for Bitnami's ## @param annotations or helm-docs for \"# --\" comments")
+	Cmd.PersistentFlags().BoolVar(&strictTags, "strict-tags", false, "fail if the values file contains comments that look like tags but are not recognized (these are always logged as warnings)")
+	Cmd.PersistentFlags().StringVar(&unknownType, "unknown-type", "", "type of the values whose type cannot be determined (eg. object), instead of unknown")
+	Cmd.PersistentFlags().BoolVar(&strictTypes, "strict-types", false, "fail if the type of a value cannot be determined (these are always logged as a warning)")
+	Cmd.PersistentFlags().StringVar(&ownersFile, "owners", "", "CODEOWNERS-style file assigning owners to values by path pattern, eg. \"webhook.* @org/webhook-team\" (+docs:owner tags take precedence)")
+	Cmd.PersistentFlags().StringVar(&overridesFile, "overrides", "", "file containing descriptions, types and examples that override the values file comments")
+
+	for _, cmd := range []*cobra.Command{&Inject, &Render, &Generate} {
+		cmd.PersistentFlags().BoolVar(&renderOptions.LinkTypes, "link-types", false, "render Kubernetes and custom type names as links to their documentation")
+		cmd.PersistentFlags().StringToStringVar(&renderOptions.TypeLinks, "type-link", nil, "link a type name to the URL of its documentation (eg. MyType=https://example.com/docs#mytype)")
+		cmd.PersistentFlags().BoolVar(&renderOptions.Tree, "tree", false, "render property names in tables as an indented tree instead of full paths (markdown-table template)")
+		cmd.PersistentFlags().StringVar(&renderOptions.ArrayIndex, "array-index", render.ArrayIndexNumber, "how array indices in property paths are displayed: number (args[0]), empty (args[]) or wildcard (args[*])")
+		cmd.PersistentFlags().StringVar(&renderOptions.Format, "format", "", "render using a different output format than the template: json for the parsed documentation as JSON, jsonl for a JSON object per property, csv for a row per property, xml for the parsed documentation as XML, yaml for the parsed documentation as YAML, search-index for a Lunr or Algolia search index, artifacthub for the artifacthub.io/changes annotation of Chart.yaml, or exec:<command> to pipe the JSON to an external renderer")
+		cmd.PersistentFlags().BoolVar(&renderOptions.AdvancedAppendix, "advanced-appendix", false, "move the values marked using +docs:advanced to a collapsed appendix section at the end of the documentation")
+		cmd.PersistentFlags().BoolVar(&renderOptions.SameAsAnchors, "same-as-anchors", false, "render the defaults of values that are a YAML alias (eg. tolerations: *defaultTolerations) as \"same as\" links to the value defining the anchor, instead of repeating the default")
+		cmd.PersistentFlags().BoolVar(&renderOptions.PropertyAnchors, "property-anchors", false, "render an HTML anchor before every property of the markdown templates, so they can be linked to (always rendered if the documentation links to properties using +docs:see or --same-as-anchors)")
+		cmd.PersistentFlags().StringVar(&renderOptions.Sort, "sort", render.SortFile, "order of the properties within a section: file (the order of the values file) or alphabetical (by name, using the collation of --locale)")
+		cmd.PersistentFlags().StringSliceVar(&renderOptions.Columns, "columns", nil, "columns of the markdown-table template to render, of "+strings.Join(render.TableColumns, ", ")+" (all if empty, the property column is always rendered)")
+		cmd.PersistentFlags().StringVar(&renderOptions.Locale, "locale", "", "locale of the documentation (eg. de-DE), used to sort the properties alphabetically and to format numbers")
+		cmd.PersistentFlags().StringVar(&renderOptions.BoolFormat, "bool-format", "", "names true and false defaults are displayed as, separated by a comma (eg. enabled,disabled)")
+		cmd.PersistentFlags().StringArrayVar(&postRender, "post-render", nil, "command run on the rendered documentation before it is written, eg. \"prettier --write\" (the path of a temporary file is appended, can be repeated)")
+		cmd.PersistentFlags().IntVar(&renderOptions.MaxSectionProperties, "max-section-properties", 0, "split the table of sections with more properties than this into a table per top-level object (markdown-table template, 0 never splits)")
+		cmd.PersistentFlags().StringToStringVar(&renderOptions.FrontMatter, "front-matter", nil, "field of the front matter written by the templates of documentation sites, eg. title=Values (mdx and hugo templates, can be repeated)")
+		cmd.PersistentFlags().StringVar(&renderOptions.FrontMatterFormat, "front-matter-format", render.FrontMatterYAML, "format of the front matter: yaml or toml")
+		cmd.PersistentFlags().StringVar(&renderOptions.TableShortcode, "table-shortcode", "", "name of a Hugo shortcode to wrap the tables in (hugo template)")
+		cmd.PersistentFlags().BoolVar(&renderOptions.LastCommits, "blame", false, "add the last git commit that changed each property (a column in the markdown-table template, lastCommit in the JSON output)")
+		cmd.PersistentFlags().StringVar(&blameLink, "blame-link", "", "Go template of the link to the commits shown by --blame, using .Hash, .ShortHash, .PR and .Summary (eg. https://github.com/org/repo/commit/{{ .Hash }})")
+		cmd.PersistentFlags().StringVar(&idsFile, "ids", "", "sidecar file of stable property IDs written by the update-ids command, renamed properties keep their ID (id in the JSON output)")
+		cmd.PersistentFlags().StringVar(&userValuesFile, "user-values", "", "values file of a user (eg. of a release under review) to show next to the defaults, values that differ are highlighted (a column in the markdown-table template, userValue in the JSON output)")
+	}
+
+	Cmd.AddCommand(&Inject)
+	Inject.PersistentFlags().StringVarP(&templateName, "template", "t", "markdown-plain", "template to render documentation with")
+	Inject.PersistentFlags().StringVar(&audience, "audience", parser.AudiencePublic, "only include documentation meant for this audience")
+	Inject.PersistentFlags().StringArrayVar(&sections, "section", nil, "only include the section with this name (can be repeated)")
+	Inject.PersistentFlags().StringVarP(&targetFile, "output", "o", "README.md", "file to inject the generated markdown into")
+	Inject.PersistentFlags().Var(&headerSearch, "header-search", "set the regex used to match the start of the injected markdown")
+	Inject.PersistentFlags().Var(&footerSearch, "footer-search", "set the regex used to match the end of the injected markdown")
+	Inject.PersistentFlags().BoolVar(&chartHeader, "chart-header", false, "also inject a header rendered from the Chart.yaml file (name, version badges and install snippet) between the "+render.ChartHeaderStart+" and "+render.ChartHeaderEnd+" markers")
+	Inject.PersistentFlags().StringVar(&chartRepo, "chart-repo", "", "URL of the chart repository for the install snippet of the chart header, eg. https://charts.jetstack.io or oci://quay.io/jetstack/charts")
+	Inject.PersistentFlags().BoolVar(&createTarget, "create", false, "create the output file with a \""+render.DefaultHeader+"\" header if it does not exist")
+	Inject.PersistentFlags().BoolVar(&provenance, "provenance", false, "write a comment with the helm-tool version and the hash of the values file at the start of the injected markdown")
+	Inject.PersistentFlags().BoolVar(&verifyIdempotent, "verify-idempotent", false, "inject the documentation twice in memory first, and fail without changing the file if the second run changes the result (eg. if the documentation contains a line matching the footer)")
+	Inject.PersistentFlags().BoolVar(&provenanceTime, "provenance-timestamp", false, "also include the generation time in the provenance comment (makes the output non-reproducible)")
+
+	Cmd.AddCommand(&Render)
+	Render.PersistentFlags().StringVarP(&templateName, "template", "t", "markdown-plain", "template to render documentation with")
+	Render.PersistentFlags().StringVar(&audience, "audience", parser.AudiencePublic, "only include documentation meant for this audience")
+	Render.PersistentFlags().StringArrayVar(&sections, "section", nil, "only include the section with this name (can be repeated)")
+	Render.PersistentFlags().StringVar(&outputDir, "output-dir", "", "render every section to its own file in this directory instead of to stdout")
+	Render.PersistentFlags().StringVar(&fileNameTemplate, "file-name", "", "Go template of the file names of the sections with --output-dir, using .Name, .Slug and .Index (defaults to {{ .Slug }} with the extension of the template, eg. {{ .Slug }}.md)")
+
+	Cmd.AddCommand(&Show)
+	Show.PersistentFlags().StringVar(&audience, "audience", parser.AudiencePublic, "only include documentation meant for this audience")
+	Show.PersistentFlags().StringArrayVar(&sections, "section", nil, "only include the section with this name (can be repeated)")
+	Show.PersistentFlags().IntVar(&textWidth, "width", 0, "width to wrap the descriptions to (defaults to $COLUMNS, or 100)")
+	Show.PersistentFlags().StringVar(&textColor, "color", "auto", "highlight types and deprecated values using colors: auto (when writing to a terminal), always or never")
+
+	Cmd.AddCommand(&View)
+	View.PersistentFlags().StringVar(&audience, "audience", parser.AudiencePublic, "only include documentation meant for this audience")
+	View.PersistentFlags().StringArrayVar(&sections, "section", nil, "only include the section with this name (can be repeated)")
+	View.PersistentFlags().IntVar(&textWidth, "width", 0, "width to wrap the descriptions to (defaults to $COLUMNS, or 100)")
+	View.PersistentFlags().StringVar(&textColor, "color", "auto", "highlight types and deprecated values using colors: auto (when writing to a terminal), always or never")
+	View.PersistentFlags().StringVar(&pagerCommand, "pager", pager.Command(), "pager to view the documentation in, use cat to disable paging")
+
+	Cmd.AddCommand(&Schema)
+	Schema.PersistentFlags().BoolVar(&schemaOptions.Editor, "editor", false, "add Markdown descriptions, examples and deprecation messages for editors using the YAML language server")
+	Schema.PersistentFlags().BoolVar(&schemaOptions.Examples, "examples", false, "add the examples set using +docs:example tags as the examples keyword")
+	Schema.PersistentFlags().StringVar(&schemaOptions.Description, "description", schema.DescriptionFull, "amount of the descriptions used in the schema: full, paragraph (the first paragraph) or sentence (the first sentence), editors show long descriptions poorly")
+
+	Cmd.AddCommand(&Generate)
+	Generate.PersistentFlags().StringVarP(&templateName, "template", "t", "markdown-plain", "template to render documentation with")
+	Generate.PersistentFlags().StringVar(&audience, "audience", parser.AudiencePublic, "only include documentation meant for this audience")
+	Generate.PersistentFlags().StringVarP(&targetFile, "output", "o", "README.md", "file to inject the generated markdown into (empty to skip)")
+	Generate.PersistentFlags().Var(&headerSearch, "header-search", "set the regex used to match the start of the injected markdown")
+	Generate.PersistentFlags().Var(&footerSearch, "footer-search", "set the regex used to match the end of the injected markdown")
+	Generate.PersistentFlags().BoolVar(&chartHeader, "chart-header", false, "also inject a header rendered from the Chart.yaml file (name, version badges and install snippet) between the "+render.ChartHeaderStart+" and "+render.ChartHeaderEnd+" markers")
+	Generate.PersistentFlags().StringVar(&chartRepo, "chart-repo", "", "URL of the chart repository for the install snippet of the chart header, eg. https://charts.jetstack.io or oci://quay.io/jetstack/charts")
+	Generate.PersistentFlags().BoolVar(&createTarget, "create", false, "create the output file with a \""+render.DefaultHeader+"\" header if it does not exist")
+	Generate.PersistentFlags().BoolVar(&provenance, "provenance", false, "write a comment with the helm-tool version and the hash of the values file at the start of the injected markdown")
+	Generate.PersistentFlags().BoolVar(&verifyIdempotent, "verify-idempotent", false, "inject the documentation twice in memory first, and fail without changing the file if the second run changes the result (eg. if the documentation contains a line matching the footer)")
+	Generate.PersistentFlags().BoolVar(&provenanceTime, "provenance-timestamp", false, "also include the generation time in the provenance comment (makes the output non-reproducible)")
+	Generate.PersistentFlags().StringVar(&schemaFile, "schema-output", "values.schema.json", "file to write the JSON schema to (empty to skip)")
+	Generate.PersistentFlags().BoolVar(&schemaOptions.Editor, "editor", false, "add Markdown descriptions, examples and deprecation messages to the schema for editors using the YAML language server")
+	Generate.PersistentFlags().BoolVar(&schemaOptions.Examples, "schema-examples", false, "add the examples set using +docs:example tags to the schema as the examples keyword")
+	Generate.PersistentFlags().StringVar(&schemaOptions.Description, "schema-description", schema.DescriptionFull, "amount of the descriptions used in the schema: full, paragraph (the first paragraph) or sentence (the first sentence)")
+	Generate.PersistentFlags().BoolVar(&lintValues, "lint", true, "lint the values file against the templates")
+	Generate.PersistentFlags().StringVarP(&templatesFolder, "templates", "d", "templates", "templates folder used to lint the values file")
+	Generate.PersistentFlags().StringVarP(&exceptionsFile, "exceptions", "e", "", "file containing exceptions to the linting rules")
+	Generate.PersistentFlags().StringVar(&outputFormat, "lint-format", linter.FormatText, "format of the reported lint issues (text or github)")
+	Generate.PersistentFlags().BoolVar(&spelling, "spelling", false, "also check the spelling of the descriptions when linting")
+	Generate.PersistentFlags().StringArrayVar(&dictionaries, "dictionary", nil, "word list used by --spelling (can be repeated, defaults to "+linter.SystemDictionary+" if it exists)")
+	Generate.PersistentFlags().StringArrayVar(&policies, "policy", nil, "command evaluating a policy against the documentation, which is piped to it as JSON (can be repeated)")
+	Generate.PersistentFlags().StringVar(&readmeFile, "readme", "", "readme file in which references to values outside of the injected markdown are checked")
+	Generate.PersistentFlags().StringVar(&sinceRef, "since", "", "only generate if the chart (values file, templates or other inputs) changed since this git ref")
+	Generate.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "directory in which the outputs are cached by the hash of the inputs, unchanged charts are not parsed again")
+
+	Cmd.AddCommand(&TemplateLint)
+	TemplateLint.PersistentFlags().BoolVar(&printTemplate, "print", false, "write the template rendered against the sample document to stdout")
+
+	Cmd.AddCommand(&Fmt)
+	Fmt.PersistentFlags().BoolVarP(&formatWrite, "write", "w", false, "write the result to the values file instead of stdout")
+	Fmt.PersistentFlags().BoolVar(&formatCheck, "check", false, "exit with an error if the values file is not formatted")
+	Fmt.PersistentFlags().StringVar(&outputFormat, "format", linter.FormatText, "format of the --check result (text or github)")
+	Fmt.PersistentFlags().IntVar(&formatOptions.Width, "wrap", 0, "wrap comment lines longer than this width (0 disables wrapping)")
+	Fmt.PersistentFlags().BoolVar(&formatOptions.Structure, "structure", false, "also normalize the indentation, quote style and key ordering of the values")
+	Fmt.PersistentFlags().StringVar(&formatOptions.QuoteStyle, "quote-style", formatter.QuoteStylePreserve, "quote style for quoted strings when formatting the structure (double or single)")
+	Fmt.PersistentFlags().StringSliceVar(&formatOptions.SortKeys, "sort-keys", nil, "paths of the subtrees in which keys are sorted when formatting the structure (use . for the whole file)")
+
+	Cmd.AddCommand(&Sync)
+	Sync.PersistentFlags().StringArrayVar(&syncSources, "set", nil, "default to update, as <path>=<source>")
+	Sync.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the updated values file instead of writing it")
+
+	Cmd.AddCommand(&Rename)
+	Rename.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the updated values file instead of writing it, and the changed lines of the templates to stderr")
+	Rename.PersistentFlags().BoolVar(&updateTemplates, "update-templates", false, "also update the .Values references in the templates")
+	Rename.PersistentFlags().StringVarP(&templatesFolder, "templates", "d", "templates", "templates folder in which references are updated")
+
+	Cmd.AddCommand(&Migrate)
+	Migrate.PersistentFlags().StringVar(&migrateFrom, "from", "", "dialect of the annotations to migrate from (bitnami or helm-docs)")
+	Migrate.PersistentFlags().StringVar(&migrateTo, "to", "", "dialect to rewrite the +docs: tags into (bitnami or helm-docs)")
+	Migrate.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the updated values file instead of writing it")
+
+	Cmd.AddCommand(&Diff)
+	Diff.PersistentFlags().StringVar(&outputFormat, "format", diff.FormatText, "format of the changes (text, json-patch or jq)")
+
+	Cmd.AddCommand(&Owners)
+	Owners.PersistentFlags().StringVar(&outputFormat, "format", "text", "format of the report (text or json)")
+
+	Cmd.AddCommand(&Deprecations)
+	Deprecations.PersistentFlags().StringVar(&outputFormat, "format", "text", "format of the report (text, markdown or json)")
+
+	Cmd.AddCommand(&Redirects)
+	Redirects.PersistentFlags().StringVar(&redirectFormat, "format", "json", "format of the redirects (json or html)")
+
+	Cmd.AddCommand(&Tags)
+	Tags.PersistentFlags().StringVar(&outputFormat, "format", "text", "format of the report (text or json)")
+
+	Cmd.AddCommand(&Index)
+	Index.PersistentFlags().StringVar(&indexDocs, "docs", "README.md", "file containing the generated documentation of each chart, relative to the chart directory")
+	Index.PersistentFlags().StringVar(&indexTitle, "title", "Charts", "title of the index page")
+	Index.PersistentFlags().StringVarP(&indexOutput, "output", "o", "", "file to write the index page to, the links to the documentation are relative to its directory (defaults to stdout)")
+
+	Cmd.AddCommand(&Consistency)
+	Consistency.PersistentFlags().StringArrayVar(&comparePaths, "path", nil, "only compare the values under this path (can be repeated)")
+	Consistency.PersistentFlags().StringVar(&outputFormat, "format", "text", "format of the report (text or json)")
+
+	Cmd.AddCommand(&TelemetryIDs)
+	TelemetryIDs.PersistentFlags().StringVar(&idsFile, "ids", "", "sidecar file of stable property IDs written by the update-ids command, the IDs of the values are read from it")
+
+	Cmd.AddCommand(&UpdateIDs)
+
+	Cmd.AddCommand(&Site)
+	Site.PersistentFlags().StringVar(&siteDir, "output-dir", "site", "directory to write the site to")
+	Site.PersistentFlags().StringVar(&siteTitle, "title", "", "title of the site (defaults to the +docs:title tag or the name of the chart)")
+	Site.PersistentFlags().StringVar(&audience, "audience", parser.AudiencePublic, "only include documentation meant for this audience")
+	Site.PersistentFlags().BoolVar(&renderOptions.LinkTypes, "link-types", false, "render Kubernetes and custom type names as links to their documentation")
+	Site.PersistentFlags().StringToStringVar(&renderOptions.TypeLinks, "type-link", nil, "link a type name to the URL of its documentation (eg. MyType=https://example.com/docs#mytype)")
+	Site.PersistentFlags().StringVar(&renderOptions.ArrayIndex, "array-index", render.ArrayIndexNumber, "how array indices in property paths are displayed: number (args[0]), empty (args[]) or wildcard (args[*])")
+	Site.PersistentFlags().BoolVar(&renderOptions.AdvancedAppendix, "advanced-appendix", false, "move the values marked using +docs:advanced to an appendix page")
+
+	Cmd.AddCommand(&MkDocs)
+	MkDocs.PersistentFlags().StringVar(&mkdocsDir, "output-dir", "docs", "directory to write the pages to")
+	MkDocs.PersistentFlags().StringVar(&navPrefix, "nav-prefix", "", "path of the output directory relative to the docs_dir of the site, used for the pages in the nav (eg. reference/values)")
+	MkDocs.PersistentFlags().StringVar(&navFile, "nav-file", "", "file to write the nav fragment to (defaults to stdout)")
+	MkDocs.PersistentFlags().StringVar(&siteTitle, "title", "", "title of the pages in the nav (defaults to the +docs:title tag or the name of the chart)")
+	MkDocs.PersistentFlags().StringVarP(&templateName, "template", "t", "markdown-plain", "template to render the pages with")
+	MkDocs.PersistentFlags().StringVar(&audience, "audience", parser.AudiencePublic, "only include documentation meant for this audience")
+	MkDocs.PersistentFlags().BoolVar(&renderOptions.LinkTypes, "link-types", false, "render Kubernetes and custom type names as links to their documentation")
+	MkDocs.PersistentFlags().StringToStringVar(&renderOptions.TypeLinks, "type-link", nil, "link a type name to the URL of its documentation (eg. MyType=https://example.com/docs#mytype)")
+	MkDocs.PersistentFlags().StringVar(&renderOptions.ArrayIndex, "array-index", render.ArrayIndexNumber, "how array indices in property paths are displayed: number (args[0]), empty (args[]) or wildcard (args[*])")
+	MkDocs.PersistentFlags().BoolVar(&renderOptions.AdvancedAppendix, "advanced-appendix", false, "move the values marked using +docs:advanced to an appendix page")
+
+	Cmd.AddCommand(&Helmfile)
+	Helmfile.PersistentFlags().StringVarP(&environment, "environment", "e", helmfile.DefaultEnvironment, "environment of the helmfile to use")
+
+	Cmd.AddCommand(&CompletionData)
+
+	Cmd.AddCommand(&CompleteSet)
+	CompleteSet.PersistentFlags().StringVar(&completionData, "data", "", "completion data written by completion-data, the values file is used if not set")
+
+	Cmd.AddCommand(&LSP)
+	LSP.PersistentFlags().StringVarP(&templatesFolder, "templates", "d", "templates", "templates folder used to lint the values file")
+	LSP.PersistentFlags().StringVarP(&exceptionsFile, "exceptions", "e", "", "file containing exceptions to the linting rules")
+
+	Cmd.AddCommand(&Lint)
+	Lint.PersistentFlags().StringVarP(&templatesFolder, "templates", "d", "templates", "templates folder used to lint the values file")
+	Lint.PersistentFlags().StringVarP(&exceptionsFile, "exceptions", "e", "", "file containing exceptions to the linting rules")
+	Lint.PersistentFlags().StringVar(&outputFormat, "format", linter.FormatText, "format of the reported issues (text or github)")
+	Lint.PersistentFlags().StringVar(&readmeFile, "readme", "", "readme file in which references to values outside of the injected markdown are checked")
+	Lint.PersistentFlags().Var(&headerSearch, "header-search", "set the regex used to match the start of the injected markdown")
+	Lint.PersistentFlags().Var(&footerSearch, "footer-search", "set the regex used to match the end of the injected markdown")
+	Lint.PersistentFlags().BoolVar(&spelling, "spelling", false, "also check the spelling of the descriptions")
+	Lint.PersistentFlags().StringArrayVar(&dictionaries, "dictionary", nil, "word list used by --spelling, eg. a list of project words such as cainjector (can be repeated, defaults to "+linter.SystemDictionary+" if it exists)")
+	Lint.PersistentFlags().StringArrayVar(&policies, "policy", nil, "command evaluating a policy against the documentation, which is piped to it as JSON, eg. \"opa eval --stdin-input --data policy.rego --format raw data.values.deny\" (can be repeated)")
+}
+
+// applyConfig applies the settings from the config file, flags that were
+// explicitly set take precedence over the config file.
+func applyConfig(cmd *cobra.Command, cfg *config.Config) error {
+	configStrings := []struct {
+		flag   string
+		target *string
+		value  string
+	}{
+		{"values", &valuesFile, cfg.Values},
+		{"template", &templateName, cfg.Template},
+		{"audience", &audience, cfg.Audience},
+		{"tag-prefix", &tagPrefix, cfg.TagPrefix},
+		{"dialect", &dialect, cfg.Dialect},
+		{"unknown-type", &unknownType, cfg.UnknownType},
+		{"templates", &templatesFolder, cfg.Lint.Templates},
+		{"exceptions", &exceptionsFile, cfg.Lint.Exceptions},
+		{"readme", &readmeFile, cfg.Lint.Readme},
+		{"owners", &ownersFile, cfg.Owners},
+		{"front-matter-format", &renderOptions.FrontMatterFormat, cfg.FrontMatterFormat},
+		{"table-shortcode", &renderOptions.TableShortcode, cfg.TableShortcode},
+		{"sort", &renderOptions.Sort, cfg.Sort},
+		{"locale", &renderOptions.Locale, cfg.Locale},
+		{"bool-format", &renderOptions.BoolFormat, cfg.BoolFormat},
+	}
+	for _, setting := range configStrings {
+		if !cmd.Flags().Changed(setting.flag) && setting.value != "" {
+			*setting.target = setting.value
+		}
+	}
+
+	// The output is the file the inject and generate commands write to, the
+	// other commands share the variable
+	if (cmd == &Inject || cmd == &Generate) && !cmd.Flags().Changed("output") && cfg.Output != "" {
+		targetFile = cfg.Output
+	}
+
+	if dialect == parser.DialectHelmDocs && !cmd.Flags().Changed("header-search") {
+		headerSearch.regexp = helmDocsHeaderSearch
+	}
+
+	if !cmd.Flags().Changed("link-types") && cfg.LinkTypes != nil {
+		renderOptions.LinkTypes = *cfg.LinkTypes
+	}
+
+	if !cmd.Flags().Changed("same-as-anchors") && cfg.SameAsAnchors != nil {
+		renderOptions.SameAsAnchors = *cfg.SameAsAnchors
+	}
+	if !cmd.Flags().Changed("property-anchors") && cfg.PropertyAnchors != nil {
+		renderOptions.PropertyAnchors = *cfg.PropertyAnchors
+	}
+
+	if !cmd.Flags().Changed("spelling") && cfg.Lint.Spelling != nil {
+		spelling = *cfg.Lint.Spelling
+	}
+	if !cmd.Flags().Changed("dictionary") && len(cfg.Lint.Dictionaries) > 0 {
+		dictionaries = cfg.Lint.Dictionaries
+	}
+	if !cmd.Flags().Changed("policy") && len(cfg.Lint.Policies) > 0 {
+		policies = cfg.Lint.Policies
+	}
+	if !cmd.Flags().Changed("columns") && len(cfg.Columns) > 0 {
+		renderOptions.Columns = cfg.Columns
+	}
+	if !cmd.Flags().Changed("post-render") && len(cfg.PostRender) > 0 {
+		postRender = cfg.PostRender
+	}
+
+	// The post-render commands are only run by the commands writing the
+	// rendered documentation. They run next to the written file, so they find
+	// its configuration files (eg. .prettierrc)
+	if cmd.Flags().Lookup("post-render") != nil {
+		dir := "."
+		switch {
+		case cmd == &Inject || cmd == &Generate:
+			dir = filepath.Dir(targetFile)
+		case outputDir != "":
+			dir = outputDir
+		}
+
+		for _, command := range postRender {
+			renderOptions.PostRender = append(renderOptions.PostRender, render.CommandHook(command, dir, render.OutputExtension(templateName, renderOptions.Format)))
+		}
+	}
+
+	if !cmd.Flags().Changed("wrap") && cfg.Format.Wrap != 0 {
+		formatOptions.Width = cfg.Format.Wrap
+	}
+	if !cmd.Flags().Changed("structure") && cfg.Format.Structure != nil {
+		formatOptions.Structure = *cfg.Format.Structure
+	}
+	if !cmd.Flags().Changed("quote-style") && cfg.Format.QuoteStyle != "" {
+		formatOptions.QuoteStyle = cfg.Format.QuoteStyle
+	}
+	if !cmd.Flags().Changed("sort-keys") && len(cfg.Format.SortKeys) > 0 {
+		formatOptions.SortKeys = cfg.Format.SortKeys
+	}
+
+	if cmd == &Sync && !cmd.Flags().Changed("set") {
+		for path, source := range cfg.Sync {
+			syncSources = append(syncSources, path+"="+source)
+		}
+		slices.Sort(syncSources)
+	}
+
+	for key, value := range cfg.FrontMatter {
+		if renderOptions.FrontMatter == nil {
+			renderOptions.FrontMatter = map[string]string{}
+		}
+
+		if _, ok := renderOptions.FrontMatter[key]; !ok {
+			renderOptions.FrontMatter[key] = value
+		}
+	}
+
+	for typeName, link := range cfg.TypeLinks {
+		if renderOptions.TypeLinks == nil {
+			renderOptions.TypeLinks = map[string]string{}
+		}
+
+		if _, ok := renderOptions.TypeLinks[typeName]; !ok {
+			renderOptions.TypeLinks[typeName] = link
+		}
+	}
+
+	return nil
+}
+
+// injectDocumentation renders the document and injects it into the target
+// file, using the inject flags.
+func injectDocumentation(document *parser.Document) {
+	stop := runTimings.Start("render")
+	rendered, err := render.RenderWithOptions(templateName, document.ForAudience(audience), renderOptions)
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering template: %s\n", err)
+		exit(1)
+	}
+
+	injectRendered(rendered)
+}
+
+// injectRendered injects the rendered documentation into the target file.
+func injectRendered(rendered string) {
+	if provenance {
+		contents, err := os.ReadFile(valuesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open %q: %s\n", valuesFile, err)
+			exit(1)
+		}
+
+		renderOptions.Provenance = render.NewProvenance(version.Version(), filepath.ToSlash(valuesFile), contents)
+		if provenanceTime {
+			renderOptions.Provenance.Timestamp = time.Now()
+		}
+	}
+
+	createInjectTarget := render.CreateInjectTarget
+	if chartHeader {
+		createInjectTarget = render.CreateChartHeaderTarget
+	}
+
+	if createTarget {
+		if err := createInjectTarget(targetFile, headerSearch.regexp); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not create %q: %s\n", targetFile, err)
+			exit(1)
+		}
+	}
+
+	// The chart header is injected before the documentation
+	var injections []render.Injection
+	if chartHeader {
+		header, err := renderChartHeader()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not inject the chart header into %q (between %q and %q): %s\n", targetFile, render.ChartHeaderStart, render.ChartHeaderEnd, err)
+			exit(1)
+		}
+
+		injections = append(injections, render.Injection{RenderedDocument: header, HeaderMatch: render.ChartHeaderStartMatch, FooterMatch: render.ChartHeaderEndMatch})
+	}
+
+	before, _ := os.ReadFile(targetFile)
+	if verifyIdempotent {
+		documentation := render.Injection{RenderedDocument: rendered, HeaderMatch: headerSearch.regexp, FooterMatch: footerSearch.regexp, Provenance: renderOptions.Provenance}
+		if err := render.VerifyIdempotent(string(before), append(injections, documentation)...); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not inject markdown into %q: %s\n", targetFile, err)
+			exit(1)
+		}
+	}
+
+	for _, injection := range injections {
+		if err := render.InjectRendered(targetFile, injection.RenderedDocument, injection.HeaderMatch, injection.FooterMatch, injection.Provenance); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not inject the chart header into %q (between %q and %q): %s\n", targetFile, render.ChartHeaderStart, render.ChartHeaderEnd, err)
+			exit(1)
+		}
+	}
+
+	stop := runTimings.Start("inject")
+	err := render.InjectRendered(targetFile, rendered, headerSearch.regexp, footerSearch.regexp, renderOptions.Provenance)
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could inject markdown into %q: %s\n", targetFile, err)
+		if errors.Is(err, render.ErrReadOnly) {
+			exit(exitCodeReadOnly)
+		}
+		exit(1)
+	}
+
+	after, _ := os.ReadFile(targetFile)
+	runSummary.FileWritten(targetFile, before, after)
+}
+
+// renderChartHeader renders the header from the Chart.yaml file next to the
+// values file, which is injected between the chart header markers of the
+// target file.
+func renderChartHeader() (string, error) {
+	chart, err := parser.LoadChart(filepath.Dir(valuesFile))
+	if err != nil {
+		return "", err
+	}
+
+	if chart == nil {
+		return "", fmt.Errorf("there is no Chart.yaml file next to %q", valuesFile)
+	}
+
+	return render.RenderChartHeader(chart, chartRepo), nil
+}
+
+// lintDocument lints the document against the templates (and the readme if
+// set) and prints the issues, it returns the number of issues found.
+func lintDocument(document *parser.Document) int {
+	defer runTimings.Start("lint")()
+
+	issues, err := linter.Lint(templatesFolder, exceptionsFile, document)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not lint: %s\n", err)
+		exit(1)
+	}
+
+	if readmeFile != "" {
+		readmeIssues, err := linter.LintReadme(readmeFile, headerSearch.regexp, footerSearch.regexp, exceptionsFile, document)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not lint: %s\n", err)
+			exit(1)
+		}
+
+		issues = append(issues, readmeIssues...)
+	}
+
+	if spelling {
+		spellingIssues, err := lintSpelling(document)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not check spelling: %s\n", err)
+			exit(1)
+		}
+
+		issues = append(issues, spellingIssues...)
+	}
+
+	if len(policies) > 0 {
+		var checks []linter.Policy
+		for _, command := range policies {
+			checks = append(checks, linter.ExecPolicy{Command: command})
+		}
+
+		policyIssues, err := linter.LintPolicies(document, checks, exceptionsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not check policies: %s\n", err)
+			exit(1)
+		}
+
+		issues = append(issues, policyIssues...)
+	}
+
+	runSummary.LintIssuesFound(len(issues))
+	if err := linter.PrintIssues(os.Stdout, outputFormat, valuesFile, issues); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not print issues: %s\n", err)
+		exit(1)
+	}
+
+	return len(issues)
+}
+
+// lintSpelling checks the spelling of the descriptions using the
+// dictionaries, or the system dictionary if none are set.
+func lintSpelling(document *parser.Document) ([]linter.Issue, error) {
+	dictionary, err := linter.LoadDictionary(spellingDictionaries()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return linter.LintSpelling(document, dictionary, exceptionsFile)
+}
+
+// spellingDictionaries returns the word lists used by --spelling.
+func spellingDictionaries() []string {
+	if len(dictionaries) > 0 {
+		return dictionaries
+	}
+
+	if _, err := os.Stat(linter.SystemDictionary); err == nil {
+		return []string{linter.SystemDictionary}
+	}
+
+	return nil
+}
+
+// loadDocument loads the values file and applies any configured overrides.
+func loadDocument(includeHidden bool) (*parser.Document, error) {
+	if useSample {
+		return parser.SampleDocument(), nil
+	}
+
+	document, err := parser.LoadWithOptions(valuesFile, parser.Options{
+		IncludeHidden: includeHidden,
+		TagPrefix:     tagPrefix,
+		Dialect:       dialect,
+		StrictTags:    strictTags,
+		UnknownType:   unknownType,
+		StrictTypes:   strictTypes,
+		Timings:       runTimings,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if overridesFile != "" {
+		overrides, err := parser.LoadOverrides(overridesFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load overrides %q: %w", overridesFile, err)
+		}
+
+		if err := document.ApplyOverrides(overrides); err != nil {
+			return nil, err
+		}
+	}
+
+	if ownersFile != "" {
+		rules, err := parser.LoadOwners(ownersFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load owners %q: %w", ownersFile, err)
+		}
+
+		document.ApplyOwners(rules)
+	}
+
+	if renderOptions.LastCommits {
+		if err := setLastCommits(document); err != nil {
+			return nil, fmt.Errorf("could not blame %q: %w", valuesFile, err)
+		}
+	}
+
+	if userValuesFile != "" {
+		values, err := parser.LoadUserValues(userValuesFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load user values %q: %w", userValuesFile, err)
+		}
+
+		for _, path := range document.SetUserValues(values) {
+			log.Printf("%s: %s is not a documented value\n", userValuesFile, path)
+		}
+		renderOptions.UserValues = true
+	}
+
+	if idsFile != "" {
+		if _, _, err := setIDs(document, idsFile); err != nil {
+			return nil, fmt.Errorf("could not set the property IDs of %q: %w", idsFile, err)
+		}
+	}
+
+	return document, nil
+}
+
+// setIDs sets the stable IDs of the properties from the sidecar file, it
+// returns the IDs with the properties that are seen for the first time added
+// with the version of the chart, and whether these need to be saved using
+// the update-ids command.
+func setIDs(document *parser.Document, filename string) (*parser.PropertyIDs, bool, error) {
+	ids, err := parser.LoadPropertyIDs(filename)
+	if err != nil {
+		return nil, false, err
+	}
+
+	version := ""
+	if document.Chart != nil {
+		version = document.Chart.Version
+	}
+
+	changed := document.SetIDs(ids, version)
+	return ids, changed, nil
+}
+
+// setLastCommits sets the last commit that changed each property using the
+// git blame of the values file, the URL of the commits is rendered using the
+// --blame-link template.
+func setLastCommits(document *parser.Document) error {
+	defer runTimings.Start("blame")()
+
+	contents, err := os.ReadFile(valuesFile)
+	if err != nil {
+		return err
+	}
+
+	blamed, err := git.Blame(valuesFile)
+	if err != nil {
+		return err
+	}
+
+	var link *template.Template
+	if blameLink != "" {
+		if link, err = template.New("blame-link").Parse(blameLink); err != nil {
+			return fmt.Errorf("invalid --blame-link: %w", err)
+		}
+	}
+
+	byHash := map[string]*parser.Commit{}
+	commits := make([]*parser.Commit, len(blamed))
+	for i, blame := range blamed {
+		if !blame.Committed() {
+			continue
+		}
+
+		commit, ok := byHash[blame.Hash]
+		if !ok {
+			commit = &parser.Commit{
+				Hash:    blame.Hash,
+				Date:    blame.Date,
+				Summary: blame.Summary,
+				PR:      blame.PullRequest(),
+			}
+
+			if link != nil {
+				var sb strings.Builder
+				if err := link.Execute(&sb, commit); err != nil {
+					return fmt.Errorf("invalid --blame-link: %w", err)
+				}
+				commit.URL = sb.String()
+			}
+
+			byHash[blame.Hash] = commit
+		}
+
+		commits[i] = commit
+	}
+
+	document.SetLastCommits(contents, func(line int) *parser.Commit {
+		if line > len(commits) {
+			return nil
+		}
+		return commits[line-1]
+	})
+
+	return nil
+}
+
+func main() {
+	// Cobra prints the error, eg. of loading the config file in
+	// PersistentPreRunE
+	if err := Cmd.Execute(); err != nil {
+		exit(1)
+	}
+}
+
+// exit writes the run summary (if requested) and exits with the code.
+func exit(code int) {
+	writeTimings()
+	writeSummary(code)
+	os.Exit(code)
+}
+
+// writeTimings writes the timings of the phases to stderr if --debug-timings
+// is set.
+func writeTimings() {
+	if err := runTimings.Write(os.Stderr, valuesFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not write timings: %s\n", err)
+	}
+}
+
+func writeSummary(code int) {
+	if summaryFile == "" {
+		return
+	}
+
+	runSummary.Finish(code)
+	if err := runSummary.WriteFile(summaryFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not write summary %q: %s\n", summaryFile, err)
+	}
+}
+
+// firstDifferentLine returns the first line (starting at 1) that differs
+// between a and b.
+func firstDifferentLine(a, b []byte) int {
+	line := 1
+	for i := 0; i < len(a) && i < len(b) && a[i] == b[i]; i++ {
+		if a[i] == '\n' {
+			line++
+		}
+	}
+
+	return line
+}
+
+// relativePath returns the path of target relative to the base directory,
+// the paths can be absolute or relative to the current directory.
+func relativePath(base, target string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Rel(absBase, absTarget)
+}
+
+// writeFile writes the content to the file and records the change in the run
+// summary.
+func writeFile(path string, content []byte) error {
+	before, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+
+	runSummary.FileWritten(path, before, content)
+	return nil
+}
+
+type regexValue struct {
+	regexp *regexp.Regexp
+}
+
+func (r *regexValue) String() string {
+	return r.regexp.String()
+}
+
+func (r *regexValue) Set(value string) error {
+	compiled, err := regexp.Compile("(?m)" + value)
+	if err != nil {
+		return err
+	}
+
+	if err := render.CheckSingleLine(compiled); err != nil {
 		return err
 	}
 
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+// AdvancedSectionName is the name of the section WithAdvancedAppendix moves
+// the advanced properties to.
+const AdvancedSectionName = "Advanced"
+
+// WithAdvancedAppendix returns a copy of the document in which the properties
+// marked using a +docs:advanced tag are moved from their sections to an
+// appendix section at the end of the document, so the main documentation
+// stays focused on the commonly used values. Sections that only contained
+// advanced properties are removed, unless they have a description.
+func (d *Document) WithAdvancedAppendix() *Document {
+	result := *d
+	result.Sections = nil
+
+	appendix := Section{Name: AdvancedSectionName, Appendix: true}
+	for _, section := range d.Sections {
+		properties := section.Properties
+		section.Properties = nil
+		for _, property := range properties {
+			if property.Advanced {
+				appendix.Properties = append(appendix.Properties, property)
+			} else {
+				section.Properties = append(section.Properties, property)
+			}
+		}
+
+		if len(properties) > 0 && len(section.Properties) == 0 && section.Description.String() == "" {
+			continue
+		}
+
+		result.Sections = append(result.Sections, section)
+	}
+
+	if len(appendix.Properties) > 0 {
+		result.Sections = append(result.Sections, appendix)
+	}
+
+	return &result
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAdvancedAppendix(t *testing.T) {
+	values := `# +docs:section=Main
+
+# Replicas
+replicaCount: 1
+# Tuning of the client
+# +docs:advanced
+tuning:
+  # QPS
+  qps: 5
+  # Burst
+  burst: 10
+
+# +docs:section=Internals
+# +docs:advanced
+
+# Debug logging
+debug: false
+
+# +docs:section=Experimental
+# These values may change.
+
+# Feature gates
+# +docs:advanced
+featureGates: ""
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	sectionPaths := map[string][]string{}
+	var appendix []string
+	for _, section := range document.WithAdvancedAppendix().Sections {
+		for _, property := range section.Properties {
+			sectionPaths[section.Name] = append(sectionPaths[section.Name], property.Path.String())
+		}
+		if section.Appendix {
+			appendix = append(appendix, section.Name)
+		}
+	}
+
+	// The Internals section only contained advanced properties, the
+	// Experimental section is kept for its description
+	require.Equal(t, map[string][]string{
+		"Main":     {"replicaCount"},
+		"Advanced": {"tuning.qps", "tuning.burst", "debug", "featureGates"},
+	}, sectionPaths)
+	require.Equal(t, []string{"Advanced"}, appendix)
+	require.Len(t, document.WithAdvancedAppendix().Sections, 4)
+
+	// The document itself is not modified
+	require.Len(t, document.Sections, 4)
+	require.Len(t, document.Sections[1].Properties, 3)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import "fmt"
+
+// setAnchors sets the Anchor of the properties, the anchors of properties
+// whose paths have the same anchor get a number so links to them are not
+// ambiguous.
+func (d *Document) setAnchors() {
+	used := map[string]bool{}
+	for i := range d.Sections {
+		for j := range d.Sections[i].Properties {
+			property := &d.Sections[i].Properties[j]
+
+			base := property.Path.Anchor()
+			anchor := base
+			for n := 2; used[anchor]; n++ {
+				anchor = fmt.Sprintf("%s-%d", base, n)
+			}
+
+			used[anchor] = true
+			property.Anchor = anchor
+		}
+	}
+}
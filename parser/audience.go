@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+// AudiencePublic is the audience of sections and properties without a
+// +docs:audience tag, public documentation is included for every audience.
+const AudiencePublic = "public"
+
+// ForAudience returns a copy of the document only containing the sections and
+// properties that are meant for the given audience. Sections and properties
+// without a +docs:audience tag are considered public and are always included.
+// The anchors of the properties are set again, so they do not depend on the
+// excluded properties.
+func (d *Document) ForAudience(audience string) *Document {
+	result := *d
+	result.Sections = nil
+
+	for _, section := range d.Sections {
+		if !section.Description.isForAudience(audience) {
+			continue
+		}
+
+		properties := section.Properties
+		section.Properties = nil
+		for _, property := range properties {
+			if property.Description.isForAudience(audience) {
+				section.Properties = append(section.Properties, property)
+			}
+		}
+
+		result.Sections = append(result.Sections, section)
+	}
+
+	// The anchors of the excluded properties can be reused
+	result.setAnchors()
+
+	return &result
+}
+
+// WithoutHidden returns a copy of the document without the properties that
+// are hidden using a +docs:hidden tag, this is the document that would have
+// been parsed without IncludeHidden, including the anchors of the properties.
+func (d *Document) WithoutHidden() *Document {
+	result := *d
+	result.Sections = make([]Section, len(d.Sections))
+
+	for i, section := range d.Sections {
+		properties := section.Properties
+		section.Properties = nil
+		for _, property := range properties {
+			if !property.Hidden {
+				section.Properties = append(section.Properties, property)
+			}
+		}
+
+		result.Sections[i] = section
+	}
+
+	result.setAnchors()
+
+	return &result
+}
+
+func (c Comment) isForAudience(audience string) bool {
+	switch c.Tags.GetString(TagAudience) {
+	case "", AudiencePublic, audience:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// bitnamiAnnotationExp matches the annotations of Bitnami's
+// readme-generator-for-helm, eg. "## @param image.tag Image tag".
+var bitnamiAnnotationExp = regexp.MustCompile(`^\s*#+\s*@(param|extra|skip|section|descriptionStart|descriptionEnd)\b\s*(.*)$`)
+
+// parseBitnami parses the documentation from the annotations used by Bitnami's
+// readme-generator-for-helm: "## @section", "## @param", "## @extra" and
+// "## @skip". The annotations refer to the values by their full path, so they
+// are read from the file line by line and the values are looked up in the
+// parsed yaml.
+func parseBitnami(contents []byte, root *yaml.Node) (*Document, error) {
+	nodes := map[string]Node{}
+	if err := walk(Node{RawNode: root}, func(node Node) (bool, error) {
+		nodes[node.Path.String()] = node
+		return false, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	document := Document{Sections: make([]Section, 1)}
+	var skipped []paths.Path
+	var description []string
+	inDescription := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		match := bitnamiAnnotationExp.FindStringSubmatch(line)
+		if match == nil {
+			if inDescription {
+				description = append(description, strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#")))
+			}
+			continue
+		}
+
+		annotation, value := match[1], strings.TrimSpace(match[2])
+		switch annotation {
+		case "section":
+			document.Sections = append(document.Sections, Section{Name: value})
+
+		case "descriptionStart":
+			inDescription = true
+			description = nil
+
+		case "descriptionEnd":
+			inDescription = false
+			document.Sections[len(document.Sections)-1].Description = parseText(strings.Join(description, "\n"))
+
+		case "skip":
+			path, err := paths.Parse(value)
+			if err != nil {
+				log.Printf("could not parse @skip path %q: %s\n", value, err)
+				continue
+			}
+			skipped = append(skipped, path)
+
+		case "param", "extra":
+			property, ok := parseBitnamiParam(annotation, value, nodes)
+			if !ok {
+				continue
+			}
+
+			sectionIdx := len(document.Sections) - 1
+			document.Sections[sectionIdx].Properties = append(document.Sections[sectionIdx].Properties, property)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Remove the properties that are part of skipped values
+	for i := range document.Sections {
+		properties := document.Sections[i].Properties[:0]
+		for _, property := range document.Sections[i].Properties {
+			if !isUnderAny(property.Path, skipped) {
+				properties = append(properties, property)
+			}
+		}
+		document.Sections[i].Properties = properties
+	}
+
+	return &document, nil
+}
+
+// parseBitnamiParam parses the value of a @param or @extra annotation into a
+// property, the type and default are read from the value in the values file
+// unless they are set using modifiers. Only @param annotations are expected to
+// match a value, @extra annotations document values that are not in the file.
+func parseBitnamiParam(annotation, value string, nodes map[string]Node) (Property, bool) {
+	pathString, rest, _ := strings.Cut(value, " ")
+	modifiers, description := splitBitnamiModifiers(strings.TrimSpace(rest))
+
+	path, err := paths.Parse(pathString)
+	if err != nil {
+		log.Printf("could not parse @%s path %q: %s\n", annotation, pathString, err)
+		return Property{}, false
+	}
+
+	property := Property{
+		Path:        path,
+		Description: parseText(description),
+		Type:        TypeUnknown,
+	}
+
+	node, ok := nodes[path.String()]
+	if ok {
+		property.Type = getTypeOf(node, Comment{})
+		property.Default = getDefaultValue(node, Comment{})
+		property.Line = node.Line
+	} else if annotation == "param" {
+		log.Printf("@param %q does not match any value\n", pathString)
+	}
+
+	for _, modifier := range modifiers {
+		modifier = strings.TrimSpace(modifier)
+		switch {
+		case modifier == "array":
+			property.Type = TypeArray
+		case modifier == "object":
+			property.Type = TypeObject
+		case modifier == "string":
+			property.Type = TypeString
+		case strings.HasPrefix(modifier, "default:"):
+			property.Default = strings.TrimSpace(strings.TrimPrefix(modifier, "default:"))
+		}
+	}
+
+	return property, true
+}
+
+// splitBitnamiModifiers splits the optional modifiers in square brackets at the
+// start of the text from the description, eg. "[array, default: [a, b]] Extra
+// arguments". Modifiers can contain brackets themselves.
+func splitBitnamiModifiers(text string) ([]string, string) {
+	if !strings.HasPrefix(text, "[") {
+		return nil, text
+	}
+
+	var modifiers []string
+	depth, start := 0, 1
+	for i, r := range text {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				modifiers = append(modifiers, strings.TrimSpace(text[start:i]))
+				return modifiers, strings.TrimSpace(text[i+1:])
+			}
+		case ',':
+			if depth == 1 {
+				modifiers = append(modifiers, strings.TrimSpace(text[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	// Unbalanced brackets, treat everything as description
+	return nil, text
+}
+
+func isUnderAny(path paths.Path, parents []paths.Path) bool {
+	for _, parent := range parents {
+		if parent.IsSubPathOf(path) {
+			return true
+		}
+	}
+
+	return false
+}
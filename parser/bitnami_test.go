@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const bitnamiValues = `## @section Global parameters
+## @descriptionStart
+## Parameters that are shared by all components.
+## @descriptionEnd
+
+## @param global.imageRegistry Global Docker image registry
+##
+global:
+  imageRegistry: ""
+
+## @section Image parameters
+
+## @param image.registry Image registry
+## @param image.tag Image tag
+## @skip image.pullSecrets
+## @param image.pullSecrets.names Not documented
+## @param extraArgs [array, default: ["--v=2", "--a"]] Extra arguments
+## @extra metrics.port [string] Port of the metrics endpoint
+image:
+  registry: docker.io
+  tag: 1.2.3
+  pullSecrets:
+    names: []
+extraArgs: null
+`
+
+func TestParseBitnami(t *testing.T) {
+	document, err := ParseWithOptions(strings.NewReader(bitnamiValues), t.TempDir(), Options{Dialect: DialectBitnami})
+	require.NoError(t, err)
+
+	type property struct {
+		path, description, typ, def string
+	}
+
+	var sections []string
+	var properties []property
+	for _, section := range document.Sections {
+		sections = append(sections, section.Name+": "+section.Description.String())
+		for _, p := range section.Properties {
+			properties = append(properties, property{p.Path.String(), p.Description.String(), string(p.Type), p.Default})
+		}
+	}
+
+	require.Equal(t, []string{
+		": ",
+		"Global parameters: Parameters that are shared by all components.",
+		"Image parameters: ",
+	}, sections)
+
+	require.Equal(t, []property{
+		{"global.imageRegistry", "Global Docker image registry", "string", `""`},
+		{"image.registry", "Image registry", "string", "docker.io"},
+		{"image.tag", "Image tag", "string", "1.2.3"},
+		{"extraArgs", "Extra arguments", "array", `["--v=2", "--a"]`},
+		{"metrics.port", "Port of the metrics endpoint", "string", ""},
+	}, properties)
+}
+
+func TestSplitBitnamiModifiers(t *testing.T) {
+	modifiers, description := splitBitnamiModifiers("[array, nullable] Extra [arguments]")
+	require.Equal(t, []string{"array", "nullable"}, modifiers)
+	require.Equal(t, "Extra [arguments]", description)
+
+	modifiers, description = splitBitnamiModifiers("Extra arguments")
+	require.Nil(t, modifiers)
+	require.Equal(t, "Extra arguments", description)
+
+	modifiers, description = splitBitnamiModifiers("[unbalanced")
+	require.Nil(t, modifiers)
+	require.Equal(t, "[unbalanced", description)
+}
+
+func TestUnknownDialect(t *testing.T) {
+	_, err := ParseWithOptions(strings.NewReader(bitnamiValues), t.TempDir(), Options{Dialect: "unknown"})
+	require.Error(t, err)
+}
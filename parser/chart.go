@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Chart contains the metadata from a chart's Chart.yaml file. The field names
+// match the ones Helm exposes to templates as .Chart.
+type Chart struct {
+	Name        string `yaml:"name" json:"name,omitempty"`
+	Version     string `yaml:"version" json:"version,omitempty"`
+	AppVersion  string `yaml:"appVersion" json:"appVersion,omitempty"`
+	Description string `yaml:"description" json:"description,omitempty"`
+	Home        string `yaml:"home" json:"home,omitempty"`
+	Icon        string `yaml:"icon" json:"icon,omitempty"`
+
+	Dependencies []Dependency `yaml:"dependencies" json:"dependencies,omitempty"`
+}
+
+// Dependency is a subchart of a chart, its values are set under its alias or
+// name in the values file of the chart.
+type Dependency struct {
+	Name  string `yaml:"name" json:"name"`
+	Alias string `yaml:"alias" json:"alias,omitempty"`
+}
+
+// Key returns the key the values of the dependency are set under.
+func (d Dependency) Key() string {
+	if d.Alias != "" {
+		return d.Alias
+	}
+
+	return d.Name
+}
+
+// LoadChart reads the Chart.yaml file in the given chart directory. If the
+// directory does not contain a Chart.yaml file, nil is returned.
+func LoadChart(chartDir string) (*Chart, error) {
+	chartBytes, err := os.ReadFile(filepath.Join(chartDir, "Chart.yaml"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var chart Chart
+	if err := yaml.Unmarshal(chartBytes, &chart); err != nil {
+		return nil, err
+	}
+
+	return &chart, nil
+}
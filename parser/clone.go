@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"slices"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+)
+
+// Clone returns a deep copy of the document, which shares no data with the
+// original document.
+func (d *Document) Clone() *Document {
+	if d == nil {
+		return nil
+	}
+
+	result := &Document{Title: d.Title, Intro: d.Intro.Clone(), VersionNote: d.VersionNote}
+	if d.Chart != nil {
+		chart := *d.Chart
+		chart.Dependencies = slices.Clone(chart.Dependencies)
+		result.Chart = &chart
+	}
+
+	if d.Sections != nil {
+		result.Sections = make([]Section, len(d.Sections))
+		for i, section := range d.Sections {
+			result.Sections[i] = section.Clone()
+		}
+	}
+
+	return result
+}
+
+// Clone returns a deep copy of the section.
+func (s Section) Clone() Section {
+	s.Description = s.Description.Clone()
+
+	if s.Properties != nil {
+		properties := make([]Property, len(s.Properties))
+		for i, property := range s.Properties {
+			properties[i] = property.Clone()
+		}
+		s.Properties = properties
+	}
+
+	return s
+}
+
+// Clone returns a deep copy of the property.
+func (p Property) Clone() Property {
+	if p.Path != nil {
+		p.Path = append(p.Path[:0:0], p.Path...)
+	}
+
+	if p.Owners != nil {
+		p.Owners = append([]string(nil), p.Owners...)
+	}
+
+	if p.LastCommit != nil {
+		commit := *p.LastCommit
+		p.LastCommit = &commit
+	}
+
+	if p.UserValue != nil {
+		userValue := *p.UserValue
+		p.UserValue = &userValue
+	}
+
+	p.Description = p.Description.Clone()
+	return p
+}
+
+// Clone returns a deep copy of the comment.
+func (c Comment) Clone() Comment {
+	if c.Segments != nil {
+		segments := make([]heuristics.CommentBlockSegment, len(c.Segments))
+		for i, segment := range c.Segments {
+			if segment.Contents != nil {
+				segment.Contents = append([]string(nil), segment.Contents...)
+			}
+			segments[i] = segment
+		}
+		c.Segments = segments
+	}
+
+	if c.Tags != nil {
+		tags := make(tags, len(c.Tags))
+		for key, values := range c.Tags {
+			tags[key] = append([]string(nil), values...)
+		}
+		c.Tags = tags
+	}
+
+	return c
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const cloneValues = `# +docs:section=Global
+# Global settings
+
+image:
+  # The image tag
+  # +docs:see=replicas
+  tag: v1.0.0
+# The number of replicas
+replicas: 1
+`
+
+func TestClone(t *testing.T) {
+	document, err := Parse(strings.NewReader(cloneValues), t.TempDir(), false)
+	require.NoError(t, err)
+	document.Chart = &Chart{Name: "example", Dependencies: []Dependency{{Name: "redis"}}}
+
+	clone := document.Clone()
+	require.Equal(t, document, clone)
+
+	// Modifying the clone does not modify the original
+	require.NoError(t, clone.ApplyOverrides(Overrides{"image.tag": {Description: "Changed"}}))
+	clone.Chart.Name = "changed"
+	clone.Chart.Dependencies[0].Alias = "cache"
+	clone.Sections[1].Properties[0].Path[0] = clone.Sections[1].Properties[1].Path[0]
+	clone.Sections[1].Properties[0].Description.Tags[TagSee][0] = "changed"
+	clone.Sections[1].Description.Segments[0].Contents[0] = "changed"
+
+	require.Equal(t, "example", document.Chart.Name)
+	require.Equal(t, []Dependency{{Name: "redis"}}, document.Chart.Dependencies)
+	require.Equal(t, "image.tag", document.Sections[1].Properties[0].Path.String())
+	require.Equal(t, []string{"replicas"}, document.Sections[1].Properties[0].SeeAlso())
+	require.Equal(t, "The image tag", document.Sections[1].Properties[0].Description.String())
+	require.Equal(t, "Global settings", document.Sections[1].Description.String())
+}
+
+func TestConcurrentReads(t *testing.T) {
+	document, err := Parse(strings.NewReader(cloneValues), t.TempDir(), false)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			filtered, err := document.ForAudience(AudiencePublic).ForSections([]string{"Global"})
+			require.NoError(t, err)
+			for _, property := range filtered.Sections[0].Properties {
+				_ = property.Path.String()
+				_ = property.Description.String()
+				_ = property.SeeAlso()
+			}
+
+			clone := document.Clone()
+			require.NoError(t, clone.ApplyOverrides(Overrides{"replicas": {Default: "2"}}))
+		}()
+	}
+	wg.Wait()
+}
@@ -16,7 +16,13 @@ limitations under the License.
 
 package parser
 
-import "github.com/cert-manager/helm-tool/heuristics"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+)
 
 type Comment struct {
 	heuristics.CommentBlock
@@ -38,3 +44,80 @@ func parseComments(comment string) (comments []Comment) {
 
 	return
 }
+
+// parseText parses free-form text, that is not part of a values.yaml file,
+// into a single Comment. Paragraphs are kept together in the same comment
+// instead of being split into separate blocks.
+func parseText(text string) Comment {
+	var c Comment
+	for _, block := range heuristics.ParseCommentIntoBlocks(text) {
+		for _, segment := range block.Segments {
+			l := len(c.Segments)
+			if l > 0 && segment.Type == heuristics.ContentTypeText && c.Segments[l-1].Type == heuristics.ContentTypeText {
+				c.Segments[l-1].Contents = append(append(c.Segments[l-1].Contents, ""), segment.Contents...)
+				continue
+			}
+
+			if segment.Type == heuristics.ContentTypeTag {
+				c.Tags.Push(segment.Contents[0])
+			}
+
+			c.Segments = append(c.Segments, segment)
+		}
+	}
+
+	return c
+}
+
+// resolveIncludes replaces every +docs:include tag in the comment with the
+// contents of the referenced file. Relative paths are resolved against
+// baseDir, which is the directory containing the values.yaml file. Included
+// files only provide text, the tags in them are ignored, as the tags of the
+// document have already been applied when the includes are resolved.
+func (c *Comment) resolveIncludes(baseDir string) error {
+	_, sectionFile := parseSectionTag(c.Tags.GetString(TagSection))
+	if len(c.Tags[TagInclude]) == 0 && sectionFile == "" {
+		return nil
+	}
+
+	var segments []heuristics.CommentBlockSegment
+	for _, segment := range c.Segments {
+		if segment.Type != heuristics.ContentTypeTag {
+			segments = append(segments, segment)
+			continue
+		}
+
+		key, includePath := parseTag(segment.Contents[0])
+		switch key {
+		case TagInclude:
+		case TagSection:
+			// The section description can be kept in a separate file, this is
+			// included after the tag so the tag itself is kept
+			segments = append(segments, segment)
+			if _, includePath = parseSectionTag(includePath); includePath == "" {
+				continue
+			}
+		default:
+			segments = append(segments, segment)
+			continue
+		}
+
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(baseDir, includePath)
+		}
+
+		contents, err := os.ReadFile(includePath)
+		if err != nil {
+			return fmt.Errorf("could not include %q: %w", includePath, err)
+		}
+
+		for _, included := range parseText(string(contents)).Segments {
+			if included.Type != heuristics.ContentTypeTag {
+				segments = append(segments, included)
+			}
+		}
+	}
+
+	c.Segments = segments
+	return nil
+}
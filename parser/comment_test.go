@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const includeValues = `# Number of replicas
+# +docs:include=docs/replicas.md
+replicas: 1
+`
+
+func TestIncludes(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "docs", "replicas.md"), []byte("Use at least 2 replicas\nin production.\n\n+docs:default=3\n"), 0o644))
+
+	// Relative paths are resolved against the directory of the values file,
+	// the tags in the included file are ignored
+	document, err := Parse(strings.NewReader(includeValues), baseDir, false)
+	require.NoError(t, err)
+
+	property := document.Sections[0].Properties[0]
+	require.Equal(t, "Number of replicas\nUse at least 2 replicas\nin production.", property.Description.String())
+	require.Empty(t, property.Description.Tags.GetString(TagDefault))
+	require.Equal(t, "1", property.Default)
+
+	_, err = Parse(strings.NewReader(includeValues), t.TempDir(), false)
+	require.ErrorContains(t, err, `property "replicas": could not include`)
+}
+
+const sectionFileValues = `# +docs:section=Webhook file=docs/webhook.md
+
+# Port of the webhook
+port: 10250
+`
+
+func TestSectionFile(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "docs", "webhook.md"), []byte("The webhook validates\nthe resources.\n"), 0o644))
+
+	document, err := Parse(strings.NewReader(sectionFileValues), baseDir, false)
+	require.NoError(t, err)
+
+	section := document.Sections[1]
+	require.Equal(t, "Webhook", section.Name)
+	require.Equal(t, "The webhook validates\nthe resources.", section.Description.String())
+	require.Equal(t, "port", section.Properties[0].Path.String())
+
+	_, err = Parse(strings.NewReader(sectionFileValues), t.TempDir(), false)
+	require.ErrorContains(t, err, `section "Webhook": could not include`)
+}
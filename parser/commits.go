@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"time"
+)
+
+// Commit is the last commit that changed a property in the values file.
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Date    time.Time `json:"date"`
+	Summary string    `json:"summary,omitempty"`
+	// PR is the number of the pull request the commit was merged in, if it
+	// is mentioned in the summary.
+	PR string `json:"pr,omitempty"`
+	// URL links to the commit or pull request.
+	URL string `json:"url,omitempty"`
+}
+
+// ShortHash returns the abbreviated hash of the commit.
+func (c Commit) ShortHash() string {
+	return c.Hash[:min(len(c.Hash), 7)]
+}
+
+// SetLastCommits sets the LastCommit of the properties in the document from
+// the commits of the lines of the values file. The lines of a property are
+// the comment directly above it, its key and its value, and the newest commit
+// of these lines is used. commitOfLine returns nil for lines that have not
+// been committed.
+func (d *Document) SetLastCommits(contents []byte, commitOfLine func(line int) *Commit) {
+	lines := strings.Split(string(contents), "\n")
+
+	for i := range d.Sections {
+		for j := range d.Sections[i].Properties {
+			property := &d.Sections[i].Properties[j]
+			if property.Line == 0 || property.Line > len(lines) {
+				continue
+			}
+
+			start, end := propertyLines(lines, property.Line)
+			for line := start; line <= end; line++ {
+				commit := commitOfLine(line)
+				if commit != nil && (property.LastCommit == nil || commit.Date.After(property.LastCommit.Date)) {
+					property.LastCommit = commit
+				}
+			}
+		}
+	}
+}
+
+// propertyLines returns the first and last line of the property with its key
+// at the line, including the comment directly above the key and the more
+// indented lines of the value. The items of a sequence can be at the same
+// indentation as its key.
+func propertyLines(lines []string, line int) (int, int) {
+	start := line
+	for start > 1 && isCommentLine(lines[start-2]) {
+		start--
+	}
+
+	keyIndent := len(leadingSpace(lines[line-1]))
+	isItem := strings.HasPrefix(strings.TrimSpace(lines[line-1]), "-")
+	end := line
+	for i := line; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+
+		indent := len(leadingSpace(lines[i]))
+		if indent < keyIndent || (indent == keyIndent && (isItem || !strings.HasPrefix(trimmed, "-"))) {
+			break
+		}
+
+		end = i + 1
+	}
+
+	return start, end
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLastCommits(t *testing.T) {
+	contents := `# +docs:section=Main
+
+# The replica count
+replicas: 1
+
+# Extra arguments
+args:
+- --v=2
+
+- --a
+# The image
+image:
+  # The tag
+  tag: v1
+`
+
+	document, err := ParseWithOptions(strings.NewReader(contents), t.TempDir(), Options{})
+	require.NoError(t, err)
+
+	old := &Commit{Hash: "aaaaaaaaaa", Date: time.Unix(1, 0)}
+	changed := &Commit{Hash: "bbbbbbbbbb", Date: time.Unix(2, 0)}
+	changedLines := map[int]bool{
+		3:  true, // comment of replicas
+		10: true, // second item of args
+		14: true, // value of image.tag
+	}
+
+	document.SetLastCommits([]byte(contents), func(line int) *Commit {
+		if line == 15 {
+			return nil
+		}
+		if changedLines[line] {
+			return changed
+		}
+		return old
+	})
+
+	lastCommits := map[string]string{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if property.LastCommit != nil {
+				lastCommits[property.Path.String()] = property.LastCommit.ShortHash()
+			}
+		}
+	}
+
+	require.Equal(t, map[string]string{
+		"replicas":  "bbbbbbb",
+		"args[0]":   "aaaaaaa",
+		"args[1]":   "bbbbbbb",
+		"image.tag": "bbbbbbb",
+	}, lastCommits)
+}
+
+func TestPropertyLines(t *testing.T) {
+	lines := strings.Split("a: 1\n# b\n# more\nb:\n  c: 1\n\n  d: 2\ne: 3", "\n")
+
+	start, end := propertyLines(lines, 1)
+	require.Equal(t, []int{1, 1}, []int{start, end})
+
+	start, end = propertyLines(lines, 4)
+	require.Equal(t, []int{2, 7}, []int{start, end})
+
+	start, end = propertyLines(lines, 5)
+	require.Equal(t, []int{5, 5}, []int{start, end})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// NamedDocument is the documentation of one chart of a set of charts that are
+// compared using FindInconsistencies.
+type NamedDocument struct {
+	Name     string
+	Document *Document
+}
+
+// Inconsistency is a field of a value that is documented differently by the
+// charts that have the value.
+type Inconsistency struct {
+	Path string `json:"path"`
+	// Field is the field that differs: "type", "default" or "description".
+	Field  string       `json:"field"`
+	Values []ChartValue `json:"values"`
+}
+
+// ChartValue is the value of a field of a property in one chart.
+type ChartValue struct {
+	Chart string `json:"chart"`
+	Value string `json:"value"`
+}
+
+// FindInconsistencies compares the values that have the same path in
+// multiple charts (eg. image.pullPolicy, resources or nodeSelector) and
+// returns the types, defaults and descriptions that differ between the
+// charts, sorted by path. If filter is not empty, only the values under the
+// filter paths are compared. Descriptions are compared ignoring whitespace.
+func FindInconsistencies(documents []NamedDocument, filter []paths.Path) []Inconsistency {
+	type chartProperty struct {
+		chart    string
+		property Property
+	}
+
+	byPath := map[string][]chartProperty{}
+	for _, document := range documents {
+		for _, section := range document.Document.Sections {
+			for _, property := range section.Properties {
+				if len(filter) > 0 && !isUnderAny(property.Path, filter) {
+					continue
+				}
+
+				path := property.Path.String()
+				byPath[path] = append(byPath[path], chartProperty{chart: document.Name, property: property})
+			}
+		}
+	}
+
+	fields := []struct {
+		name  string
+		value func(Property) string
+	}{
+		{"type", func(p Property) string { return p.Type.String() }},
+		{"default", func(p Property) string { return p.Default }},
+		{"description", func(p Property) string { return p.Description.String() }},
+	}
+
+	result := []Inconsistency{}
+	for path, charts := range byPath {
+		if len(charts) < 2 {
+			continue
+		}
+
+		for _, field := range fields {
+			values := make([]ChartValue, len(charts))
+			consistent := true
+			for i, chart := range charts {
+				values[i] = ChartValue{Chart: chart.chart, Value: field.value(chart.property)}
+				if normalizeSpace(values[i].Value) != normalizeSpace(values[0].Value) {
+					consistent = false
+				}
+			}
+
+			if !consistent {
+				result = append(result, Inconsistency{Path: path, Field: field.name, Values: values})
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+func normalizeSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
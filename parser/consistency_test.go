@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindInconsistencies(t *testing.T) {
+	parse := func(values string) *Document {
+		document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+		require.NoError(t, err)
+		return document
+	}
+
+	documents := []NamedDocument{
+		{Name: "a", Document: parse(`image:
+  # The pull policy
+  pullPolicy: IfNotPresent
+# Node selector
+nodeSelector: {}
+# Only in a
+replicas: 1
+`)},
+		{Name: "b", Document: parse(`image:
+  # The pull policy
+  pullPolicy: Always
+# Node
+# selector
+nodeSelector: {}
+`)},
+		{Name: "c", Document: parse(`image:
+  # The image pull policy
+  pullPolicy: IfNotPresent
+# Node selector
+# +docs:type=map
+nodeSelector: {}
+`)},
+	}
+
+	require.Equal(t, []Inconsistency{
+		{Path: "image.pullPolicy", Field: "default", Values: []ChartValue{
+			{Chart: "a", Value: "IfNotPresent"},
+			{Chart: "b", Value: "Always"},
+			{Chart: "c", Value: "IfNotPresent"},
+		}},
+		{Path: "image.pullPolicy", Field: "description", Values: []ChartValue{
+			{Chart: "a", Value: "The pull policy"},
+			{Chart: "b", Value: "The pull policy"},
+			{Chart: "c", Value: "The image pull policy"},
+		}},
+		{Path: "nodeSelector", Field: "type", Values: []ChartValue{
+			{Chart: "a", Value: "object"},
+			{Chart: "b", Value: "object"},
+			{Chart: "c", Value: "map"},
+		}},
+	}, FindInconsistencies(documents, nil))
+
+	filter := []paths.Path{paths.Path{}.WithProperty("nodeSelector")}
+	require.Len(t, FindInconsistencies(documents, filter), 1)
+	require.Empty(t, FindInconsistencies(documents[:2], filter))
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Removal is the list of deprecated properties that are removed in a single
+// version of the chart.
+type Removal struct {
+	// Version is empty for the deprecated properties that do not have a
+	// +docs:removed-in tag.
+	Version    string
+	Properties []Property
+}
+
+// ByRemovalVersion groups the deprecated properties of the document by the
+// version they are removed in, sorted by version. The properties without a
+// removal version are listed last.
+func (d *Document) ByRemovalVersion() []Removal {
+	byVersion := map[string][]Property{}
+	for _, section := range d.Sections {
+		for _, property := range section.Properties {
+			if property.Deprecated() {
+				byVersion[property.RemovedIn()] = append(byVersion[property.RemovedIn()], property)
+			}
+		}
+	}
+
+	result := make([]Removal, 0, len(byVersion))
+	for version, properties := range byVersion {
+		result = append(result, Removal{Version: version, Properties: properties})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Version == "") != (result[j].Version == "") {
+			return result[j].Version == ""
+		}
+
+		return compareVersions(result[i].Version, result[j].Version) < 0
+	})
+
+	return result
+}
+
+// compareVersions compares versions such as "v1.16" and "1.9.0" by their
+// numeric components, components that are not numbers are compared as text.
+func compareVersions(a, b string) int {
+	aParts := strings.FieldsFunc(strings.TrimPrefix(a, "v"), isVersionSeparator)
+	bParts := strings.FieldsFunc(strings.TrimPrefix(b, "v"), isVersionSeparator)
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, aErr := strconv.Atoi(aParts[i])
+		bNumber, bErr := strconv.Atoi(bParts[i])
+
+		switch {
+		case aErr == nil && bErr == nil && aNumber != bNumber:
+			return aNumber - bNumber
+		case (aErr != nil || bErr != nil) && aParts[i] != bParts[i]:
+			return strings.Compare(aParts[i], bParts[i])
+		}
+	}
+
+	return len(aParts) - len(bParts)
+}
+
+func isVersionSeparator(r rune) bool {
+	return r == '.' || r == '-' || r == '+'
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestByRemovalVersion(t *testing.T) {
+	values := `# +docs:deprecated=Use image.digest instead
+# +docs:removed-in=v1.10
+tag: v1
+# +docs:removed-in=v1.9
+old: 1
+# +docs:deprecated
+legacy: true
+replicas: 1
+# +docs:removed-in=v1.10
+other: 1
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	var versions []string
+	paths := map[string][]string{}
+	messages := map[string]string{}
+	for _, removal := range document.ByRemovalVersion() {
+		versions = append(versions, removal.Version)
+		for _, property := range removal.Properties {
+			paths[removal.Version] = append(paths[removal.Version], property.Path.String())
+			messages[property.Path.String()] = property.DeprecationMessage()
+		}
+	}
+
+	require.Equal(t, []string{"v1.9", "v1.10", ""}, versions)
+	require.Equal(t, map[string][]string{
+		"v1.9":  {"old"},
+		"v1.10": {"tag", "other"},
+		"":      {"legacy"},
+	}, paths)
+	require.Equal(t, map[string]string{
+		"tag":    "Use image.digest instead (to be removed in v1.10)",
+		"old":    "This value is deprecated and will be removed in v1.9.",
+		"legacy": "This value is deprecated.",
+		"other":  "This value is deprecated and will be removed in v1.10.",
+	}, messages)
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package parser
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/cert-manager/helm-tool/heuristics"
@@ -27,22 +31,71 @@ import (
 )
 
 const (
-	TagSection  = "docs:section"
-	TagIgnore   = "docs:ignore"
-	TagHidden   = "docs:hidden"
-	TagType     = "docs:type"
-	TagDefault  = "docs:default"
-	TagProperty = "docs:property"
+	TagSection     = "docs:section"
+	TagSectionEnd  = "docs:section-end"
+	TagIgnore      = "docs:ignore"
+	TagHidden      = "docs:hidden"
+	TagType        = "docs:type"
+	TagDefault     = "docs:default"
+	TagProperty    = "docs:property"
+	TagInclude     = "docs:include"
+	TagAudience    = "docs:audience"
+	TagSee         = "docs:see"
+	TagAlias       = "docs:alias"
+	TagName        = "docs:name"
+	TagWeight      = "docs:weight"
+	TagDeprecated  = "docs:deprecated"
+	TagOwner       = "docs:owner"
+	TagEnum        = "docs:enum"
+	TagRemovedIn   = "docs:removed-in"
+	TagAdvanced    = "docs:advanced"
+	TagDefaultFrom = "docs:default-from"
+	TagReadOnly    = "docs:read-only"
+	TagExample     = "docs:example"
+	TagTitle       = "docs:title"
+	TagIntro       = "docs:intro"
+	TagVersionNote = "docs:version-note"
 )
 
+// Document is the parsed documentation of a values file.
+//
+// Documents are safe for concurrent use as long as they are only read: the
+// methods that return a filtered document (ForAudience and ForSections) do not
+// modify the document. ApplyOverrides modifies the document in place and must
+// not be called while the document is used concurrently, and the filtered
+// documents share data with the document they were created from. Use Clone to
+// get an independent copy that can be modified.
 type Document struct {
+	// Chart contains the metadata of the chart the values file belongs to,
+	// it is nil if there is no Chart.yaml file next to the values file.
+	Chart    *Chart
 	Sections []Section
+
+	// Title is the title of the documentation, set using a +docs:title tag
+	// in a comment that is not attached to a value (eg. at the top of the
+	// values file). Templates fall back to the name of the chart.
+	Title string
+	// Intro is the comment with a +docs:intro tag, its text introduces the
+	// documentation.
+	Intro Comment
+	// VersionNote is a note on the versions the documentation applies to,
+	// set using a +docs:version-note tag.
+	VersionNote string
+
+	// sectionStack contains the indices of the sections that were started and
+	// not yet ended using +docs:section-end, new properties are added to the
+	// last one. It is only used while parsing.
+	sectionStack []int
 }
 
 type Section struct {
 	Name        string
 	Description Comment
 	Properties  []Property
+
+	// Appendix is set for the section of advanced properties added by
+	// WithAdvancedAppendix, templates render it collapsed.
+	Appendix bool
 }
 
 type Property struct {
@@ -50,6 +103,141 @@ type Property struct {
 	Description Comment
 	Type        Type
 	Default     string
+
+	// Line is the line of the property in the values file, it is 0 for
+	// properties that are defined using a +docs:property comment.
+	Line int
+	// Hidden is set for properties that are hidden using a +docs:hidden tag
+	// (on the property or one of its parents), these are only included when
+	// parsing with IncludeHidden.
+	Hidden bool
+	// Advanced is set for properties that are rarely changed, these are
+	// marked using a +docs:advanced tag on the property, one of its parents
+	// or its section.
+	Advanced bool
+	// Owners are the teams responsible for the property, these are set using
+	// +docs:owner tags on the property, one of its parents or its section,
+	// or using ApplyOwners.
+	Owners []string
+	// Anchor is the HTML anchor of the property, eg.
+	// "controller-replicacount", which is unique within the document:
+	// properties whose paths have the same anchor (eg. "a.b" and "a-b") are
+	// numbered in the order of the values file.
+	Anchor string
+	// SameAs is the path of the property defining the YAML anchor the value
+	// of the property is an alias of, eg. "tolerations" for
+	// "webhook.tolerations: *tolerations". It is only set if that property
+	// is documented.
+	SameAs string
+	// LastCommit is the last commit that changed the property, it is only set
+	// after calling SetLastCommits.
+	LastCommit *Commit
+	// UserValue is the value a user values file sets for the property, it is
+	// only set after calling SetUserValues.
+	UserValue *UserValue
+	// ID is the stable identifier of the property, it is only set after
+	// calling SetIDs.
+	ID string
+	// Source is where the value is used: SourceChart for the values of the
+	// chart itself, SourceGlobal for the global values shared with all
+	// subcharts, or the name (or alias) of the dependency in Chart.yaml the
+	// value is passed to.
+	Source string
+}
+
+// SeeAlso returns the paths of the properties referenced using +docs:see
+// tags.
+func (p Property) SeeAlso() []string {
+	return p.Description.Tags[TagSee]
+}
+
+// Name returns the display name set using a +docs:name tag, or an empty
+// string if the property has no display name.
+func (p Property) Name() string {
+	return p.Description.Tags.GetString(TagName)
+}
+
+// DisplayName returns the name the property is shown under in the
+// documentation, this is the display name if set and the path otherwise.
+func (p Property) DisplayName() string {
+	if name := p.Name(); name != "" {
+		return name
+	}
+
+	return p.Path.String()
+}
+
+// Deprecated returns whether the property has a +docs:deprecated or a
+// +docs:removed-in tag.
+func (p Property) Deprecated() bool {
+	_, ok := p.Description.Tags[TagDeprecated]
+	return ok || p.RemovedIn() != ""
+}
+
+// DeprecationMessage returns the message of the +docs:deprecated tag, or a
+// generic message if the tag has no value. The version the property is
+// removed in is added to the message.
+func (p Property) DeprecationMessage() string {
+	message := p.Description.Tags.GetString(TagDeprecated)
+	removedIn := p.RemovedIn()
+
+	switch {
+	case message == "" && removedIn == "":
+		return "This value is deprecated."
+	case message == "":
+		return fmt.Sprintf("This value is deprecated and will be removed in %s.", removedIn)
+	case removedIn == "":
+		return message
+	default:
+		return fmt.Sprintf("%s (to be removed in %s)", message, removedIn)
+	}
+}
+
+// RemovedIn returns the version of the chart in which the deprecated property
+// is removed, this is set using a +docs:removed-in tag.
+func (p Property) RemovedIn() string {
+	return p.Description.Tags.GetString(TagRemovedIn)
+}
+
+// DefaultFrom returns where the effective default of the property comes from
+// when it is computed in the templates of the chart, eg. ".Chart.AppVersion"
+// or "generated at install time", this is set using a +docs:default-from tag.
+func (p Property) DefaultFrom() string {
+	return p.Description.Tags.GetString(TagDefaultFrom)
+}
+
+// ReadOnly returns whether the property must not be changed by users, eg.
+// because it is managed by the chart, this is set using a +docs:read-only
+// tag.
+func (p Property) ReadOnly() bool {
+	return p.Description.Tags.GetBool(TagReadOnly)
+}
+
+// Examples returns the YAML examples of the values of the property, these are
+// set using +docs:example tags (one per example).
+func (p Property) Examples() []string {
+	return p.Description.Tags[TagExample]
+}
+
+// Enum returns the allowed values of the property, these are set using a
+// +docs:enum tag with a comma-separated list of values.
+func (p Property) Enum() []string {
+	var result []string
+	for _, value := range p.Description.Tags[TagEnum] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+
+	return result
+}
+
+// Aliases returns the previous paths of the property, these are added using
+// +docs:alias tags when a value is renamed.
+func (p Property) Aliases() []string {
+	return p.Description.Tags[TagAlias]
 }
 
 type Type string
@@ -89,26 +277,113 @@ type Node struct {
 	HeadComments []Comment
 	FootComment  []Comment
 	RawNode      *yaml.Node
+
+	// Line is the line of the key of the node, or of the node itself if it
+	// has no key.
+	Line int
 }
 
 func Load(filename string, includeHidden bool) (*Document, error) {
+	return LoadWithOptions(filename, Options{IncludeHidden: includeHidden})
+}
+
+func LoadWithOptions(filename string, options Options) (*Document, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
+
+	return ParseWithOptions(file, filepath.Dir(filename), options)
+}
+
+// Parse reads a values file from r. Included files and the Chart.yaml file
+// are resolved relative to baseDir, which is the directory containing the
+// values file.
+func Parse(r io.Reader, baseDir string, includeHidden bool) (*Document, error) {
+	return ParseWithOptions(r, baseDir, Options{IncludeHidden: includeHidden})
+}
+
+func ParseWithOptions(r io.Reader, baseDir string, options Options) (*Document, error) {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := options.Timings.Start("parse")
+	contents = options.normalizeTags(contents)
 
 	var root yaml.Node
-	if err := yaml.NewDecoder(file).Decode(&root); err != nil {
+	err = yaml.NewDecoder(bytes.NewReader(contents)).Decode(&root)
+	stop()
+	if err != nil {
+		return nil, err
+	}
+
+	defer options.Timings.Start("comments")()
+
+	unknownTags := FindUnknownTags(contents)
+	if options.StrictTags && len(unknownTags) > 0 {
+		return nil, fmt.Errorf("found %d unknown tags, the first is on %s", len(unknownTags), unknownTags[0])
+	}
+
+	for _, tag := range unknownTags {
+		log.Println(tag)
+	}
+
+	var document *Document
+	switch options.Dialect {
+	case DialectDefault:
+		document, err = parseDocument(&root, options)
+	case DialectBitnami:
+		document, err = parseBitnami(contents, &root)
+	case DialectHelmDocs:
+		document, err = parseHelmDocs(contents, &root)
+	default:
+		return nil, fmt.Errorf("unknown dialect %q", options.Dialect)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := document.resolveIncludes(baseDir); err != nil {
 		return nil, err
 	}
 
+	if err := document.sortProperties(); err != nil {
+		return nil, err
+	}
+
+	if err := document.resolveUnknownTypes(options); err != nil {
+		return nil, err
+	}
+
+	document.setAnchors()
+
+	document.Chart, err = LoadChart(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not load chart metadata: %w", err)
+	}
+
+	document.setSources()
+
+	return document, nil
+}
+
+// parseDocument parses the documentation from the +docs: comments in the
+// values file.
+func parseDocument(root *yaml.Node, options Options) (*Document, error) {
 	document := Document{Sections: make([]Section, 1)}
+	var hidden, advanced []paths.Path
+	owners := map[string][]string{}
+	anchors := newYAMLAnchors()
 	node := Node{
-		RawNode:      &root,
+		RawNode:      root,
 		HeadComments: parseComments(root.HeadComment),
 		FootComment:  parseComments(root.FootComment),
 	}
-	err = walk(node, func(node Node) (bool, error) {
+	err := walk(node, func(node Node) (bool, error) {
+		anchors.visit(node)
 		comment := pop(&node.HeadComments)
 
 		parseCommentsOntoDocument(node.Path.Parent(), &document, node.HeadComments)
@@ -120,38 +395,168 @@ func Load(filename string, includeHidden bool) (*Document, error) {
 		}
 
 		// If we have a comment instructing us to hide this node, obey it if we are not including hidden nodes
-		if comment.Tags.GetBool(TagHidden) && !includeHidden {
-			return true, nil
+		if comment.Tags.GetBool(TagHidden) {
+			if !options.IncludeHidden {
+				return true, nil
+			}
+
+			hidden = append(hidden, node.Path)
+		}
+
+		if comment.Tags.GetBool(TagAdvanced) {
+			advanced = append(advanced, node.Path)
 		}
 
 		// An end node is a node we find a property at, this is usually a scalar
 		// node, but can be a map or sequence if the user uses the
 		// +docs:property tag (or if they have no values).
 		if !isEndNode(node, comment) {
+			if tagOwners := comment.Tags[TagOwner]; len(tagOwners) > 0 {
+				owners[node.Path.String()] = tagOwners
+			}
+
 			parseCommentsOntoDocument(node.Path.Parent(), &document, []Comment{comment})
 			return false, nil
 		}
 
-		sectionIdx := len(document.Sections) - 1
+		// A +docs:section-end tag above the property ends the section before it
+		if comment.Tags.GetBool(TagSectionEnd) {
+			document.endSection()
+		}
+
+		sectionIdx := document.currentSection()
 		document.Sections[sectionIdx].Properties = append(document.Sections[sectionIdx].Properties, Property{
 			Path:        node.Path,
 			Description: comment,
 			Type:        getTypeOf(node, comment),
 			Default:     getDefaultValue(node, comment),
+			Line:        node.Line,
+			Hidden:      isUnderAny(node.Path, hidden),
+			Advanced:    isUnderAny(node.Path, advanced),
+			Owners:      ownersOf(node.Path, comment, owners),
+			SameAs:      anchors.sameAs(node.Path),
 		})
 
 		return true, nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	document.sectionStack = nil
+
+	// Properties without owners inherit the owners of their section, all
+	// properties of an advanced section are advanced
+	for i := range document.Sections {
+		sectionOwners := document.Sections[i].Description.Tags[TagOwner]
+		sectionAdvanced := document.Sections[i].Description.Tags.GetBool(TagAdvanced)
+		for j := range document.Sections[i].Properties {
+			property := &document.Sections[i].Properties[j]
+			if len(property.Owners) == 0 {
+				property.Owners = sectionOwners
+			}
+			if sectionAdvanced {
+				property.Advanced = true
+			}
+		}
+	}
+
+	document.removeUndocumentedSameAs()
+
+	return &document, nil
+}
+
+// ownersOf returns the owners of the +docs:owner tags of the property, or of
+// the closest parent with +docs:owner tags.
+func ownersOf(path paths.Path, comment Comment, parentOwners map[string][]string) []string {
+	if tagOwners := comment.Tags[TagOwner]; len(tagOwners) > 0 {
+		return tagOwners
+	}
+
+	for parent := path.Parent(); len(parent) > 0; parent = parent.Parent() {
+		if tagOwners, ok := parentOwners[parent.String()]; ok {
+			return tagOwners
+		}
+	}
 
-	return &document, err
+	return nil
+}
+
+// setMetadata sets the title, intro and version note of the document from
+// the tags of the comment.
+func (d *Document) setMetadata(comment Comment) {
+	if title := comment.Tags.GetString(TagTitle); title != "" {
+		d.Title = title
+	}
+
+	if comment.Tags.GetBool(TagIntro) {
+		d.Intro = comment
+	}
+
+	if note := comment.Tags.GetString(TagVersionNote); note != "" {
+		d.VersionNote = note
+	}
+}
+
+// parseSectionTag splits the value of a +docs:section tag into the name of
+// the section and the optional file containing its description, eg.
+// "Webhook file=docs/webhook.md".
+func parseSectionTag(value string) (string, string) {
+	if idx := strings.LastIndex(value, "file="); idx != -1 && (idx == 0 || value[idx-1] == ' ') {
+		return strings.TrimSpace(value[:idx]), strings.TrimSpace(value[idx+len("file="):])
+	}
+
+	return value, ""
+}
+
+func sectionName(value string) string {
+	name, _ := parseSectionTag(value)
+	return name
+}
+
+// currentSection returns the index of the section new properties are added
+// to, this is the last section that was not ended using +docs:section-end or
+// the first (unnamed) section.
+func (d *Document) currentSection() int {
+	if len(d.sectionStack) == 0 {
+		return 0
+	}
+
+	return d.sectionStack[len(d.sectionStack)-1]
+}
+
+// startSection adds the section to the document, the following properties are
+// added to it.
+func (d *Document) startSection(section Section) {
+	d.Sections = append(d.Sections, section)
+	d.sectionStack = append(d.sectionStack, len(d.Sections)-1)
+}
+
+// endSection ends the current section, the following properties are added
+// to the section that was current before it.
+func (d *Document) endSection() {
+	if len(d.sectionStack) == 0 {
+		log.Println("+docs:section-end tag outside of a section")
+		return
+	}
+
+	d.sectionStack = d.sectionStack[:len(d.sectionStack)-1]
 }
 
 func parseCommentsOntoDocument(path paths.Path, document *Document, comments []Comment) {
 	for _, comment := range comments {
+		// The section is ended first, so a comment can end a section and
+		// start the next one
+		if comment.Tags.GetBool(TagSectionEnd) {
+			document.endSection()
+		}
+
+		document.setMetadata(comment)
+
 		switch {
 		case comment.Tags.GetBool(TagSection):
-			document.Sections = append(document.Sections, Section{
-				Name:        comment.Tags.GetString(TagSection),
+			document.startSection(Section{
+				Name:        sectionName(comment.Tags.GetString(TagSection)),
 				Description: comment,
 			})
 		case comment.Tags.GetBool(TagProperty):
@@ -221,7 +626,7 @@ func parseCommentsOntoDocument(path paths.Path, document *Document, comments []C
 				continue
 			}
 
-			sectionIdx := len(document.Sections) - 1
+			sectionIdx := document.currentSection()
 			document.Sections[sectionIdx].Properties = append(document.Sections[sectionIdx].Properties, Property{
 				Path:        path,
 				Description: comment,
@@ -254,6 +659,7 @@ func walk(root Node, fn func(node Node) (bool, error)) error {
 				HeadComments: parseComments(root.RawNode.HeadComment),
 				FootComment:  parseComments(root.RawNode.FootComment),
 				RawNode:      node,
+				Line:         node.Line,
 			}
 
 			if err := walk(n, fn); err != nil {
@@ -270,6 +676,7 @@ func walk(root Node, fn func(node Node) (bool, error)) error {
 				HeadComments: parseComments(keyNode.HeadComment),
 				FootComment:  parseComments(keyNode.FootComment),
 				RawNode:      valueNode,
+				Line:         keyNode.Line,
 			}
 
 			if err := walk(n, fn); err != nil {
@@ -295,6 +702,7 @@ func walk(root Node, fn func(node Node) (bool, error)) error {
 			HeadComments: parseComments(root.RawNode.HeadComment),
 			FootComment:  parseComments(root.RawNode.FootComment),
 			RawNode:      root.RawNode.Alias,
+			Line:         root.Line,
 		}
 
 		if err := walk(n, fn); err != nil {
@@ -367,11 +775,24 @@ func getTypeOf(node Node, comment Comment) Type {
 		return Type(typ)
 	}
 
-	if node.RawNode == nil {
-		return TypeUnknown
+	if node.RawNode != nil {
+		return typeOfNode(node.RawNode)
 	}
 
-	switch node.RawNode.ShortTag() {
+	// Properties defined using +docs:property may not have a node, in which
+	// case the type is inferred from the default
+	if def := comment.Tags.GetString(TagDefault); def != "" {
+		var defaultNode yaml.Node
+		if err := yaml.Unmarshal([]byte(def), &defaultNode); err == nil && len(defaultNode.Content) != 0 {
+			return typeOfNode(defaultNode.Content[0])
+		}
+	}
+
+	return TypeUnknown
+}
+
+func typeOfNode(node *yaml.Node) Type {
+	switch node.ShortTag() {
 	case "!!bool":
 		return TypeBool
 	case "!!str":
@@ -390,3 +811,23 @@ func getTypeOf(node Node, comment Comment) Type {
 		return TypeUnknown
 	}
 }
+
+// resolveIncludes inlines the files referenced by +docs:include tags into the
+// section and property descriptions.
+func (d *Document) resolveIncludes(baseDir string) error {
+	for i := range d.Sections {
+		section := &d.Sections[i]
+		if err := section.Description.resolveIncludes(baseDir); err != nil {
+			return fmt.Errorf("section %q: %w", section.Name, err)
+		}
+
+		for j := range section.Properties {
+			property := &section.Properties[j]
+			if err := property.Description.resolveIncludes(baseDir); err != nil {
+				return fmt.Errorf("property %q: %w", property.Path, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndefinedPropertyTypes(t *testing.T) {
+	values := `# +docs:section=Example
+
+# A property with an example
+# +docs:property
+# replicas: 1
+
+# +docs:property=image.pullPolicy
+# +docs:default=IfNotPresent
+
+# +docs:property=nodeSelector
+# +docs:default={}
+
+# +docs:property
+# extraArgs: ["--v=2"]
+
+# +docs:property=tolerations
+# +docs:type=array
+
+# +docs:property=unknown
+
+other: true
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	types := map[string]Type{}
+	for _, property := range document.Sections[1].Properties {
+		types[property.Path.String()] = property.Type
+	}
+
+	require.Equal(t, map[string]Type{
+		"replicas":         TypeNumber,
+		"image.pullPolicy": TypeString,
+		"nodeSelector":     TypeObject,
+		"extraArgs":        TypeArray,
+		"tolerations":      TypeArray,
+		"unknown":          TypeUnknown,
+		"other":            TypeBool,
+	}, types)
+}
+
+func TestWithoutHidden(t *testing.T) {
+	values := `# Shown
+shown: true
+
+# +docs:hidden
+internal:
+  # Hidden with its parent
+  flag: true
+
+# Hidden itself
+# +docs:hidden
+secret: ""
+`
+
+	paths := func(document *Document) []string {
+		var result []string
+		for _, section := range document.Sections {
+			for _, property := range section.Properties {
+				result = append(result, property.Path.String())
+			}
+		}
+		return result
+	}
+
+	withHidden, err := Parse(strings.NewReader(values), t.TempDir(), true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"shown", "internal.flag", "secret"}, paths(withHidden))
+
+	withoutHidden, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+	require.Equal(t, paths(withoutHidden), paths(withHidden.WithoutHidden()))
+}
+
+func TestSectionEnd(t *testing.T) {
+	values := `# General property
+general: true
+
+# +docs:section=Global
+
+# Foo parameter description
+foo: bar
+
+# +docs:section=Proxy
+
+# Proxy URL
+proxy: ""
+
+# +docs:section-end
+
+# Back in the Global section
+baz: qux
+
+# +docs:section-end
+# Back in the unnamed section
+# +docs:property
+# other: 1
+
+# +docs:section=Webhook
+
+webhook:
+  # Webhook port
+  port: 10250
+
+  # +docs:section-end
+  # +docs:section=Webhook TLS
+
+  # Webhook certificate
+  cert: ""
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	sections := map[string][]string{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			sections[section.Name] = append(sections[section.Name], property.Path.String())
+		}
+	}
+
+	require.Equal(t, map[string][]string{
+		"":            {"general", "other"},
+		"Global":      {"foo", "baz"},
+		"Proxy":       {"proxy"},
+		"Webhook":     {"webhook.port"},
+		"Webhook TLS": {"webhook.cert"},
+	}, sections)
+	require.Len(t, document.Sections, 5)
+	require.Nil(t, document.sectionStack)
+}
+
+func TestAnchors(t *testing.T) {
+	values := `controller:
+  replicaCount: 1
+controller-replicaCount: 2
+a-b-2: 3
+a:
+  b: 4
+a-b: 5
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	anchors := map[string]string{}
+	for _, property := range document.Sections[0].Properties {
+		anchors[property.Path.String()] = property.Anchor
+	}
+
+	require.Equal(t, map[string]string{
+		"controller.replicaCount": "controller-replicacount",
+		"controller-replicaCount": "controller-replicacount-2",
+		"a-b-2":                   "a-b-2",
+		"a.b":                     "a-b",
+		"a-b":                     "a-b-3",
+	}, anchors)
+}
+
+func TestSameAs(t *testing.T) {
+	values := `# +docs:property
+tolerations: &tolerations
+  - key: example
+image: &image quay.io/example
+webhook:
+  # +docs:property
+  tolerations: *tolerations
+  image: *image
+# +docs:hidden
+hidden: &hidden 1
+other: *hidden
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	sameAs := map[string]string{}
+	for _, property := range document.Sections[0].Properties {
+		sameAs[property.Path.String()] = property.SameAs
+	}
+
+	require.Equal(t, map[string]string{
+		"tolerations":         "",
+		"image":               "",
+		"webhook.tolerations": "tolerations",
+		"webhook.image":       "image",
+		"other":               "",
+	}, sameAs)
+}
+
+func TestDocumentMetadata(t *testing.T) {
+	values := `# +docs:title=cert-manager configuration
+# +docs:version-note=These values apply to v1.14 and later.
+
+# +docs:intro
+# This page documents the values of the chart.
+
+# The number of replicas
+replicas: 1
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+	require.Equal(t, "cert-manager configuration", document.Title)
+	require.Equal(t, "These values apply to v1.14 and later.", document.VersionNote)
+	require.Equal(t, "This page documents the values of the chart.", document.Intro.String())
+	require.Len(t, document.Sections[0].Properties, 1)
+
+	clone := document.Clone()
+	require.Equal(t, document.Title, clone.Title)
+	require.Equal(t, document.Intro.String(), clone.Intro.String())
+
+	uses, err := FindTagUses([]byte("# +docs:title=Values\nreplicas: 1\n"), t.TempDir(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, []TagUse{
+		{Line: 1, Tag: TagTitle, Value: "Values", NoEffect: "document tags directly above a value are not used, add an empty line after the comment"},
+	}, uses)
+}
+
+func TestAnchorsFiltered(t *testing.T) {
+	values := `# +docs:hidden
+controller:
+  replicaCount: 1
+a:
+  # +docs:audience=internal
+  b: 2
+controller-replicaCount: 3
+a-b: 4
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), true)
+	require.NoError(t, err)
+
+	anchors := func(document *Document) []string {
+		var result []string
+		for _, property := range document.Sections[0].Properties {
+			result = append(result, property.Anchor)
+		}
+		return result
+	}
+
+	// The anchors do not depend on the excluded properties
+	require.Equal(t, []string{"controller-replicacount", "a-b", "controller-replicacount-2", "a-b-2"}, anchors(document))
+	require.Equal(t, []string{"a-b", "controller-replicacount", "a-b-2"}, anchors(document.WithoutHidden()))
+	require.Equal(t, []string{"controller-replicacount", "a-b"}, anchors(document.WithoutHidden().ForAudience(AudiencePublic)))
+}
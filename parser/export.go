@@ -0,0 +1,365 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// bitnamiTypes are the types that can be set using a modifier in a Bitnami
+// @param annotation.
+var bitnamiTypes = []Type{TypeArray, TypeObject, TypeString}
+
+// Export rewrites the +docs: comments in a values file into the annotations
+// of another dialect (see DialectBitnami and DialectHelmDocs), the reverse of
+// Migrate. Tags that have no equivalent in the dialect are dropped, values
+// and comments that are not documentation are kept as they are.
+func Export(contents []byte, dialect string) ([]byte, error) {
+	if dialect != DialectBitnami && dialect != DialectHelmDocs {
+		return nil, fmt.Errorf("cannot export to dialect %q", dialect)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(contents, &root); err != nil {
+		return nil, err
+	}
+
+	document, err := parseDocument(&root, Options{IncludeHidden: true})
+	if err != nil {
+		return nil, err
+	}
+
+	e := exporter{
+		migration:  newMigration(string(contents), &root),
+		dialect:    dialect,
+		properties: map[int]exportedProperty{},
+	}
+
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if property.Line > 0 {
+				e.properties[property.Line-1] = exportedProperty{Property: property, section: section.Name}
+			}
+		}
+	}
+
+	e.export(document.Sections[1:])
+
+	exported := e.bytes()
+
+	var original, updated any
+	if err := yaml.Unmarshal(contents, &original); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(exported, &updated); err != nil {
+		return nil, fmt.Errorf("exporting produced invalid yaml: %w", err)
+	}
+	if !reflect.DeepEqual(original, updated) {
+		return nil, fmt.Errorf("exporting changed the values in the document")
+	}
+
+	return exported, nil
+}
+
+type exportedProperty struct {
+	Property
+	section string
+}
+
+type exporter struct {
+	*migration
+	dialect string
+	// properties are the properties with a value, by the line of their key.
+	properties map[int]exportedProperty
+}
+
+func (e *exporter) export(sections []Section) {
+	documented := map[int]bool{}
+	section := ""
+
+	for start := 0; start < len(e.lines); start++ {
+		if !isCommentLine(e.lines[start]) {
+			continue
+		}
+
+		end := start
+		for end < len(e.lines) && isCommentLine(e.lines[end]) {
+			end++
+		}
+
+		block := e.lines[start:end]
+		indent := leadingSpace(block[0])
+		keyIdx, isKey := e.keyLines[end]
+
+		switch {
+		case blockHasTag(block, TagSection) && len(sections) > 0:
+			e.replaceBlock(start, end, e.section(indent, block, sections[0]))
+			section = sections[0].Name
+			sections = sections[1:]
+
+		case isKey && blockHasTag(block, TagIgnore):
+			e.replaceBlock(start, end, e.ignored(indent, block, e.keys[keyIdx].path.String()))
+
+		case isKey && e.hasProperty(end):
+			e.replaceBlock(start, end, e.property(indent, block, e.properties[end]))
+			documented[end] = true
+
+		case !isKey && blockHasTag(block, TagProperty):
+			property, ok := e.standaloneProperty(start, end)
+			if !ok {
+				break
+			}
+
+			if e.dialect == DialectHelmDocs {
+				log.Printf("property %q has no value, it cannot be documented using helm-docs\n", property.Path)
+				break
+			}
+
+			property.section = section
+			e.replaceBlock(start, end, e.property(indent, nil, property))
+		}
+
+		start = end
+	}
+
+	// Bitnami's readme-generator-for-helm only documents values that have a
+	// @param annotation
+	if e.dialect == DialectBitnami {
+		for line, property := range e.properties {
+			if !documented[line] {
+				e.inserted[line] = e.property(leadingSpace(e.lines[line]), nil, property)
+			}
+		}
+	}
+}
+
+// standaloneProperty parses the property defined by a +docs:property comment
+// block that is not followed by a value. The property is nested in the same
+// mapping as the keys with the same indentation around the block.
+func (e *exporter) standaloneProperty(start, end int) (exportedProperty, bool) {
+	indent := leadingSpace(e.lines[start])
+
+	var parent paths.Path
+	for _, key := range e.keys {
+		if leadingSpace(e.lines[key.line]) == indent {
+			parent = key.path.Parent()
+			if key.line > end {
+				break
+			}
+		}
+	}
+
+	var comment []string
+	for _, line := range e.lines[start:end] {
+		comment = append(comment, strings.TrimSpace(line))
+	}
+
+	var document Document
+	document.Sections = make([]Section, 1)
+	parseCommentsOntoDocument(parent, &document, parseComments(strings.Join(comment, "\n")))
+	if len(document.Sections[0].Properties) == 0 {
+		return exportedProperty{}, false
+	}
+
+	return exportedProperty{Property: document.Sections[0].Properties[0]}, true
+}
+
+func (e *exporter) hasProperty(line int) bool {
+	_, ok := e.properties[line]
+	return ok
+}
+
+// replaceBlock replaces the comment lines from start to end, if there are no
+// replacement lines the blank line after the block is removed too.
+func (e *exporter) replaceBlock(start, end int, replacement []string) {
+	for i := start; i < end; i++ {
+		e.deleted[i] = true
+	}
+
+	if len(replacement) > 0 {
+		e.replaced[start] = replacement
+		return
+	}
+
+	if end < len(e.lines) && strings.TrimSpace(e.lines[end]) == "" && (start == 0 || strings.TrimSpace(e.lines[start-1]) == "") {
+		e.deleted[end] = true
+	}
+}
+
+// section returns the annotations of a +docs:section comment block. Sections
+// in helm-docs are set on each property, so only the description is kept.
+func (e *exporter) section(indent string, block []string, section Section) []string {
+	description := blockDescription(block)
+
+	if e.dialect == DialectHelmDocs {
+		var lines []string
+		for _, line := range description {
+			lines = append(lines, indent+commentLine(line))
+		}
+		return lines
+	}
+
+	lines := []string{indent + "## @section " + section.Name}
+	if len(description) > 0 {
+		lines = append(lines, indent+"## @descriptionStart")
+		for _, line := range description {
+			lines = append(lines, strings.TrimRight(indent+"## "+line, " "))
+		}
+		lines = append(lines, indent+"## @descriptionEnd")
+	}
+
+	return lines
+}
+
+// ignored returns the annotations of a comment block with a +docs:ignore tag.
+func (e *exporter) ignored(indent string, block []string, path string) []string {
+	var lines []string
+	for _, line := range block {
+		if !isTagLine(line, TagIgnore) {
+			lines = append(lines, line)
+		}
+	}
+
+	if e.dialect == DialectHelmDocs {
+		return append(lines, indent+"# @ignored")
+	}
+
+	return append(lines, indent+"## @skip "+path)
+}
+
+// property returns the annotations documenting a property, block is the
+// comment block above its value (if any).
+func (e *exporter) property(indent string, block []string, property exportedProperty) []string {
+	typ := property.Description.Tags.GetString(TagType)
+	def := property.Description.Tags.GetString(TagDefault)
+	hidden := property.Description.Tags.GetBool(TagHidden)
+
+	if e.dialect == DialectHelmDocs {
+		description := blockDescription(block)
+		if len(description) == 0 {
+			description = []string{""}
+		}
+		if typ != "" {
+			description[0] = strings.TrimSpace("(" + typ + ") " + description[0])
+		}
+
+		lines := []string{strings.TrimRight(indent+"# -- "+description[0], " ")}
+		for _, line := range description[1:] {
+			lines = append(lines, indent+commentLine(line))
+		}
+
+		if def != "" {
+			lines = append(lines, indent+"# @default -- "+def)
+		}
+		if property.section != "" {
+			lines = append(lines, indent+"# @section -- "+property.section)
+		}
+		if hidden {
+			lines = append(lines, indent+"# @ignored")
+		}
+
+		return lines
+	}
+
+	var modifiers []string
+	for _, t := range bitnamiTypes {
+		if Type(typ) == t {
+			modifiers = append(modifiers, typ)
+		}
+	}
+	if def != "" {
+		modifiers = append(modifiers, "default: "+def)
+	}
+
+	annotation := "@param"
+	if property.Line == 0 {
+		annotation = "@extra"
+	}
+
+	line := indent + "## " + annotation + " " + property.Path.String()
+	if len(modifiers) > 0 {
+		line += " [" + strings.Join(modifiers, ", ") + "]"
+	}
+
+	// Bitnami descriptions are a single line of text
+	var text []string
+	for _, segment := range property.Description.Segments {
+		if segment.Type == heuristics.ContentTypeText {
+			text = append(text, strings.Join(strings.Fields(segment.String()), " "))
+		}
+	}
+	if len(text) > 0 {
+		line += " " + strings.Join(text, " ")
+	}
+
+	lines := []string{line}
+	if hidden {
+		lines = append(lines, indent+"## @skip "+property.Path.String())
+	}
+
+	return lines
+}
+
+// blockDescription returns the content of the comment lines that are not
+// tags, with the relative indentation of the lines preserved.
+func blockDescription(block []string) []string {
+	var description []string
+	for _, line := range block {
+		content := strings.TrimPrefix(strings.TrimLeft(strings.TrimSpace(line), "#"), " ")
+		if isTagContent(content) {
+			continue
+		}
+
+		description = append(description, strings.TrimRight(content, " \t"))
+	}
+
+	// Remove the empty lines around the description
+	for len(description) > 0 && description[0] == "" {
+		description = description[1:]
+	}
+	for len(description) > 0 && description[len(description)-1] == "" {
+		description = description[:len(description)-1]
+	}
+
+	return description
+}
+
+func blockHasTag(block []string, tag string) bool {
+	for _, line := range block {
+		if isTagLine(line, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTagLine(line, tag string) bool {
+	content := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+	return content == "+"+tag || strings.HasPrefix(content, "+"+tag+"=")
+}
+
+func isTagContent(content string) bool {
+	return strings.HasPrefix(strings.TrimSpace(content), "+docs:")
+}
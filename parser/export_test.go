@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const docsValues = `# +docs:section=Image
+# The image of the controller.
+
+image:
+  # The image repository
+  repository: quay.io/example
+  # The image tag
+  # +docs:default=The chart appVersion
+  tag: ""
+
+# +docs:ignore
+internal: {}
+
+# Extra arguments
+# +docs:type=array
+extraArgs: null
+`
+
+func TestExportHelmDocs(t *testing.T) {
+	exported, err := Export([]byte(docsValues), DialectHelmDocs)
+	require.NoError(t, err)
+
+	require.Equal(t, `# The image of the controller.
+
+image:
+  # -- The image repository
+  # @section -- Image
+  repository: quay.io/example
+  # -- The image tag
+  # @default -- The chart appVersion
+  # @section -- Image
+  tag: ""
+
+# @ignored
+internal: {}
+
+# -- (array) Extra arguments
+# @section -- Image
+extraArgs: null
+`, string(exported))
+}
+
+func TestExportBitnami(t *testing.T) {
+	exported, err := Export([]byte(docsValues), DialectBitnami)
+	require.NoError(t, err)
+
+	require.Equal(t, `## @section Image
+## @descriptionStart
+## The image of the controller.
+## @descriptionEnd
+
+image:
+  ## @param image.repository The image repository
+  repository: quay.io/example
+  ## @param image.tag [default: The chart appVersion] The image tag
+  tag: ""
+
+## @skip internal
+internal: {}
+
+## @param extraArgs [array] Extra arguments
+extraArgs: null
+`, string(exported))
+}
+
+func TestExportUnknownDialect(t *testing.T) {
+	_, err := Export([]byte(docsValues), "unknown")
+	require.Error(t, err)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// helmDocsPathCommentExp matches the old style helm-docs comments that refer to
+// a value by its path, eg. "# image.tag -- The image tag".
+var helmDocsPathCommentExp = regexp.MustCompile(`^\s*#\s*([A-Za-z0-9_.\[\]/-]+)\s+--\s*(.*)$`)
+
+// helmDocsTypeExp matches the type hint at the start of a helm-docs
+// description, eg. "(int) The number of replicas".
+var helmDocsTypeExp = regexp.MustCompile(`^\((\w+)\)\s*`)
+
+// helmDocsComment is the documentation of a value in a helm-docs comment.
+type helmDocsComment struct {
+	documented  bool
+	description []string
+	typ         string
+	def         string
+	section     string
+	ignored     bool
+}
+
+// parseHelmDocsComment parses the comment above a value using the helm-docs
+// conventions: the description starts at a "# --" line and continues until
+// the value, "# @default --", "# @section --" and "# @ignored" lines are
+// annotations.
+func parseHelmDocsComment(comment string) helmDocsComment {
+	var result helmDocsComment
+	inDescription := false
+
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+
+		switch {
+		case line == "--" || strings.HasPrefix(line, "-- "):
+			result.documented = true
+			result.description = []string{strings.TrimSpace(strings.TrimPrefix(line, "--"))}
+			inDescription = true
+		case strings.HasPrefix(line, "@default --"):
+			result.def = strings.TrimSpace(strings.TrimPrefix(line, "@default --"))
+			inDescription = false
+		case strings.HasPrefix(line, "@section --"):
+			result.section = strings.TrimSpace(strings.TrimPrefix(line, "@section --"))
+			inDescription = false
+		case line == "@ignored":
+			result.ignored = true
+		case strings.HasPrefix(line, "@"):
+			// Other annotations (eg. @raw) do not change the documentation
+			inDescription = false
+		case inDescription:
+			result.description = append(result.description, line)
+		}
+	}
+
+	if len(result.description) > 0 {
+		if match := helmDocsTypeExp.FindStringSubmatch(result.description[0]); match != nil {
+			result.typ = match[1]
+			result.description[0] = strings.TrimPrefix(result.description[0], match[0])
+		}
+	}
+
+	return result
+}
+
+type helmDocsParser struct {
+	document *Document
+	sections map[string]int
+	// pathComments are the descriptions of old style comments, keyed by path.
+	pathComments map[string]helmDocsComment
+}
+
+// parseHelmDocs parses the documentation from the comments used by
+// norwoodj/helm-docs: "# -- description" comments above values, optionally
+// with "# @default --", "# @section --" and "# @ignored" annotations, and
+// old style "# path -- description" comments anywhere in the file.
+func parseHelmDocs(contents []byte, root *yaml.Node) (*Document, error) {
+	p := helmDocsParser{
+		document:     &Document{Sections: make([]Section, 1)},
+		sections:     map[string]int{"": 0},
+		pathComments: map[string]helmDocsComment{},
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for scanner.Scan() {
+		match := helmDocsPathCommentExp.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		path, err := paths.Parse(match[1])
+		if err != nil {
+			continue
+		}
+
+		p.pathComments[path.String()] = parseHelmDocsComment("-- " + match[2])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(root.Content) > 0 {
+		p.walk(root.Content[0], paths.Path{}, "", 0)
+	}
+
+	return p.document, nil
+}
+
+func (p *helmDocsParser) walk(node *yaml.Node, path paths.Path, rawComment string, line int) {
+	comment := parseHelmDocsComment(rawComment)
+	if comment.ignored {
+		return
+	}
+
+	if !comment.documented {
+		if pathComment, ok := p.pathComments[path.String()]; ok {
+			pathComment.def, pathComment.section = comment.def, comment.section
+			comment = pathComment
+		}
+	}
+
+	isLeaf := node.Kind == yaml.ScalarNode || node.Kind == yaml.AliasNode || len(node.Content) == 0
+	if len(path) > 0 && (comment.documented || isLeaf) {
+		p.addProperty(node, path, comment, line)
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			p.walk(valueNode, path.WithProperty(keyNode.Value), keyNode.HeadComment, keyNode.Line)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			p.walk(item, path.WithIndex(i), item.HeadComment, item.Line)
+		}
+	}
+}
+
+func (p *helmDocsParser) addProperty(node *yaml.Node, path paths.Path, comment helmDocsComment, line int) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	property := Property{
+		Path:        path,
+		Description: parseText(strings.Join(comment.description, "\n")),
+		Type:        getTypeOf(Node{RawNode: node}, Comment{}),
+		Default:     comment.def,
+		Line:        line,
+	}
+
+	if comment.typ != "" {
+		property.Type = Type(comment.typ)
+	}
+
+	// The "# @default --" annotation describes the effective default, like
+	// a +docs:default-from tag
+	if comment.def != "" {
+		property.Description.Tags.Push("+" + TagDefaultFrom + "=" + comment.def)
+	}
+
+	if property.Default == "" {
+		property.Default = getDefaultValue(Node{RawNode: node}, Comment{})
+	}
+
+	sectionIdx, ok := p.sections[comment.section]
+	if !ok {
+		sectionIdx = len(p.document.Sections)
+		p.sections[comment.section] = sectionIdx
+		p.document.Sections = append(p.document.Sections, Section{Name: comment.section})
+	}
+
+	p.document.Sections[sectionIdx].Properties = append(p.document.Sections[sectionIdx].Properties, property)
+}
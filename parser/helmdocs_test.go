@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const helmDocsValues = `# Default values for the chart.
+
+# -- (int) Number of replicas
+replicaCount: 1
+
+image:
+  # -- Image repository
+  repository: nginx
+  # -- Overrides the image tag
+  # @default -- The chart appVersion
+  tag: ""
+  pullPolicy: IfNotPresent
+
+# -- Pod annotations
+# @section -- Pods
+podAnnotations:
+  a: b
+
+# @ignored
+internal:
+  foo: bar
+
+# service.port -- The service port
+service:
+  port: 80
+`
+
+func TestParseHelmDocs(t *testing.T) {
+	document, err := ParseWithOptions(strings.NewReader(helmDocsValues), t.TempDir(), Options{Dialect: DialectHelmDocs})
+	require.NoError(t, err)
+
+	type property struct {
+		section, path, description, typ, def string
+	}
+
+	var properties []property
+	for _, section := range document.Sections {
+		for _, p := range section.Properties {
+			properties = append(properties, property{section.Name, p.Path.String(), p.Description.String(), string(p.Type), p.Default})
+		}
+	}
+
+	require.Equal(t, []property{
+		{"", "replicaCount", "Number of replicas", "int", "1"},
+		{"", "image.repository", "Image repository", "string", "nginx"},
+		{"", "image.tag", "Overrides the image tag", "string", "The chart appVersion"},
+		{"", "image.pullPolicy", "", "string", "IfNotPresent"},
+		{"", "service.port", "The service port", "number", "80"},
+		{"Pods", "podAnnotations", "Pod annotations", "object", "a: b"},
+	}, properties)
+}
+
+func TestParseHelmDocsComment(t *testing.T) {
+	comment := parseHelmDocsComment("# Not part of the description\n# -- The description\n# continued here\n# @default -- 3")
+	require.True(t, comment.documented)
+	require.Equal(t, []string{"The description", "continued here"}, comment.description)
+	require.Equal(t, "3", comment.def)
+
+	comment = parseHelmDocsComment("# A regular comment")
+	require.False(t, comment.documented)
+}
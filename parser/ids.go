@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+)
+
+// PropertyIDs are the stable identifiers of the properties of a chart, these
+// are kept in a sidecar file next to the values file so the identifiers do
+// not change when properties are renamed or reordered.
+type PropertyIDs struct {
+	// Properties maps the paths of the properties to their identifiers.
+	Properties map[string]PropertyID `json:"properties"`
+}
+
+// PropertyID is the stable identifier of a property.
+type PropertyID struct {
+	// ID is the identifier of the property, see NewPropertyID.
+	ID string `json:"id"`
+	// FirstSeen is the version of the chart the property was first seen in.
+	FirstSeen string `json:"firstSeen"`
+	// Fingerprint is the hash of the type, default and description of the
+	// property, which are used to detect renamed properties.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// LoadPropertyIDs reads a sidecar file of property identifiers, an empty set
+// of identifiers is returned if the file does not exist yet.
+func LoadPropertyIDs(filename string) (*PropertyIDs, error) {
+	ids := &PropertyIDs{Properties: map[string]PropertyID{}}
+
+	contents, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(contents, ids); err != nil {
+		return nil, err
+	}
+
+	if ids.Properties == nil {
+		ids.Properties = map[string]PropertyID{}
+	}
+
+	return ids, nil
+}
+
+// Marshal returns the sidecar file of the identifiers, the properties are
+// sorted by path so the file has a stable diff.
+func (ids *PropertyIDs) Marshal() ([]byte, error) {
+	contents, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(contents, '\n'), nil
+}
+
+// NewPropertyID returns the identifier of a property that is not in a sidecar
+// file: the hash of the path the property was first documented under, which
+// is its oldest +docs:alias path (added when a value is renamed) or its
+// current path. The identifier does not depend on the sidecar file, so
+// tools without access to it (eg. the telemetry of a chart) compute the same
+// identifiers.
+func NewPropertyID(property Property) string {
+	path := property.Path.String()
+	if aliases := property.Aliases(); len(aliases) > 0 {
+		path = aliases[0]
+	}
+
+	hash := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(hash[:6])
+}
+
+// fingerprint returns the hash of the type, default and description of the
+// property, which usually do not change when a property is renamed.
+func fingerprint(property Property) string {
+	hash := sha256.Sum256([]byte(property.Type.String() + "\x00" + property.Default + "\x00" + property.Description.String()))
+	return hex.EncodeToString(hash[:6])
+}
+
+// SetIDs sets the ID of the properties in the document from the identifiers,
+// properties that are seen for the first time are added to the identifiers
+// with the version (eg. the version of the chart). Renamed properties keep
+// the identifier of their previous path, these are detected using
+// +docs:alias tags or, for properties that are not in the identifiers, by a
+// single removed property with the same type, default and description. It
+// returns whether the identifiers changed and need to be saved.
+func (d *Document) SetIDs(ids *PropertyIDs, version string) bool {
+	var properties []*Property
+	current := map[string]bool{}
+	for i := range d.Sections {
+		for j := range d.Sections[i].Properties {
+			property := &d.Sections[i].Properties[j]
+			properties = append(properties, property)
+			current[property.Path.String()] = true
+		}
+	}
+
+	changed := false
+	setID := func(property *Property, id PropertyID) {
+		path := property.Path.String()
+		id.Fingerprint = fingerprint(*property)
+		if ids.Properties[path] != id {
+			ids.Properties[path] = id
+			changed = true
+		}
+
+		property.ID = id.ID
+	}
+
+	var unknown []*Property
+	for _, property := range properties {
+		id, ok := ids.Properties[property.Path.String()]
+		if !ok {
+			// The newest alias is the path the property was renamed from
+			aliases := property.Aliases()
+			for k := len(aliases) - 1; k >= 0 && !ok; k-- {
+				if id, ok = ids.Properties[aliases[k]]; ok {
+					delete(ids.Properties, aliases[k])
+				}
+			}
+		}
+
+		if !ok {
+			unknown = append(unknown, property)
+			continue
+		}
+
+		setID(property, id)
+	}
+
+	// Properties that were renamed without an alias are matched to the
+	// removed properties by their fingerprint, if it is unambiguous
+	removed := map[string][]string{}
+	for path, id := range ids.Properties {
+		if !current[path] && id.Fingerprint != "" {
+			removed[id.Fingerprint] = append(removed[id.Fingerprint], path)
+		}
+	}
+
+	added := map[string]int{}
+	for _, property := range unknown {
+		added[fingerprint(*property)]++
+	}
+
+	for _, property := range unknown {
+		key := fingerprint(*property)
+		if candidates := removed[key]; len(candidates) == 1 && added[key] == 1 {
+			id := ids.Properties[candidates[0]]
+			delete(ids.Properties, candidates[0])
+			setID(property, id)
+			continue
+		}
+
+		setID(property, PropertyID{ID: NewPropertyID(*property), FirstSeen: version})
+	}
+
+	return changed
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetIDs(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "ids.json")
+
+	ids, err := LoadPropertyIDs(filename)
+	require.NoError(t, err)
+	require.Empty(t, ids.Properties)
+
+	setIDs := func(values string, version string) (map[string]string, bool) {
+		document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+		require.NoError(t, err)
+
+		changed := document.SetIDs(ids, version)
+
+		propertyIDs := map[string]string{}
+		for _, property := range document.Sections[0].Properties {
+			propertyIDs[property.Path.String()] = property.ID
+		}
+
+		return propertyIDs, changed
+	}
+
+	first, changed := setIDs("replicas: 1\ntimeoutSeconds: 3\n", "1.0.0")
+	require.True(t, changed)
+	require.Len(t, first, 2)
+	require.NotEqual(t, first["replicas"], first["timeoutSeconds"])
+	require.Len(t, first["replicas"], 12)
+
+	// The IDs of new properties do not depend on the sidecar file
+	document, err := Parse(strings.NewReader("replicas: 1\n"), t.TempDir(), false)
+	require.NoError(t, err)
+	require.Equal(t, first["replicas"], NewPropertyID(document.Sections[0].Properties[0]))
+
+	// Reordering the properties keeps their IDs
+	reordered, changed := setIDs("timeoutSeconds: 3\nreplicas: 1\n", "1.1.0")
+	require.False(t, changed)
+	require.Equal(t, first, reordered)
+
+	// Renamed properties keep the ID of their previous path, new properties
+	// are added with the version they were first seen in
+	renamed, changed := setIDs("replicas: 1\n# +docs:alias=timeoutSeconds\ntimeout: 3\nnew: true\n", "1.2.0")
+	require.True(t, changed)
+	require.Equal(t, first["replicas"], renamed["replicas"])
+	require.Equal(t, first["timeoutSeconds"], renamed["timeout"])
+	require.NotContains(t, ids.Properties, "timeoutSeconds")
+	require.Equal(t, "1.0.0", ids.Properties["timeout"].FirstSeen)
+	require.Equal(t, "1.2.0", ids.Properties["new"].FirstSeen)
+
+	// Properties renamed without an alias are detected by their type,
+	// default and description, if the match is unambiguous
+	moved, changed := setIDs("replicas: 1\n# Timeout\ntimeout: 3\n# Enabled\nnew: true\n", "1.3.0")
+	require.True(t, changed)
+	require.Equal(t, first["replicas"], moved["replicas"])
+	require.Equal(t, renamed["new"], moved["new"])
+	moved, changed = setIDs("replicas: 1\n# Timeout\nrequestTimeout: 3\n# Enabled\nenabled: true\n", "1.4.0")
+	require.True(t, changed)
+	require.Equal(t, first["timeoutSeconds"], moved["requestTimeout"])
+	require.Equal(t, renamed["new"], moved["enabled"])
+	require.Equal(t, "1.2.0", ids.Properties["enabled"].FirstSeen)
+	require.NotContains(t, ids.Properties, "timeout")
+
+	ambiguous, _ := setIDs("replicas: 1\n# Timeout\nrequestTimeout: 3\n# Enabled\nenabled: true\na: 1\nb: 1\n", "1.5.0")
+	_, changed = setIDs("replicas: 1\n# Timeout\nrequestTimeout: 3\n# Enabled\nenabled: true\nc: 1\nd: 1\n", "1.6.0")
+	require.True(t, changed)
+	require.NotContains(t, []string{ambiguous["a"], ambiguous["b"]}, ids.Properties["c"].ID)
+	require.Equal(t, "1.6.0", ids.Properties["d"].FirstSeen)
+
+	contents, err := ids.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, contents, 0o644))
+
+	loaded, err := LoadPropertyIDs(filename)
+	require.NoError(t, err)
+	require.Equal(t, ids, loaded)
+}
@@ -0,0 +1,475 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// Migrate rewrites the documentation annotations of another dialect (see
+// DialectBitnami and DialectHelmDocs) in a values file into +docs: tags. The
+// annotations are replaced by comments directly above the values they
+// document, all values and other comments are kept as they are.
+func Migrate(contents []byte, dialect string) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(contents, &root); err != nil {
+		return nil, err
+	}
+
+	m := newMigration(string(contents), &root)
+	switch dialect {
+	case DialectBitnami:
+		m.migrateBitnami()
+	case DialectHelmDocs:
+		m.migrateHelmDocs()
+	default:
+		return nil, fmt.Errorf("cannot migrate from dialect %q", dialect)
+	}
+
+	migrated := m.bytes()
+
+	var original, updated any
+	if err := yaml.Unmarshal(contents, &original); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(migrated, &updated); err != nil {
+		return nil, fmt.Errorf("migrating produced invalid yaml: %w", err)
+	}
+	if !reflect.DeepEqual(original, updated) {
+		return nil, fmt.Errorf("migrating changed the values in the document")
+	}
+
+	return migrated, nil
+}
+
+// migrationKey is a key in the values file that documentation can be moved
+// above.
+type migrationKey struct {
+	path  paths.Path
+	line  int
+	value *yaml.Node
+}
+
+// migratedDoc is the documentation of a key, written as +docs: comments.
+type migratedDoc struct {
+	// at is the line the comments are inserted in front of, by default the
+	// start of the comment block above the key.
+	at          int
+	separate    bool
+	section     string
+	description []string
+	typ         string
+	def         string
+	ignore      bool
+}
+
+// migration records the changes to the lines of a values file, the line
+// numbers refer to the original lines.
+type migration struct {
+	lines        []string
+	ending       string
+	finalNewline bool
+
+	keys     []migrationKey
+	keyPaths map[string]int
+	keyLines map[int]int
+
+	deleted  map[int]bool
+	replaced map[int][]string
+	inserted map[int][]string
+	docs     map[int]*migratedDoc
+}
+
+func newMigration(contents string, root *yaml.Node) *migration {
+	m := migration{
+		ending:       "\n",
+		finalNewline: strings.HasSuffix(contents, "\n"),
+		keyPaths:     map[string]int{},
+		keyLines:     map[int]int{},
+		deleted:      map[int]bool{},
+		replaced:     map[int][]string{},
+		inserted:     map[int][]string{},
+		docs:         map[int]*migratedDoc{},
+	}
+
+	if strings.Contains(contents, "\r\n") {
+		m.ending = "\r\n"
+	}
+
+	m.lines = strings.Split(strings.TrimSuffix(contents, "\n"), "\n")
+	for i := range m.lines {
+		m.lines[i] = strings.TrimSuffix(m.lines[i], "\r")
+	}
+
+	if len(root.Content) > 0 {
+		m.collectKeys(root.Content[0], paths.Path{})
+	}
+
+	return &m
+}
+
+func (m *migration) collectKeys(node *yaml.Node, path paths.Path) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			key := migrationKey{
+				path:  path.WithProperty(keyNode.Value),
+				line:  keyNode.Line - 1,
+				value: valueNode,
+			}
+
+			m.keyPaths[key.path.String()] = len(m.keys)
+			m.keyLines[key.line] = len(m.keys)
+			m.keys = append(m.keys, key)
+
+			m.collectKeys(valueNode, key.path)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			m.collectKeys(item, path.WithIndex(i))
+		}
+	}
+}
+
+// doc returns the documentation of the key at path, or nil if there is no
+// such key.
+func (m *migration) doc(path paths.Path) *migratedDoc {
+	idx, ok := m.keyPaths[path.String()]
+	if !ok {
+		return nil
+	}
+
+	line := m.keys[idx].line
+	if m.docs[line] == nil {
+		m.docs[line] = &migratedDoc{at: -1}
+	}
+
+	return m.docs[line]
+}
+
+func (m *migration) migrateBitnami() {
+	for i := 0; i < len(m.lines); i++ {
+		match := bitnamiAnnotationExp.FindStringSubmatch(m.lines[i])
+		if match == nil {
+			continue
+		}
+
+		indent := leadingSpace(m.lines[i])
+		annotation, value := match[1], strings.TrimSpace(match[2])
+		switch annotation {
+		case "section":
+			replacement := []string{indent + "# +" + TagSection + "=" + value}
+
+			// The section description follows the section tag in the same
+			// comment block
+			end := i
+			if i+1 < len(m.lines) && bitnamiAnnotation(m.lines[i+1]) == "descriptionStart" {
+				for end = i + 1; end+1 < len(m.lines); end++ {
+					m.deleted[end] = true
+					if bitnamiAnnotation(m.lines[end]) == "descriptionEnd" {
+						break
+					}
+
+					if end > i+1 {
+						replacement = append(replacement, indent+commentLine(strings.TrimLeft(strings.TrimSpace(m.lines[end]), "#")))
+					}
+				}
+			}
+
+			if end+1 < len(m.lines) && strings.TrimSpace(m.lines[end+1]) != "" {
+				replacement = append(replacement, "")
+			}
+
+			m.replaced[i] = replacement
+			i = end
+
+		case "descriptionStart", "descriptionEnd":
+			m.deleted[i] = true
+
+		case "skip":
+			m.deleted[i] = true
+
+			path, err := paths.Parse(value)
+			if err != nil {
+				log.Printf("could not parse @skip path %q: %s\n", value, err)
+				continue
+			}
+
+			if doc := m.doc(path); doc != nil {
+				doc.ignore = true
+			} else {
+				log.Printf("@skip %q does not match any value\n", value)
+			}
+
+		case "param", "extra":
+			pathString, rest, _ := strings.Cut(value, " ")
+			modifiers, description := splitBitnamiModifiers(strings.TrimSpace(rest))
+
+			path, err := paths.Parse(pathString)
+			if err != nil {
+				log.Printf("could not parse @%s path %q: %s\n", annotation, pathString, err)
+				continue
+			}
+
+			doc := m.doc(path)
+			standalone := doc == nil
+			if standalone {
+				doc = &migratedDoc{}
+			}
+
+			if description != "" {
+				doc.description = []string{description}
+			}
+
+			for _, modifier := range modifiers {
+				switch {
+				case modifier == "array", modifier == "object", modifier == "string":
+					doc.typ = modifier
+				case strings.HasPrefix(modifier, "default:"):
+					doc.def = strings.TrimSpace(strings.TrimPrefix(modifier, "default:"))
+				}
+			}
+
+			m.deleted[i] = true
+			if standalone {
+				// Values that are not in the file are documented using a
+				// +docs:property tag in a separate comment block
+				var replacement []string
+				if i > 0 && !m.deleted[i-1] && isCommentLine(m.lines[i-1]) {
+					replacement = append(replacement, "")
+				}
+
+				replacement = append(replacement, m.render(indent, doc, "+"+TagProperty+"="+path.String())...)
+				m.replaced[i] = append(replacement, "")
+			}
+		}
+	}
+
+	// Remove the comment blocks that only consisted of annotations
+	for i := 0; i < len(m.lines); {
+		if m.deleted[i] || !isCommentLine(m.lines[i]) {
+			i++
+			continue
+		}
+
+		end, empty := i, true
+		for ; end < len(m.lines) && (m.deleted[end] || isCommentLine(m.lines[end])) && m.replaced[end] == nil; end++ {
+			if !m.deleted[end] && strings.Trim(strings.TrimSpace(m.lines[end]), "#") != "" {
+				empty = false
+			}
+		}
+
+		if empty {
+			for j := i; j < end; j++ {
+				m.deleted[j] = true
+			}
+		}
+
+		i = max(end, i+1)
+	}
+}
+
+func (m *migration) migrateHelmDocs() {
+	// Old style comments refer to a value by its path
+	for i, line := range m.lines {
+		match := helmDocsPathCommentExp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		path, err := paths.Parse(match[1])
+		if err != nil {
+			continue
+		}
+
+		doc := m.doc(path)
+		if doc == nil {
+			log.Printf("comment for %q does not match any value\n", match[1])
+			continue
+		}
+
+		comment := parseHelmDocsComment("-- " + match[2])
+		doc.description, doc.typ = comment.description, comment.typ
+		m.deleted[i] = true
+	}
+
+	section := ""
+	for _, key := range m.keys {
+		start := key.line
+		for start > 0 && isCommentLine(m.lines[start-1]) {
+			start--
+		}
+
+		// The helm-docs comment starts at the first "# --" or annotation line,
+		// the comments before it are not documentation
+		docStart := -1
+		for i := start; i < key.line && docStart == -1; i++ {
+			content := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(m.lines[i]), "#"))
+			if content == "--" || strings.HasPrefix(content, "-- ") || strings.HasPrefix(content, "@") {
+				docStart = i
+			}
+		}
+
+		if docStart == -1 {
+			continue
+		}
+
+		comment := parseHelmDocsComment(strings.Join(m.lines[docStart:key.line], "\n"))
+		doc := m.doc(key.path)
+		doc.at = docStart
+		doc.separate = docStart > start
+		if comment.documented {
+			doc.description, doc.typ = comment.description, comment.typ
+		}
+		doc.def = comment.def
+		doc.ignore = comment.ignored
+		if comment.section != "" && comment.section != section {
+			doc.section = comment.section
+			section = comment.section
+		}
+
+		for i := docStart; i < key.line; i++ {
+			m.deleted[i] = true
+		}
+	}
+}
+
+// render returns the +docs: comment lines of the documentation.
+func (m *migration) render(indent string, doc *migratedDoc, tags ...string) []string {
+	var lines []string
+	if doc.separate {
+		lines = append(lines, "")
+	}
+
+	if doc.section != "" {
+		lines = append(lines, indent+"# +"+TagSection+"="+doc.section, "")
+	}
+
+	for _, line := range doc.description {
+		lines = append(lines, indent+commentLine(line))
+	}
+
+	if doc.ignore {
+		tags = append(tags, "+"+TagIgnore)
+	}
+	if doc.typ != "" {
+		tags = append(tags, "+"+TagType+"="+doc.typ)
+	}
+	if doc.def != "" {
+		tags = append(tags, "+"+TagDefault+"="+doc.def)
+	}
+
+	for _, tag := range tags {
+		lines = append(lines, indent+"# "+tag)
+	}
+
+	return lines
+}
+
+// bytes returns the migrated values file.
+func (m *migration) bytes() []byte {
+	inserted := maps.Clone(m.inserted)
+	for _, key := range m.keys {
+		doc := m.docs[key.line]
+		if doc == nil {
+			continue
+		}
+
+		// Values that are not scalars are only documented as a whole if
+		// none of their children are documented
+		var tags []string
+		if !doc.ignore && key.value.Kind != yaml.ScalarNode && len(key.value.Content) > 0 && !m.hasDocumentedChildren(key.path) {
+			tags = append(tags, "+"+TagProperty)
+		}
+
+		at := doc.at
+		if at == -1 {
+			at = key.line
+			for at > 0 && (m.deleted[at-1] || isCommentLine(m.lines[at-1])) && m.replaced[at-1] == nil {
+				at--
+			}
+		}
+
+		lines := m.render(leadingSpace(m.lines[key.line]), doc, tags...)
+		inserted[at] = append(inserted[at], lines...)
+	}
+
+	var sb strings.Builder
+	var written []string
+	for i, line := range m.lines {
+		written = append(written, inserted[i]...)
+
+		switch {
+		case m.replaced[i] != nil:
+			written = append(written, m.replaced[i]...)
+		case !m.deleted[i]:
+			written = append(written, line)
+		}
+	}
+
+	for i, line := range written {
+		if i > 0 {
+			sb.WriteString(m.ending)
+		}
+		sb.WriteString(line)
+	}
+	if m.finalNewline {
+		sb.WriteString(m.ending)
+	}
+
+	return []byte(sb.String())
+}
+
+func (m *migration) hasDocumentedChildren(path paths.Path) bool {
+	return slices.ContainsFunc(m.keys, func(key migrationKey) bool {
+		return len(key.path) > len(path) && path.IsSubPathOf(key.path) && m.docs[key.line] != nil
+	})
+}
+
+func bitnamiAnnotation(line string) string {
+	if match := bitnamiAnnotationExp.FindStringSubmatch(line); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+func commentLine(content string) string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "#"
+	}
+
+	return "# " + content
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
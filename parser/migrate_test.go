@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	type property struct {
+		path, description, typ, def string
+	}
+
+	properties := func(document *Document) []property {
+		var result []property
+		for _, section := range document.Sections {
+			for _, p := range section.Properties {
+				result = append(result, property{p.Path.String(), p.Description.String(), string(p.Type), p.Default})
+			}
+		}
+
+		sort.Slice(result, func(i, j int) bool { return result[i].path < result[j].path })
+		return result
+	}
+
+	for _, dialect := range []string{DialectBitnami, DialectHelmDocs} {
+		t.Run(dialect, func(t *testing.T) {
+			values := map[string]string{DialectBitnami: bitnamiValues, DialectHelmDocs: helmDocsValues}[dialect]
+
+			migrated, err := Migrate([]byte(values), dialect)
+			require.NoError(t, err)
+			require.NotContains(t, string(migrated), "@param")
+			require.NotContains(t, string(migrated), "# --")
+
+			expected, err := ParseWithOptions(strings.NewReader(values), t.TempDir(), Options{Dialect: dialect})
+			require.NoError(t, err)
+
+			actual, err := ParseWithOptions(bytes.NewReader(migrated), t.TempDir(), Options{})
+			require.NoError(t, err)
+
+			require.Equal(t, properties(expected), properties(actual))
+		})
+	}
+}
+
+func TestMigrateUnknownDialect(t *testing.T) {
+	_, err := Migrate([]byte("a: 1\n"), "unknown")
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"regexp"
+
+	"github.com/cert-manager/helm-tool/timings"
+)
+
+// DefaultTagPrefix is the prefix of the tags in comments, eg. the "+docs:" in
+// "+docs:type=string".
+const DefaultTagPrefix = "+docs:"
+
+// The dialects of the documentation comments that can be parsed.
+const (
+	// DialectDefault is the +docs: tags of this tool.
+	DialectDefault = ""
+	// DialectBitnami is the "## @param" annotations of Bitnami's
+	// readme-generator-for-helm.
+	DialectBitnami = "bitnami"
+	// DialectHelmDocs is the "# --" comments of norwoodj/helm-docs.
+	DialectHelmDocs = "helm-docs"
+)
+
+// Options configures how a values file is parsed.
+type Options struct {
+	// IncludeHidden includes the properties that have a +docs:hidden tag.
+	IncludeHidden bool
+	// TagPrefix is an alternative prefix for the tags in comments, eg. with
+	// "@" the type of a property can be set using "@type=string". Tags using
+	// the DefaultTagPrefix are still recognized, so charts can move to a
+	// different prefix gradually.
+	TagPrefix string
+	// Dialect is the convention used by the documentation comments, see
+	// DialectDefault, DialectBitnami and DialectHelmDocs.
+	Dialect string
+	// StrictTags fails parsing if the values file contains comments that look
+	// like tags but are not recognized, instead of logging a warning.
+	StrictTags bool
+	// UnknownType is the type of the properties whose type cannot be
+	// determined (eg. "object"), instead of TypeUnknown.
+	UnknownType string
+	// StrictTypes fails parsing if the type of a property cannot be
+	// determined, instead of logging a warning listing these properties.
+	StrictTypes bool
+	// Timings records the time spent parsing the yaml ("parse") and the
+	// documentation comments ("comments") if set.
+	Timings *timings.Timings
+}
+
+// normalizeTags rewrites the comment lines starting with the TagPrefix to use
+// the DefaultTagPrefix, which is what the rest of the parser understands.
+func (o Options) normalizeTags(contents []byte) []byte {
+	if o.TagPrefix == "" || o.TagPrefix == DefaultTagPrefix {
+		return contents
+	}
+
+	prefixExp := regexp.MustCompile(`(?m)^([ \t]*#[ \t]*)` + regexp.QuoteMeta(o.TagPrefix))
+	return prefixExp.ReplaceAll(contents, []byte("${1}"+DefaultTagPrefix))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagPrefix(t *testing.T) {
+	values := `# @section=Global
+
+image:
+  # The image tag
+  # @type=string
+  tag: 1
+  # The pull policy
+  # +docs:hidden
+  pullPolicy: Always
+# Email address, eg. user@example.com
+email: ""
+`
+
+	document, err := ParseWithOptions(strings.NewReader(values), t.TempDir(), Options{TagPrefix: "@"})
+	require.NoError(t, err)
+
+	require.Equal(t, "Global", document.Sections[1].Name)
+	require.Len(t, document.Sections[1].Properties, 2)
+	require.Equal(t, TypeString, document.Sections[1].Properties[0].Type)
+	require.Equal(t, "Email address, eg. user@example.com", document.Sections[1].Properties[1].Description.String())
+
+	// Without the prefix the tags are part of the description
+	document, err = Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+	require.Len(t, document.Sections, 1)
+}
+
+func TestStrictTags(t *testing.T) {
+	values := "# +docs:defualt=1\nreplicas: 1\n"
+
+	_, err := ParseWithOptions(strings.NewReader(values), t.TempDir(), Options{StrictTags: true})
+	require.ErrorContains(t, err, `did you mean "+docs:default"?`)
+
+	_, err = ParseWithOptions(strings.NewReader(values), t.TempDir(), Options{})
+	require.NoError(t, err)
+}
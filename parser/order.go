@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// weight returns the weight set using a +docs:weight tag, and whether the
+// property has a weight.
+func (p Property) weight() (int, bool, error) {
+	value := p.Description.Tags.GetString(TagWeight)
+	if value == "" {
+		return 0, false, nil
+	}
+
+	weight, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid weight %q for %q: %w", value, p.Path, err)
+	}
+
+	return weight, true, nil
+}
+
+// Weight returns the weight set using a +docs:weight tag, and whether the
+// property has a valid weight.
+func (p Property) Weight() (int, bool) {
+	weight, ok, err := p.weight()
+	return weight, ok && err == nil
+}
+
+// sortProperties orders the properties within each section. Properties with a
+// +docs:weight tag are listed first, ordered by ascending weight. The other
+// properties keep the order in which they appear in the values file.
+func (d *Document) sortProperties() error {
+	for i := range d.Sections {
+		properties := d.Sections[i].Properties
+
+		type sortKey struct {
+			value    int
+			weighted bool
+		}
+
+		weights := make(map[string]sortKey, len(properties))
+		for _, property := range properties {
+			value, weighted, err := property.weight()
+			if err != nil {
+				return err
+			}
+
+			weights[property.Path.String()] = sortKey{value, weighted}
+		}
+
+		sort.SliceStable(properties, func(a, b int) bool {
+			wa, wb := weights[properties[a].Path.String()], weights[properties[b].Path.String()]
+			if wa.weighted != wb.weighted {
+				return wa.weighted
+			}
+
+			return wa.value < wb.value
+		})
+	}
+
+	return nil
+}
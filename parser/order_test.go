@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const weightValues = `# Unweighted first
+a: 1
+
+# Weighted last
+# +docs:weight=20
+b: 2
+
+# Unweighted second
+c: 3
+
+# Equal weight, first in the file
+# +docs:weight=10
+d: 4
+
+# Negative weight
+# +docs:weight=-1
+e: 5
+
+# Equal weight, second in the file
+# +docs:weight=10
+f: 6
+`
+
+func TestSortProperties(t *testing.T) {
+	document, err := Parse(strings.NewReader(weightValues), t.TempDir(), false)
+	require.NoError(t, err)
+
+	// Weighted properties come first by ascending weight, properties with
+	// equal weights and unweighted properties keep the order of the file
+	var order []string
+	for _, property := range document.Sections[0].Properties {
+		order = append(order, property.Path.String())
+	}
+	require.Equal(t, []string{"e", "d", "f", "b", "a", "c"}, order)
+
+	weight, ok := document.Sections[0].Properties[0].Weight()
+	require.True(t, ok)
+	require.Equal(t, -1, weight)
+
+	_, ok = document.Sections[0].Properties[4].Weight()
+	require.False(t, ok)
+
+	_, err = Parse(strings.NewReader("# +docs:weight=high\na: 1\n"), t.TempDir(), false)
+	require.ErrorContains(t, err, `invalid weight "high" for "a"`)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// Override contains documentation for a single property that is maintained
+// outside of the values.yaml file. Any field that is set replaces the
+// information that was inferred from the values.yaml file.
+type Override struct {
+	Description string `yaml:"description"`
+	Type        Type   `yaml:"type"`
+	Default     string `yaml:"default"`
+	Examples    []any  `yaml:"examples"`
+}
+
+// Overrides maps property paths (eg. "controller.image.tag") to the
+// documentation that should be used for that property.
+type Overrides map[string]Override
+
+// LoadOverrides reads an overrides file, this is a YAML file that maps
+// property paths to descriptions, types, defaults and examples.
+func LoadOverrides(filename string) (Overrides, error) {
+	overridesBytes, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides Overrides
+	if err := yaml.Unmarshal(overridesBytes, &overrides); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+// ApplyOverrides merges the overrides over the properties in the document.
+// Overrides for properties that do not exist in the document are reported,
+// but otherwise ignored.
+func (d *Document) ApplyOverrides(overrides Overrides) error {
+	applied := map[string]bool{}
+
+	for pathString, override := range overrides {
+		path, err := paths.Parse(pathString)
+		if err != nil {
+			return fmt.Errorf("could not parse override path %q: %w", pathString, err)
+		}
+
+		for i := range d.Sections {
+			for j := range d.Sections[i].Properties {
+				property := &d.Sections[i].Properties[j]
+				if !property.Path.Equal(path) {
+					continue
+				}
+
+				override.applyTo(property)
+				applied[pathString] = true
+			}
+		}
+	}
+
+	// Report the unmatched overrides in a stable order
+	var unmatched []string
+	for pathString := range overrides {
+		if !applied[pathString] {
+			unmatched = append(unmatched, pathString)
+		}
+	}
+	sort.Strings(unmatched)
+
+	for _, pathString := range unmatched {
+		log.Printf("override for %q does not match any property\n", pathString)
+	}
+
+	return nil
+}
+
+func (o Override) applyTo(property *Property) {
+	if o.Type != "" {
+		property.Type = o.Type
+	}
+
+	if o.Default != "" {
+		property.Default = o.Default
+	}
+
+	if o.Description == "" && len(o.Examples) == 0 {
+		return
+	}
+
+	// Keep the tags of the original comment, only the content is replaced
+	comment := Comment{Tags: property.Description.Tags}
+	for _, segment := range property.Description.Segments {
+		if segment.Type == heuristics.ContentTypeTag {
+			comment.Segments = append(comment.Segments, segment)
+		}
+	}
+
+	if o.Description != "" {
+		comment.Segments = append(comment.Segments, parseText(o.Description).Segments...)
+	} else {
+		for _, segment := range property.Description.Segments {
+			if segment.Type != heuristics.ContentTypeTag {
+				comment.Segments = append(comment.Segments, segment)
+			}
+		}
+	}
+
+	// Examples are rendered as yaml code blocks, keyed by the property name
+	// in the same way examples are usually written in values.yaml comments.
+	for _, example := range o.Examples {
+		var sb strings.Builder
+		encoder := yaml.NewEncoder(&sb)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(map[string]any{paths.SegmentString(property.Path.Property()): example}); err != nil {
+			log.Printf("could not encode example for %q: %s\n", property.Path, err)
+			continue
+		}
+
+		comment.Segments = append(comment.Segments, heuristics.CommentBlockSegment{
+			Type:     heuristics.ContentTypeYaml,
+			Contents: strings.Split(strings.TrimSpace(sb.String()), "\n"),
+		})
+	}
+
+	property.Description = comment
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const overridesValues = `# The image tag
+# +docs:property
+image:
+  tag: v1.0.0
+
+# Number of replicas
+replicas: 1
+`
+
+func TestApplyOverrides(t *testing.T) {
+	document, err := Parse(strings.NewReader(overridesValues), t.TempDir(), false)
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	defer log.SetFlags(log.Flags())
+	log.SetOutput(&logs)
+	log.SetFlags(0)
+
+	require.NoError(t, document.ApplyOverrides(Overrides{
+		"replicas":  {Description: "Replicas of the controller", Type: TypeNumber, Default: "2"},
+		"missing.b": {Description: "Not in the values file"},
+		"missing.a": {Type: TypeString},
+	}))
+
+	property := document.Sections[0].Properties[1]
+	require.Equal(t, "replicas", property.Path.String())
+	require.Equal(t, "Replicas of the controller", property.Description.String())
+	require.Equal(t, TypeNumber, property.Type)
+	require.Equal(t, "2", property.Default)
+
+	// Overrides that match no property are reported in a stable order
+	require.Equal(t, "override for \"missing.a\" does not match any property\noverride for \"missing.b\" does not match any property\n", logs.String())
+
+	require.Error(t, document.ApplyOverrides(Overrides{"a[": {}}))
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// OwnerRule assigns owners to the properties matching a path pattern.
+type OwnerRule struct {
+	// Pattern is a property path in which "*" matches any characters, it
+	// matches the properties at the path and all properties below it.
+	Pattern string
+	Owners  []string
+
+	exp *regexp.Regexp
+}
+
+// OwnerRules is the content of an owners file, the last matching rule
+// determines the owners of a property.
+type OwnerRules []OwnerRule
+
+// LoadOwners reads an owners file. Like a CODEOWNERS file, every line
+// contains a path pattern followed by one or more owners, eg.
+// "webhook.* @org/webhook-team". Empty lines and lines starting with "#" are
+// ignored.
+func LoadOwners(filename string) (OwnerRules, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseOwners(contents)
+}
+
+// ParseOwners parses the contents of an owners file, see LoadOwners.
+func ParseOwners(contents []byte) (OwnerRules, error) {
+	var rules OwnerRules
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) == 1 {
+			return nil, fmt.Errorf("line %d: pattern %q has no owners", line, fields[0])
+		}
+
+		parts := strings.Split(fields[0], "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+
+		rules = append(rules, OwnerRule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			exp:     regexp.MustCompile("^" + strings.Join(parts, ".*") + "$"),
+		})
+	}
+
+	return rules, scanner.Err()
+}
+
+// Match returns whether the rule matches the path or one of its parents.
+func (r OwnerRule) Match(path paths.Path) bool {
+	for ; len(path) > 0; path = path.Parent() {
+		if r.exp.MatchString(path.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OwnersOf returns the owners of the last rule matching the path.
+func (r OwnerRules) OwnersOf(path paths.Path) []string {
+	for i := len(r) - 1; i >= 0; i-- {
+		if r[i].Match(path) {
+			return r[i].Owners
+		}
+	}
+
+	return nil
+}
+
+// ApplyOwners sets the owners of the properties that do not have a
+// +docs:owner tag (on the property, a parent or its section) from the owners
+// file rules.
+func (d *Document) ApplyOwners(rules OwnerRules) {
+	for i := range d.Sections {
+		for j := range d.Sections[i].Properties {
+			property := &d.Sections[i].Properties[j]
+			if len(property.Owners) == 0 {
+				property.Owners = rules.OwnersOf(property.Path)
+			}
+		}
+	}
+}
+
+// Ownership is the list of properties owned by a single owner.
+type Ownership struct {
+	// Owner is empty for the properties that do not have an owner.
+	Owner      string
+	Properties []Property
+}
+
+// ByOwner groups the properties of the document by their owners, sorted by
+// the name of the owner. Properties with multiple owners are listed for each
+// owner, the properties without an owner are listed last.
+func (d *Document) ByOwner() []Ownership {
+	byOwner := map[string][]Property{}
+	for _, section := range d.Sections {
+		for _, property := range section.Properties {
+			if len(property.Owners) == 0 {
+				byOwner[""] = append(byOwner[""], property)
+			}
+
+			for _, owner := range property.Owners {
+				byOwner[owner] = append(byOwner[owner], property)
+			}
+		}
+	}
+
+	result := make([]Ownership, 0, len(byOwner))
+	for owner, properties := range byOwner {
+		result = append(result, Ownership{Owner: owner, Properties: properties})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Owner == "") != (result[j].Owner == "") {
+			return result[j].Owner == ""
+		}
+
+		return result[i].Owner < result[j].Owner
+	})
+
+	return result
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+const ownersValues = `# +docs:section=Main
+# +docs:owner=@org/core
+
+# Replicas
+replicas: 1
+
+# +docs:owner=@org/webhook
+webhook:
+  # Port
+  port: 10250
+  # +docs:owner=@org/security
+  # Secure
+  secure: true
+
+# +docs:section=Other
+
+image:
+  # Tag
+  tag: v1
+  # Registry
+  registry: quay.io
+`
+
+func TestParseOwners(t *testing.T) {
+	document, err := ParseWithOptions(strings.NewReader(ownersValues), t.TempDir(), Options{})
+	require.NoError(t, err)
+
+	rules, err := ParseOwners([]byte("# Owners of the values\nimage.* @org/release\nimage.registry @org/infra @org/release\nreplicas @org/other\n"))
+	require.NoError(t, err)
+	document.ApplyOwners(rules)
+
+	owners := map[string][]string{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			owners[property.Path.String()] = property.Owners
+		}
+	}
+
+	require.Equal(t, map[string][]string{
+		"replicas":       {"@org/core"},
+		"webhook.port":   {"@org/webhook"},
+		"webhook.secure": {"@org/security"},
+		"image.tag":      {"@org/release"},
+		"image.registry": {"@org/infra", "@org/release"},
+	}, owners)
+
+	var report []string
+	for _, owned := range document.ByOwner() {
+		for _, property := range owned.Properties {
+			report = append(report, owned.Owner+": "+property.Path.String())
+		}
+	}
+
+	require.Equal(t, []string{
+		"@org/core: replicas",
+		"@org/infra: image.registry",
+		"@org/release: image.tag",
+		"@org/release: image.registry",
+		"@org/security: webhook.secure",
+		"@org/webhook: webhook.port",
+	}, report)
+}
+
+func TestOwnerRules(t *testing.T) {
+	rules, err := ParseOwners([]byte("webhook @org/webhook\n*.image.tag @org/release\n"))
+	require.NoError(t, err)
+
+	for path, expected := range map[string][]string{
+		"webhook":           {"@org/webhook"},
+		"webhook.port":      {"@org/webhook"},
+		"webhook.image.tag": {"@org/release"},
+		"webhookPort":       nil,
+		"image.tag":         nil,
+	} {
+		parsed, err := paths.Parse(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, rules.OwnersOf(parsed), path)
+	}
+
+	_, err = ParseOwners([]byte("webhook\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	_ "embed"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed sample.yaml
+var sampleValues []byte
+
+// SampleDocument returns a representative document containing all shapes of
+// properties: sections with descriptions, a nested section, scalars, lists,
+// objects documented as a single property, undefaulted properties, enums,
+// deprecated, renamed and hidden properties and long defaults. It is meant
+// for testing custom templates and programs consuming the documentation, a
+// new document is returned on every call so it can be modified.
+func SampleDocument() *Document {
+	document, err := parseSample()
+	if err != nil {
+		panic("could not parse the sample document: " + err.Error())
+	}
+
+	return document
+}
+
+func parseSample() (*Document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(sampleValues, &root); err != nil {
+		return nil, err
+	}
+
+	document, err := parseDocument(&root, Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := document.sortProperties(); err != nil {
+		return nil, err
+	}
+
+	document.setAnchors()
+
+	document.Chart = &Chart{
+		Name:        "sample",
+		Version:     "v1.0.0",
+		AppVersion:  "v1.0.0",
+		Description: "A sample chart documenting all the shapes of properties",
+	}
+	document.setSources()
+
+	return document, nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleDocument(t *testing.T) {
+	document := SampleDocument()
+	require.Equal(t, "sample", document.Chart.Name)
+
+	var sections []string
+	properties := map[string]Property{}
+	for _, section := range document.Sections {
+		sections = append(sections, section.Name)
+		for _, property := range section.Properties {
+			properties[property.Path.String()] = property
+		}
+	}
+	require.Equal(t, []string{"", "Global", "Controller", "Pod disruption budget", "Webhook"}, sections)
+
+	require.Equal(t, []string{"Always", "IfNotPresent", "Never"}, properties["image.pullPolicy"].Enum())
+	require.True(t, properties["image.tag"].Deprecated())
+	require.Equal(t, []string{"imageTag"}, properties["image.tag"].Aliases())
+	require.Equal(t, ".Chart.AppVersion", properties["image.tag"].DefaultFrom())
+	require.Equal(t, "Pod annotations", properties[`podAnnotations`].Name())
+	require.Contains(t, properties["securityContext"].Default, "\n")
+	require.Contains(t, properties, "args[0]")
+	require.Contains(t, properties, "webhook.config")
+	require.NotContains(t, properties, "webhook.debug")
+	require.Equal(t, "Controller", document.Sections[2].Name)
+	require.Equal(t, "resources", document.Sections[2].Properties[len(document.Sections[2].Properties)-1].Path.String())
+
+	// Every call returns a new document
+	document.Sections[1].Name = "changed"
+	require.Equal(t, "Global", SampleDocument().Sections[1].Name)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ForSections returns a copy of the document only containing the sections with
+// the given names, in the order of the document. If no names are given the
+// document is returned unchanged. An error is returned if a name does not
+// match any section.
+func (d *Document) ForSections(names []string) (*Document, error) {
+	if len(names) == 0 {
+		return d, nil
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = false
+	}
+
+	result := *d
+	result.Sections = nil
+
+	for _, section := range d.Sections {
+		if _, ok := wanted[section.Name]; !ok {
+			continue
+		}
+
+		wanted[section.Name] = true
+		result.Sections = append(result.Sections, section)
+	}
+
+	for _, name := range names {
+		if !wanted[name] {
+			available := make([]string, 0, len(d.Sections))
+			for _, section := range d.Sections {
+				if section.Name == "" {
+					continue
+				}
+				available = append(available, fmt.Sprintf("%q", section.Name))
+			}
+
+			return nil, fmt.Errorf("section %q not found, available sections are: %s", name, strings.Join(available, ", "))
+		}
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import "github.com/cert-manager/helm-tool/paths"
+
+const (
+	// SourceChart is the source of the values used by the chart itself.
+	SourceChart = "chart"
+	// SourceGlobal is the source of the values under global, which Helm
+	// passes to the chart and all its subcharts.
+	SourceGlobal = "global"
+)
+
+// HasDependencies returns whether the chart of the document has subcharts,
+// the sources of the properties are only of interest for these charts.
+func (d *Document) HasDependencies() bool {
+	return d.Chart != nil && len(d.Chart.Dependencies) > 0
+}
+
+// setSources sets the source of every property from its top-level key: the
+// global values, the values of a dependency of the chart, or the values of
+// the chart itself.
+func (d *Document) setSources() {
+	dependencies := map[string]bool{}
+	if d.Chart != nil {
+		for _, dependency := range d.Chart.Dependencies {
+			dependencies[dependency.Key()] = true
+		}
+	}
+
+	for i := range d.Sections {
+		for j := range d.Sections[i].Properties {
+			property := &d.Sections[i].Properties[j]
+			property.Source = SourceChart
+			if len(property.Path) == 0 {
+				continue
+			}
+
+			switch key := paths.SegmentString(property.Path[0]); {
+			case key == SourceGlobal:
+				property.Source = SourceGlobal
+			case dependencies[key]:
+				property.Source = key
+			}
+		}
+	}
+}
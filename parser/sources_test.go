@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sourcesValues = `# Image pull secrets of all the charts
+global:
+  # Pull secrets
+  imagePullSecrets: []
+# Replicas
+replicas: 1
+# Values of the redis subchart
+redis:
+  # Enable authentication
+  auth: true
+# Values of the postgresql subchart
+db:
+  # Database name
+  name: app
+`
+
+func TestSources(t *testing.T) {
+	chartDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(`name: app
+version: v1.0.0
+dependencies:
+  - name: redis
+    version: 18.0.0
+  - name: postgresql
+    alias: db
+    version: 13.0.0
+`), 0644))
+
+	document, err := ParseWithOptions(strings.NewReader(sourcesValues), chartDir, Options{})
+	require.NoError(t, err)
+	require.True(t, document.HasDependencies())
+
+	sources := map[string]string{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			sources[property.Path.String()] = property.Source
+		}
+	}
+
+	require.Equal(t, map[string]string{
+		"global.imagePullSecrets": SourceGlobal,
+		"replicas":                SourceChart,
+		"redis.auth":              "redis",
+		"db.name":                 "db",
+	}, sources)
+
+	// Without dependencies the values of the chart are not attributed to a
+	// subchart
+	document, err = ParseWithOptions(strings.NewReader(sourcesValues), t.TempDir(), Options{})
+	require.NoError(t, err)
+	require.False(t, document.HasDependencies())
+	require.Equal(t, SourceChart, document.Sections[0].Properties[2].Source)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TagUse is a known tag in the comments of a values file.
+type TagUse struct {
+	// Line is the line of the tag in the values file.
+	Line int `json:"line"`
+	// Tag is the key of the tag, eg. "docs:type".
+	Tag string `json:"tag"`
+	// Value is the value of the tag, eg. "string".
+	Value string `json:"value,omitempty"`
+	// NoEffect is the reason the tag has no effect on the documentation, it
+	// is empty if the tag is used.
+	NoEffect string `json:"noEffect,omitempty"`
+}
+
+// documentTags are the tags that have an effect wherever they are, as they
+// are applied to the document instead of to a value.
+var documentTags = []string{TagSection, TagSectionEnd, TagProperty}
+
+// metadataTags are the tags of the document metadata, these have an effect
+// in comments that are not the description of a value.
+var metadataTags = []string{TagTitle, TagIntro, TagVersionNote}
+
+// singleValueTags are the tags of which only the last one in a comment is
+// used.
+var singleValueTags = []string{TagType, TagDefault, TagName, TagWeight, TagDeprecated, TagEnum, TagRemovedIn, TagDefaultFrom, TagTitle, TagVersionNote}
+
+// tagBlock is a block of consecutive comment lines.
+type tagBlock struct {
+	tags []TagUse
+	// next is the line following the block.
+	next string
+	// nextLine is the line number of the line following the block.
+	nextLine int
+}
+
+func (b tagBlock) has(tag string) bool {
+	for _, use := range b.tags {
+		if use.Tag == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FindTagUses returns the known tags in the comments of the values file and
+// whether they had an effect, eg. a +docs:type tag above an ignored value or
+// above an object that is documented as its children does nothing. Included
+// files are resolved relative to baseDir, like Parse. This only supports the
+// default dialect.
+func FindTagUses(contents []byte, baseDir string, options Options) ([]TagUse, error) {
+	if options.Dialect != DialectDefault {
+		return nil, fmt.Errorf("tag usage can only be reported for the %q dialect", DialectDefault)
+	}
+
+	options.IncludeHidden = true
+	options.StrictTags = false
+	document, err := ParseWithOptions(bytes.NewReader(contents), baseDir, options)
+	if err != nil {
+		return nil, err
+	}
+
+	propertyLines := map[int]bool{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if property.Line > 0 {
+				propertyLines[property.Line] = true
+			}
+		}
+	}
+
+	var result []TagUse
+	for _, block := range tagBlocks(options.normalizeTags(contents)) {
+		last := map[string]int{}
+		for i, use := range block.tags {
+			last[use.Tag] = i
+		}
+
+		for i, use := range block.tags {
+			if slices.Contains(singleValueTags, use.Tag) && last[use.Tag] != i {
+				use.NoEffect = fmt.Sprintf("overridden by the tag on line %d", block.tags[last[use.Tag]].Line)
+			} else {
+				use.NoEffect = block.noEffect(use.Tag, propertyLines)
+			}
+
+			result = append(result, use)
+		}
+	}
+
+	return result, nil
+}
+
+// noEffect returns why the tag in the block has no effect, or an empty
+// string if it has an effect.
+func (b tagBlock) noEffect(tag string, propertyLines map[int]bool) string {
+	// A comment is attached to the value on the line after it, comments
+	// followed by an empty line are applied to the document
+	attached := b.next != "" && b.next != "---"
+	documented := attached && propertyLines[b.nextLine]
+
+	switch {
+	case tag == TagSection && documented && !b.has(TagProperty):
+		return "a section directly above a value is not started, add an empty line after the comment"
+	case slices.Contains(metadataTags, tag) && documented:
+		return "document tags directly above a value are not used, add an empty line after the comment"
+	case slices.Contains(documentTags, tag), slices.Contains(metadataTags, tag):
+		return ""
+	case b.has(TagProperty):
+		return ""
+	case b.has(TagSection) && !documented:
+		if tag == TagOwner || tag == TagAudience || tag == TagInclude || tag == TagAdvanced {
+			return ""
+		}
+		return "tag is on a section, which does not use it"
+	case !attached:
+		return "comment is not attached to a value, remove the empty line after it"
+	case tag == TagIgnore:
+		return ""
+	case b.has(TagIgnore):
+		return "value is ignored"
+	case documented:
+		return ""
+	case tag == TagOwner || tag == TagAdvanced:
+		// Owners and advanced tags of objects are inherited by their children
+		return ""
+	default:
+		return "value is not documented as a property, eg. it is an object documented as its children (use +docs:property to document it)"
+	}
+}
+
+// tagBlocks splits the comments of the values file into blocks of
+// consecutive comment lines, only the blocks containing known tags are
+// returned.
+func tagBlocks(contents []byte) []tagBlock {
+	var blocks []tagBlock
+	var current *tagBlock
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for line := 1; scanner.Scan(); line++ {
+		trimmed := strings.TrimSpace(scanner.Text())
+		text, ok := strings.CutPrefix(trimmed, "#")
+		if !ok {
+			if current != nil {
+				current.next, current.nextLine = trimmed, line
+				if len(current.tags) > 0 {
+					blocks = append(blocks, *current)
+				}
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil {
+			current = &tagBlock{}
+		}
+
+		text = strings.TrimSpace(text)
+		if !strings.HasPrefix(text, "+docs:") {
+			continue
+		}
+
+		key, value := parseTag(text)
+		if isKnownTag(key) {
+			current.tags = append(current.tags, TagUse{Line: line, Tag: key, Value: value})
+		}
+	}
+
+	if current != nil && len(current.tags) > 0 {
+		blocks = append(blocks, *current)
+	}
+
+	return blocks
+}
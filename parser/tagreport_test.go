@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTagUses(t *testing.T) {
+	values := `# +docs:section=Image
+
+# +docs:type=number
+
+# The image tag
+# +docs:type=number
+# +docs:type=string
+tag: v1
+# +docs:ignore
+# +docs:default=1
+ignored: 1
+# +docs:name=Resources
+resources:
+  # +docs:owner=@org/team
+  limits:
+    cpu: 1
+# +docs:section=Webhook
+# +docs:hidden
+webhook: true
+`
+
+	uses, err := FindTagUses([]byte(values), t.TempDir(), Options{})
+	require.NoError(t, err)
+	require.Equal(t, []TagUse{
+		{Line: 1, Tag: TagSection, Value: "Image"},
+		{Line: 3, Tag: TagType, Value: "number", NoEffect: "comment is not attached to a value, remove the empty line after it"},
+		{Line: 6, Tag: TagType, Value: "number", NoEffect: "overridden by the tag on line 7"},
+		{Line: 7, Tag: TagType, Value: "string"},
+		{Line: 9, Tag: TagIgnore},
+		{Line: 10, Tag: TagDefault, Value: "1", NoEffect: "value is ignored"},
+		{Line: 12, Tag: TagName, Value: "Resources", NoEffect: "value is not documented as a property, eg. it is an object documented as its children (use +docs:property to document it)"},
+		{Line: 14, Tag: TagOwner, Value: "@org/team"},
+		{Line: 17, Tag: TagSection, Value: "Webhook", NoEffect: "a section directly above a value is not started, add an empty line after the comment"},
+		{Line: 18, Tag: TagHidden},
+	}, uses)
+
+	_, err = FindTagUses([]byte(values), t.TempDir(), Options{Dialect: DialectBitnami})
+	require.Error(t, err)
+}
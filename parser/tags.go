@@ -27,11 +27,18 @@ func (t *tags) Push(value string) {
 		*t = make(tags)
 	}
 
-	trimmed := strings.TrimSpace(value)
-	key, value, _ := strings.Cut(trimmed[1:], "=")
+	key, value := parseTag(value)
 	(*t)[key] = append((*t)[key], value)
 }
 
+// parseTag splits a tag line (eg. "+docs:type=string") into its key
+// ("docs:type") and value ("string").
+func parseTag(line string) (string, string) {
+	trimmed := strings.TrimSpace(line)
+	key, value, _ := strings.Cut(trimmed[1:], "=")
+	return key, value
+}
+
 func (t tags) GetBool(key string) bool {
 	result := false
 
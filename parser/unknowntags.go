@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// knownTags are the tags that are understood by the parser.
+var knownTags = []string{
+	TagSection,
+	TagSectionEnd,
+	TagIgnore,
+	TagHidden,
+	TagType,
+	TagDefault,
+	TagProperty,
+	TagInclude,
+	TagAudience,
+	TagSee,
+	TagAlias,
+	TagName,
+	TagWeight,
+	TagDeprecated,
+	TagOwner,
+	TagEnum,
+	TagRemovedIn,
+	TagAdvanced,
+	TagDefaultFrom,
+	TagReadOnly,
+	TagExample,
+	TagTitle,
+	TagIntro,
+	TagVersionNote,
+}
+
+// tagLikeExp matches comment lines that were likely meant to be a tag, but
+// are not recognized as one (eg. "+doc:type=string" or "docs:type=string").
+var tagLikeExp = regexp.MustCompile(`(?i)^(\+\s*docs?\b|docs?:[a-z-]+(=|$))`)
+
+// UnknownTag is a comment line in a values file that looks like a tag, but
+// is not a tag understood by the parser.
+type UnknownTag struct {
+	// Line is the line of the comment in the values file.
+	Line int
+	// Text is the comment line, without the leading "#".
+	Text string
+	// Suggestion is the known tag that was likely meant, it is empty if there
+	// is no similar tag.
+	Suggestion string
+}
+
+func (t UnknownTag) String() string {
+	message := fmt.Sprintf("line %d: unknown tag %q", t.Line, t.Text)
+	if t.Suggestion != "" {
+		message += fmt.Sprintf(", did you mean \"+%s\"?", t.Suggestion)
+	}
+	return message
+}
+
+// FindUnknownTags returns the comment lines in the values file that look like
+// tags, but are not recognized. Without this, typos such as "+docs:defualt"
+// are silently ignored or end up in the description.
+func FindUnknownTags(contents []byte) []UnknownTag {
+	var result []UnknownTag
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "#")
+		if !ok {
+			continue
+		}
+		text = strings.TrimSpace(text)
+
+		var key string
+		switch {
+		case strings.HasPrefix(text, "+docs:"):
+			key, _ = parseTag(text)
+			if isKnownTag(key) {
+				continue
+			}
+		case tagLikeExp.MatchString(text):
+			key, _, _ = strings.Cut(strings.TrimLeft(text, "+ "), "=")
+		default:
+			continue
+		}
+
+		result = append(result, UnknownTag{
+			Line:       line,
+			Text:       text,
+			Suggestion: suggestTag(key),
+		})
+	}
+
+	return result
+}
+
+func isKnownTag(key string) bool {
+	for _, known := range knownTags {
+		if key == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suggestTag returns the known tag closest to the key, if it is close enough
+// to likely be a typo.
+func suggestTag(key string) string {
+	key = strings.ToLower(strings.ReplaceAll(key, " ", ""))
+	if !strings.HasPrefix(key, "docs:") {
+		key = "docs:" + strings.TrimPrefix(key, "doc:")
+	}
+
+	suggestion, best := "", 3
+	for _, known := range knownTags {
+		if distance := editDistance(key, known); distance < best {
+			suggestion, best = known, distance
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUnknownTags(t *testing.T) {
+	values := `# +docs:section=Global
+# The docs: section describes the values
+
+# +docs:defualt=1
+# +doc:type=string
+# docs:hidden
+#   + docs:ignore
+# +docs:something
+image:
+  # +docs:type=string
+  tag: v1 # +docs:typo
+`
+
+	require.Equal(t, []UnknownTag{
+		{Line: 4, Text: "+docs:defualt=1", Suggestion: TagDefault},
+		{Line: 5, Text: "+doc:type=string", Suggestion: TagType},
+		{Line: 6, Text: "docs:hidden", Suggestion: TagHidden},
+		{Line: 7, Text: "+ docs:ignore", Suggestion: TagIgnore},
+		{Line: 8, Text: "+docs:something"},
+	}, FindUnknownTags([]byte(values)))
+
+	require.Equal(t, `line 4: unknown tag "+docs:defualt=1", did you mean "+docs:default"?`, UnknownTag{Line: 4, Text: "+docs:defualt=1", Suggestion: TagDefault}.String())
+}
+
+func TestResolveUnknownTypes(t *testing.T) {
+	values := `a: ~
+b:
+# +docs:type=string
+c: ~
+d: 1
+`
+
+	typesOf := func(options Options) (map[string]Type, error) {
+		document, err := ParseWithOptions(strings.NewReader(values), t.TempDir(), options)
+		if err != nil {
+			return nil, err
+		}
+
+		types := map[string]Type{}
+		for _, property := range document.Sections[0].Properties {
+			types[property.Path.String()] = property.Type
+		}
+
+		return types, nil
+	}
+
+	types, err := typesOf(Options{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]Type{"a": TypeUnknown, "b": TypeUnknown, "c": TypeString, "d": TypeNumber}, types)
+
+	types, err = typesOf(Options{UnknownType: "object"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]Type{"a": TypeObject, "b": TypeObject, "c": TypeString, "d": TypeNumber}, types)
+
+	_, err = typesOf(Options{StrictTypes: true})
+	require.EqualError(t, err, "found 2 properties of unknown type, set their type using +docs:type: a, b")
+}
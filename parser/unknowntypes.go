@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// UnknownTypes returns the paths of the properties whose type could not be
+// determined, eg. because their default is null and they have no +docs:type
+// tag.
+func (d *Document) UnknownTypes() []paths.Path {
+	var result []paths.Path
+	for _, section := range d.Sections {
+		for _, property := range section.Properties {
+			if property.Type == TypeUnknown {
+				result = append(result, property.Path)
+			}
+		}
+	}
+
+	return result
+}
+
+// resolveUnknownTypes reports the properties of unknown type in a single
+// warning, or fails if StrictTypes is set, and sets their type to the
+// UnknownType fallback if set.
+func (d *Document) resolveUnknownTypes(options Options) error {
+	unknownTypes := d.UnknownTypes()
+	if len(unknownTypes) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(unknownTypes))
+	for i, path := range unknownTypes {
+		names[i] = path.String()
+	}
+
+	summary := fmt.Sprintf("found %d properties of unknown type, set their type using +docs:type: %s", len(unknownTypes), strings.Join(names, ", "))
+	if options.StrictTypes {
+		return fmt.Errorf("%s", summary)
+	}
+
+	log.Println(summary)
+
+	if options.UnknownType == "" {
+		return nil
+	}
+
+	for i := range d.Sections {
+		for j := range d.Sections[i].Properties {
+			property := &d.Sections[i].Properties[j]
+			if property.Type == TypeUnknown {
+				property.Type = Type(options.UnknownType)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// UserValue is the value a user values file sets for a property, eg. the
+// values of a release under review.
+type UserValue struct {
+	// Value is the value formatted as YAML, like the Default of a property.
+	Value string `json:"value"`
+	// Changed is set if the value differs from the default of the property.
+	Changed bool `json:"changed"`
+}
+
+// LoadUserValues reads a user values file.
+func LoadUserValues(filename string) (map[string]any, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]any
+	if err := yaml.Unmarshal(contents, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// SetUserValues sets the UserValue of the properties in the document that
+// are set in the values. It returns the paths of the values that do not
+// belong to a documented property, these are likely typos or values the
+// chart does not use. Lists are not split into their items, as helm replaces
+// lists instead of merging them.
+func (d *Document) SetUserValues(values map[string]any) []paths.Path {
+	var documented []paths.Path
+	for i := range d.Sections {
+		for j := range d.Sections[i].Properties {
+			property := &d.Sections[i].Properties[j]
+			documented = append(documented, property.Path)
+
+			value, ok := LookupValue(values, property.Path)
+			if !ok {
+				continue
+			}
+
+			property.UserValue = &UserValue{
+				Value:   formatValue(value),
+				Changed: !equalsDefault(value, property.Default),
+			}
+		}
+	}
+
+	var undocumented []paths.Path
+	var walk func(path paths.Path, value any)
+	walk = func(path paths.Path, value any) {
+		for _, property := range documented {
+			if property.IsSubPathOf(path) {
+				return
+			}
+		}
+
+		m, ok := value.(map[string]any)
+		if !ok || len(m) == 0 {
+			// Lists are documented by their items
+			for _, property := range documented {
+				if path.IsSubPathOf(property) {
+					return
+				}
+			}
+
+			undocumented = append(undocumented, path)
+			return
+		}
+
+		for _, key := range sortedKeys(m) {
+			walk(path.WithProperty(key), m[key])
+		}
+	}
+	for _, key := range sortedKeys(values) {
+		walk(paths.Path{}.WithProperty(key), values[key])
+	}
+
+	return undocumented
+}
+
+// LookupValue returns the value at the path in the values, eg. the values
+// of a user values file read using LoadUserValues.
+func LookupValue(values any, path paths.Path) (any, bool) {
+	value := values
+	for _, component := range path {
+		segment := paths.SegmentString(component)
+
+		if paths.IsArrayPathComponent(component) {
+			list, ok := value.([]any)
+			if !ok {
+				return nil, false
+			}
+
+			idx, err := strconv.Atoi(strings.Trim(segment, "[]"))
+			if err != nil || idx < 0 || idx >= len(list) {
+				return nil, false
+			}
+
+			value = list[idx]
+			continue
+		}
+
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = m[segment]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// formatValue formats the value as YAML in the same way as the defaults.
+func formatValue(value any) string {
+	var sb strings.Builder
+	encoder := yaml.NewEncoder(&sb)
+	encoder.SetIndent(2)
+	encoder.Encode(value)
+	return strings.TrimSpace(sb.String())
+}
+
+// equalsDefault returns whether the value equals the default of a property,
+// defaults set using +docs:default that are not YAML are compared as text.
+func equalsDefault(value any, defaultValue string) bool {
+	var parsed any
+	if err := yaml.Unmarshal([]byte(defaultValue), &parsed); err != nil {
+		return formatValue(value) == defaultValue
+	}
+
+	return reflect.DeepEqual(value, parsed)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetUserValues(t *testing.T) {
+	values := `image:
+  # +docs:default=latest
+  tag: ""
+  pullPolicy: IfNotPresent
+args:
+  - --v=2
+replicas: 1
+# +docs:property
+resources: {}
+`
+
+	document, err := Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	undocumented := document.SetUserValues(map[string]any{
+		"image": map[string]any{
+			"tag":        "latest",
+			"pullPolicy": "Always",
+			"typo":       true,
+		},
+		"args":      []any{"--v=4"},
+		"replicas":  1,
+		"resources": map[string]any{"limits": map[string]any{"cpu": "1"}},
+	})
+
+	userValues := map[string]*UserValue{}
+	for _, property := range document.Sections[0].Properties {
+		userValues[property.Path.String()] = property.UserValue
+	}
+
+	require.Equal(t, map[string]*UserValue{
+		"image.tag":        {Value: "latest", Changed: false},
+		"image.pullPolicy": {Value: "Always", Changed: true},
+		"args[0]":          {Value: "--v=4", Changed: true},
+		"replicas":         {Value: "1", Changed: false},
+		"resources":        {Value: "limits:\n  cpu: \"1\"", Changed: true},
+	}, userValues)
+
+	require.Equal(t, []paths.Path{mustParsePath(t, "image.typo")}, undocumented)
+}
+
+func mustParsePath(t *testing.T, path string) paths.Path {
+	parsed, err := paths.Parse(path)
+	require.NoError(t, err)
+	return parsed
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// yamlAnchors tracks the anchors and aliases of a values file, so properties
+// whose value is an alias (eg. "tolerations: *defaultTolerations") can refer
+// to the value defining the anchor instead of repeating its default.
+type yamlAnchors struct {
+	// definitions are the paths of the nodes that define an anchor.
+	definitions map[*yaml.Node]paths.Path
+	// aliases maps the paths of the aliases to the paths of their anchors.
+	aliases map[string]paths.Path
+}
+
+func newYAMLAnchors() *yamlAnchors {
+	return &yamlAnchors{
+		definitions: map[*yaml.Node]paths.Path{},
+		aliases:     map[string]paths.Path{},
+	}
+}
+
+// visit records the anchor or alias of a node, the walk visits the node of
+// an anchor again below every alias so only the first path is kept.
+func (a *yamlAnchors) visit(node Node) {
+	if node.RawNode.Anchor != "" {
+		if _, ok := a.definitions[node.RawNode]; !ok {
+			a.definitions[node.RawNode] = node.Path
+		}
+	}
+
+	if node.RawNode.Kind == yaml.AliasNode {
+		if path, ok := a.definitions[node.RawNode.Alias]; ok {
+			a.aliases[node.Path.String()] = path
+		}
+	}
+}
+
+// sameAs returns the path of the anchor the value at path is an alias of,
+// or an empty string if it is not an alias.
+func (a *yamlAnchors) sameAs(path paths.Path) string {
+	if definition, ok := a.aliases[path.String()]; ok {
+		return definition.String()
+	}
+
+	return ""
+}
+
+// removeUndocumentedSameAs clears the SameAs of properties whose anchor is
+// not defined by a documented property, as there is nothing to refer to.
+func (d *Document) removeUndocumentedSameAs() {
+	documented := map[string]bool{}
+	for _, section := range d.Sections {
+		for _, property := range section.Properties {
+			documented[property.Path.String()] = true
+		}
+	}
+
+	for i := range d.Sections {
+		for j := range d.Sections[i].Properties {
+			property := &d.Sections[i].Properties[j]
+			if !documented[property.SameAs] {
+				property.SameAs = ""
+			}
+		}
+	}
+}
@@ -17,22 +17,34 @@ limitations under the License.
 package paths
 
 import (
-	"bytes"
 	"fmt"
 	"io"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+var nonAnchorCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+var jqIdentifierExp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 type pathComponent interface {
 	Append(idx int, path io.Writer)
 }
 
 type mapPathComponent string
 
+// keyQuoter escapes the characters that have a special meaning within a
+// quoted key, eg. `["say \"hi\""]`.
+var keyQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// setEscaper escapes the characters that have a special meaning in the keys
+// of helm's --set flag.
+var setEscaper = strings.NewReplacer(`\`, `\\`, `.`, `\.`, `,`, `\,`, `=`, `\=`, `[`, `\[`)
+
 func (s mapPathComponent) Append(idx int, w io.Writer) {
-	if strings.Contains(string(s), ".") {
-		fmt.Fprintf(w, "[%q]", s)
+	if needsQuoting(string(s)) {
+		fmt.Fprintf(w, `["%s"]`, keyQuoter.Replace(string(s)))
 	} else if idx == 0 {
 		fmt.Fprintf(w, "%s", s)
 	} else {
@@ -40,6 +52,12 @@ func (s mapPathComponent) Append(idx int, w io.Writer) {
 	}
 }
 
+// needsQuoting returns whether the key can only be written as a quoted key,
+// eg. "linkerd.io/inject" or "config.yaml", so the path stays unambiguous.
+func needsQuoting(key string) bool {
+	return key == "" || strings.ContainsAny(key, `.[]"\`)
+}
+
 type arrayPathComponent int
 
 func (i arrayPathComponent) Append(idx int, w io.Writer) {
@@ -51,7 +69,13 @@ func IsArrayPathComponent(pc pathComponent) bool {
 	return ok
 }
 
+// SegmentString returns the key of a map path component, eg. "linkerd.io/inject"
+// (without quotes), or the index of an array path component, eg. "[0]".
 func SegmentString(pc pathComponent) string {
+	if key, ok := pc.(mapPathComponent); ok {
+		return string(key)
+	}
+
 	sb := strings.Builder{}
 	pc.Append(0, &sb)
 	return sb.String()
@@ -59,52 +83,127 @@ func SegmentString(pc pathComponent) string {
 
 type Path []pathComponent
 
+// Parse parses a path, eg. `controller.extraArgs[0]`. Keys containing dots or
+// other special characters are written as quoted keys (eg.
+// `podAnnotations["linkerd.io/inject"]`) or escaped with a backslash like in
+// helm's --set flag (eg. `podAnnotations.linkerd\.io/inject`).
 func Parse(pathString string) (Path, error) {
-	scanner := bytes.NewBufferString(pathString)
-
+	runes := []rune(pathString)
 	path := Path{}
 
-	for {
-		switch k, last, err := runesUntil(scanner, runeSet([]rune{'[', '.'})); {
-		case err == io.EOF && len(k) > 0:
-			return append(path, mapPathComponent(k)), nil
-		case err == io.EOF && len(k) == 0:
-			return path, nil
-		case err != nil:
+	for i := 0; i < len(runes); {
+		if runes[i] != '[' {
+			key, n, err := parseKey(runes[i:])
+			if err != nil {
+				return path, err
+			}
+
+			path = append(path, mapPathComponent(key))
+			i += n
+			if i < len(runes) && runes[i] == '.' {
+				i++
+			}
+			continue
+		}
+
+		component, n, err := parseBrackets(runes[i:])
+		if err != nil {
 			return path, err
-		case last == '[':
-			path = append(path, mapPathComponent(k))
+		}
 
-			betweenBrackets, _, err := runesUntil(scanner, runeSet([]rune{']'}))
-			if err != nil {
-				return path, fmt.Errorf("unexpected end of path")
+		path = append(path, component)
+		i += n
+
+		// Brackets are followed by the end of the path, a key or more brackets
+		if i < len(runes) {
+			switch runes[i] {
+			case '.':
+				i++
+			case '[':
+			default:
+				return path, fmt.Errorf("unexpected token %q", runes[i])
 			}
+		}
+	}
 
-			if len(betweenBrackets) == 0 {
-				return path, fmt.Errorf("unexpected empty array index")
+	return path, nil
+}
+
+// parseKey parses an unquoted key at the start of runes, up to the next "."
+// or "[". It returns the key and the number of runes it was written with.
+func parseKey(runes []rune) (string, int, error) {
+	var key []rune
+
+	i := 0
+	for ; i < len(runes) && runes[i] != '.' && runes[i] != '['; i++ {
+		if runes[i] == '\\' {
+			i++
+			if i == len(runes) {
+				return "", i, fmt.Errorf("unexpected end of path")
 			}
+		}
+
+		key = append(key, runes[i])
+	}
+
+	if i == 0 {
+		return "", i, fmt.Errorf("unexpected empty key")
+	}
+
+	return string(key), i, nil
+}
 
-			if betweenBrackets[0] == '"' && betweenBrackets[len(betweenBrackets)-1] == '"' {
-				// betweenBrackets is a string
-				path = append(path, mapPathComponent(betweenBrackets[1:len(betweenBrackets)-1]))
-			} else {
-				idx, err := strconv.Atoi(string(betweenBrackets))
-				if err != nil {
-					return path, fmt.Errorf("unexpected array index: %q", betweenBrackets)
+// parseBrackets parses an array index (eg. "[0]") or a quoted key (eg.
+// `["linkerd.io/inject"]`) at the start of runes. It returns the component
+// and the number of runes it was written with.
+func parseBrackets(runes []rune) (pathComponent, int, error) {
+	if len(runes) > 1 && runes[1] == '"' {
+		var key []rune
+
+		for i := 2; i < len(runes); i++ {
+			switch runes[i] {
+			case '\\':
+				i++
+				if i < len(runes) {
+					key = append(key, runes[i])
+				}
+			case '"':
+				if i+1 >= len(runes) || runes[i+1] != ']' {
+					return nil, i, fmt.Errorf("expected ] after quoted key %q", string(key))
 				}
 
-				path = append(path, arrayPathComponent(idx))
+				return mapPathComponent(key), i + 2, nil
+			default:
+				key = append(key, runes[i])
 			}
+		}
 
-			if r, _, e := scanner.ReadRune(); e == nil && r != '.' {
-				return path, fmt.Errorf("unexpected token %q", r)
-			}
-		case last == '.':
-			path = append(path, mapPathComponent(k))
-		default:
-			return nil, fmt.Errorf("parse error: unexpected token %v", last)
+		return nil, len(runes), fmt.Errorf("unexpected end of path")
+	}
+
+	end := -1
+	for i, r := range runes {
+		if r == ']' {
+			end = i
+			break
 		}
 	}
+
+	if end == -1 {
+		return nil, len(runes), fmt.Errorf("unexpected end of path")
+	}
+
+	index := string(runes[1:end])
+	if len(index) == 0 {
+		return nil, end + 1, fmt.Errorf("unexpected empty array index")
+	}
+
+	idx, err := strconv.Atoi(index)
+	if err != nil {
+		return nil, end + 1, fmt.Errorf("unexpected array index: %q", index)
+	}
+
+	return arrayPathComponent(idx), end + 1, nil
 }
 
 func (p Path) WithProperty(part string) Path {
@@ -188,35 +287,74 @@ func (p Path) PatternString() string {
 	return sb.String()
 }
 
-func runeSet(r []rune) map[rune]bool {
-	s := make(map[rune]bool, len(r))
-	for _, rr := range r {
-		s[rr] = true
+// SetString returns the path in the syntax of helm's --set flag, eg.
+// `podAnnotations.linkerd\.io/inject` for `podAnnotations["linkerd.io/inject"]`.
+// The characters that have a special meaning in --set keys are escaped with a
+// backslash.
+func (p Path) SetString() string {
+	sb := strings.Builder{}
+	for i, part := range p {
+		switch part := part.(type) {
+		case mapPathComponent:
+			if i > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(setEscaper.Replace(string(part)))
+		case arrayPathComponent:
+			fmt.Fprintf(&sb, "[%d]", part)
+		}
 	}
-	return s
+	return sb.String()
 }
 
-func runesUntil(in io.RuneReader, stop map[rune]bool) ([]rune, rune, error) {
-	v := []rune{}
-	for {
-		switch r, _, e := in.ReadRune(); {
-		case e != nil:
-			return v, r, e
-		case inMap(r, stop):
-			return v, r, nil
-		case r == '\\':
-			next, _, e := in.ReadRune()
-			if e != nil {
-				return v, next, e
-			}
-			v = append(v, next)
-		default:
-			v = append(v, r)
+// Anchor returns an identifier for the path that can be used as an HTML
+// anchor, eg. "controller-replicacount" for "controller.replicaCount".
+func (p Path) Anchor() string {
+	anchor := nonAnchorCharacters.ReplaceAllString(strings.ToLower(p.String()), "-")
+	return strings.Trim(anchor, "-")
+}
+
+// JSONPointer returns the path as an RFC 6901 JSON pointer, eg.
+// "/podAnnotations/linkerd.io~1inject".
+func (p Path) JSONPointer() string {
+	sb := strings.Builder{}
+	for _, part := range p {
+		sb.WriteString("/")
+		switch part := part.(type) {
+		case mapPathComponent:
+			sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(string(part)))
+		case arrayPathComponent:
+			sb.WriteString(strconv.Itoa(int(part)))
 		}
 	}
+	return sb.String()
 }
 
-func inMap(r rune, m map[rune]bool) bool {
-	_, ok := m[r]
-	return ok
+// JQString returns the path as a jq (and yq) expression, eg.
+// `.podAnnotations["linkerd.io/inject"]`.
+func (p Path) JQString() string {
+	if len(p) == 0 {
+		return "."
+	}
+
+	sb := strings.Builder{}
+	for i, part := range p {
+		switch part := part.(type) {
+		case mapPathComponent:
+			if jqIdentifierExp.MatchString(string(part)) {
+				fmt.Fprintf(&sb, ".%s", part)
+				continue
+			}
+			if i == 0 {
+				sb.WriteString(".")
+			}
+			fmt.Fprintf(&sb, "[%q]", part)
+		case arrayPathComponent:
+			if i == 0 {
+				sb.WriteString(".")
+			}
+			fmt.Fprintf(&sb, "[%d]", part)
+		}
+	}
+	return sb.String()
 }
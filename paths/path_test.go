@@ -52,6 +52,54 @@ func TestParsePath(t *testing.T) {
 			expected: Path{mapPathComponent("foo"), mapPathComponent("bar"), arrayPathComponent(0), mapPathComponent("baz")},
 			wantErr:  false,
 		},
+		{
+			name:     "Quoted key",
+			path:     `podAnnotations["linkerd.io/inject"].value`,
+			expected: Path{mapPathComponent("podAnnotations"), mapPathComponent("linkerd.io/inject"), mapPathComponent("value")},
+			wantErr:  false,
+		},
+		{
+			name:     "Quoted key at the start",
+			path:     `["config.yaml"].data`,
+			expected: Path{mapPathComponent("config.yaml"), mapPathComponent("data")},
+			wantErr:  false,
+		},
+		{
+			name:     "Quoted key with special characters",
+			path:     `a["b]\"c\\"]`,
+			expected: Path{mapPathComponent("a"), mapPathComponent(`b]"c\`)},
+			wantErr:  false,
+		},
+		{
+			name:     "Consecutive brackets",
+			path:     `matrix[0][1]["x.y"]`,
+			expected: Path{mapPathComponent("matrix"), arrayPathComponent(0), arrayPathComponent(1), mapPathComponent("x.y")},
+			wantErr:  false,
+		},
+		{
+			name:     "Escaped dot",
+			path:     `podAnnotations.linkerd\.io/inject`,
+			expected: Path{mapPathComponent("podAnnotations"), mapPathComponent("linkerd.io/inject")},
+			wantErr:  false,
+		},
+		{
+			name:     "Quoted empty key",
+			path:     `empty[""]`,
+			expected: Path{mapPathComponent("empty"), mapPathComponent("")},
+			wantErr:  false,
+		},
+		{
+			name:     "Empty key",
+			path:     "foo..bar",
+			expected: Path{mapPathComponent("foo")},
+			wantErr:  true,
+		},
+		{
+			name:     "Unterminated quoted key",
+			path:     `foo["bar`,
+			expected: Path{mapPathComponent("foo")},
+			wantErr:  true,
+		},
 		{
 			name:     "Invalid path 2",
 			path:     "foo[0]aa",
@@ -103,3 +151,118 @@ func TestWithIndex(t *testing.T) {
 		t.Errorf("path2.String() = %v, expected %v", path2.String(), "foo.bar.aaaa[1]")
 	}
 }
+
+func TestAnchor(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "replicaCount", expected: "replicacount"},
+		{path: "controller.image.pullPolicy", expected: "controller-image-pullpolicy"},
+		{path: "extraArgs[0]", expected: "extraargs-0"},
+		{path: `podAnnotations["linkerd.io/inject"]`, expected: "podannotations-linkerd-io-inject"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := Parse(tt.path)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got := path.Anchor(); got != tt.expected {
+				t.Errorf("Anchor() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSONPointerAndJQString(t *testing.T) {
+	tests := []struct {
+		path        string
+		jsonPointer string
+		jq          string
+	}{
+		{path: "replicaCount", jsonPointer: "/replicaCount", jq: ".replicaCount"},
+		{path: "extraArgs[0]", jsonPointer: "/extraArgs/0", jq: ".extraArgs[0]"},
+		{path: `podAnnotations["linkerd.io/inject"]`, jsonPointer: "/podAnnotations/linkerd.io~1inject", jq: `.podAnnotations["linkerd.io/inject"]`},
+		{path: "webhook.timeout-seconds", jsonPointer: "/webhook/timeout-seconds", jq: `.webhook["timeout-seconds"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := Parse(tt.path)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got := path.JSONPointer(); got != tt.jsonPointer {
+				t.Errorf("JSONPointer() = %q, expected %q", got, tt.jsonPointer)
+			}
+
+			if got := path.JQString(); got != tt.jq {
+				t.Errorf("JQString() = %q, expected %q", got, tt.jq)
+			}
+		})
+	}
+}
+
+func TestSpecialKeys(t *testing.T) {
+	tests := []struct {
+		path      Path
+		str       string
+		setString string
+	}{
+		{
+			path:      Path{}.WithProperty("podAnnotations").WithProperty("linkerd.io/inject"),
+			str:       `podAnnotations["linkerd.io/inject"]`,
+			setString: `podAnnotations.linkerd\.io/inject`,
+		},
+		{
+			path:      Path{}.WithProperty("config.yaml").WithProperty("data"),
+			str:       `["config.yaml"].data`,
+			setString: `config\.yaml.data`,
+		},
+		{
+			path:      Path{}.WithProperty("args").WithIndex(0).WithProperty(`a=b,c[d]`),
+			str:       `args[0]["a=b,c[d]"]`,
+			setString: `args[0].a\=b\,c\[d]`,
+		},
+		{
+			path:      Path{}.WithProperty(`quote"and\backslash`),
+			str:       `["quote\"and\\backslash"]`,
+			setString: `quote"and\\backslash`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if got := tt.path.String(); got != tt.str {
+				t.Errorf("String() = %q, expected %q", got, tt.str)
+			}
+
+			if got := tt.path.SetString(); got != tt.setString {
+				t.Errorf("SetString() = %q, expected %q", got, tt.setString)
+			}
+
+			// Segments are the raw keys, eg. for the names of schema properties
+			if key, ok := tt.path.Property().(mapPathComponent); ok {
+				if got := SegmentString(tt.path.Property()); got != string(key) {
+					t.Errorf("SegmentString() = %q, expected %q", got, key)
+				}
+			}
+
+			// Both forms parse back to the same path
+			for _, s := range []string{tt.str, tt.setString} {
+				parsed, err := Parse(s)
+				if err != nil {
+					t.Fatalf("Parse(%q) error = %v", s, err)
+				}
+
+				if !parsed.Equal(tt.path) {
+					t.Errorf("Parse(%q) = %v, expected %v", s, parsed, tt.path)
+				}
+			}
+		})
+	}
+}
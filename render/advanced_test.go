@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderAdvancedAppendix(t *testing.T) {
+	values := `# Replicas
+replicaCount: 1
+# Client QPS
+# +docs:advanced
+qps: 5
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("markdown-table", document, Options{})
+	require.NoError(t, err)
+	require.NotContains(t, output, "<details>")
+
+	output, err = RenderWithOptions("markdown-table", document, Options{AdvancedAppendix: true})
+	require.NoError(t, err)
+	require.Less(t, strings.Index(output, "replicacount"), strings.Index(output, "<details>\n<summary>Advanced</summary>\n\n<table>"))
+	require.Less(t, strings.Index(output, "<details>"), strings.Index(output, "qps"))
+	require.True(t, strings.HasSuffix(output, "</table>\n\n\n</details>"), output)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatArtifactHub is the format that renders the artifacthub.io/changes
+// annotation of Chart.yaml describing the deprecated and renamed values, see
+// https://artifacthub.io/docs/topics/annotations/helm/.
+const FormatArtifactHub = "artifacthub"
+
+// ArtifactHubChange is an entry of the artifacthub.io/changes annotation.
+type ArtifactHubChange struct {
+	// Kind is one of added, changed, deprecated, removed, fixed or security.
+	Kind        string `yaml:"kind"`
+	Description string `yaml:"description"`
+}
+
+// ArtifactHubChanges returns the changes to the values that are documented
+// by the properties themselves: the values deprecated using +docs:deprecated
+// tags and the values renamed using +docs:alias tags.
+func ArtifactHubChanges(document *parser.Document) []ArtifactHubChange {
+	changes := []ArtifactHubChange{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			for _, alias := range property.Aliases() {
+				changes = append(changes, ArtifactHubChange{Kind: "changed", Description: fmt.Sprintf("Renamed the `%s` value to `%s`", alias, property.Path)})
+			}
+
+			if property.Deprecated() {
+				description := fmt.Sprintf("Deprecated the `%s` value", property.Path)
+				if message := strings.Join(strings.Fields(property.DeprecationMessage()), " "); message != "" {
+					description += ": " + message
+				}
+				changes = append(changes, ArtifactHubChange{Kind: "deprecated", Description: description})
+			}
+		}
+	}
+
+	return changes
+}
+
+// MarshalArtifactHub returns the annotations block of Chart.yaml with the
+// artifacthub.io/changes annotation of the document, ready to paste into
+// Chart.yaml. The changes are a YAML list in a string like Artifact Hub
+// expects.
+func MarshalArtifactHub(document *parser.Document) ([]byte, error) {
+	list, err := marshalChartYAML(ArtifactHubChanges(document))
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := marshalChartYAML(map[string]map[string]string{
+		"annotations": {"artifacthub.io/changes": list},
+	})
+	return []byte(annotations), err
+}
+
+// marshalChartYAML encodes the value using the 2 space indentation of
+// Chart.yaml files.
+func marshalChartYAML(value any) (string, error) {
+	var sb strings.Builder
+	encoder := yaml.NewEncoder(&sb)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalArtifactHub(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(`# Number of replicas
+replicaCount: 2
+
+# Timeout of the webhook
+# +docs:alias=timeoutSeconds
+timeout: 10
+
+# New value
+labels: {}
+
+# Old name of the labels
+# +docs:deprecated=Use labels instead.
+podLabels: {}
+`), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("", document, Options{Format: FormatArtifactHub})
+	require.NoError(t, err)
+	require.Equal(t, `annotations:
+  artifacthub.io/changes: |
+    - kind: changed
+      description: Renamed the `+"`timeoutSeconds` value to `timeout`"+`
+    - kind: deprecated
+      description: 'Deprecated the `+"`podLabels`"+` value: Use labels instead.'
+`, output)
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import "strings"
+
+// asciidocCell escapes the cell separator of AsciiDoc tables, so the value
+// can be used in a table cell.
+func asciidocCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+// asciidocText converts a text comment to AsciiDoc, which joins the lines of
+// a paragraph unless they end in a hard line break (" +"). Blank lines still
+// separate paragraphs. The text is escaped for use in a table cell.
+func asciidocText(text string) string {
+	lines := strings.Split(asciidocCell(text), "\n")
+	for i := range lines[:len(lines)-1] {
+		if strings.TrimSpace(lines[i]) != "" && strings.TrimSpace(lines[i+1]) != "" {
+			lines[i] += " +"
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
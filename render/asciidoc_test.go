@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsciidocText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "", expected: ""},
+		{text: "The image tag", expected: "The image tag"},
+		{text: "line 1\nline 2\n\nparagraph 2", expected: "line 1 +\nline 2\n\nparagraph 2"},
+		{text: "a | b", expected: `a \| b`},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, asciidocText(test.text), test.text)
+	}
+}
+
+func TestRenderAsciidoc(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image tag, eg. ` + "`v1|v2`" + `
+# +docs:see=pullPolicy
+tag: v1
+# The pull policy
+pullPolicy: IfNotPresent
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("asciidoc", document)
+	require.NoError(t, err)
+
+	require.Contains(t, output, "== Image\n")
+	require.Contains(t, output, "[cols=\"2,5,1,3\",options=\"header\"]\n|===\n|Property |Description |Type |Default\n")
+	require.Contains(t, output, "|[[tag]]`tag`\na|")
+	require.Contains(t, output, "The image tag, eg. `v1\\|v2`")
+	require.Contains(t, output, "See also: <<pullpolicy,`pullPolicy`>>")
+	require.Contains(t, output, "|string\na|\n[source,yaml]\n----\nv1\n----\n")
+	require.Equal(t, 2, strings.Count(output, "|===\n"))
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderBitnami(t *testing.T) {
+	values := `# The number of replicas
+replicaCount: 1
+
+# +docs:section=Image parameters
+
+# The image tag,
+# eg. v1|v2
+tag: ""
+# The image pull secrets
+pullSecrets: []
+# Pod annotations
+# +docs:property
+podAnnotations:
+  linkerd.io/inject: enabled
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("bitnami", document)
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(output, "\n\n### Parameters\n\n| Name | Description | Value |\n| ---- | ----------- | ----- |\n| `replicaCount` | The number of replicas | `1` |\n"), output)
+	require.Contains(t, output, "\n### Image parameters\n\n| Name | Description | Value |\n")
+	require.Contains(t, output, "| `tag` | The image tag, eg. v1\\|v2 | `\"\"` |\n")
+	require.Contains(t, output, "| `pullSecrets` | The image pull secrets | `[]` |\n")
+	require.Contains(t, output, "| `podAnnotations` | Pod annotations | `{\"linkerd.io/inject\":\"enabled\"}` |")
+}
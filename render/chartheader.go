@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// The markers between which the chart header is injected, these are placed
+// above the parameters section of the README.
+const (
+	ChartHeaderStart = "<!-- helm-tool:chart-header:start -->"
+	ChartHeaderEnd   = "<!-- helm-tool:chart-header:end -->"
+)
+
+var (
+	// ChartHeaderStartMatch matches the ChartHeaderStart marker, it is the
+	// header regex to inject the chart header with.
+	ChartHeaderStartMatch = regexp.MustCompile(`^` + regexp.QuoteMeta(ChartHeaderStart) + `\s*$`)
+	// ChartHeaderEndMatch matches the ChartHeaderEnd marker, it is the
+	// footer regex to inject the chart header with.
+	ChartHeaderEndMatch = regexp.MustCompile(`^` + regexp.QuoteMeta(ChartHeaderEnd) + `\s*$`)
+)
+
+// RenderChartHeader renders the header of the README of a chart from its
+// metadata: the name, badges of the version and app version, the
+// description and, if the URL of the chart repository is set, a snippet
+// installing the chart. Repositories starting with "oci://" are registries
+// the chart is installed from directly.
+func RenderChartHeader(chart *parser.Chart, repo string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", chart.Name)
+
+	var badges []string
+	if chart.Version != "" {
+		badges = append(badges, badge("Version", chart.Version))
+	}
+	if chart.AppVersion != "" {
+		badges = append(badges, badge("AppVersion", chart.AppVersion))
+	}
+	if len(badges) > 0 {
+		fmt.Fprintf(&sb, "\n%s\n", strings.Join(badges, " "))
+	}
+
+	if chart.Description != "" {
+		fmt.Fprintf(&sb, "\n%s\n", chart.Description)
+	}
+
+	if repo != "" {
+		sb.WriteString("\n## Installing\n\n```bash\n")
+		if strings.HasPrefix(repo, "oci://") {
+			fmt.Fprintf(&sb, "helm install %s %s/%s", chart.Name, strings.TrimSuffix(repo, "/"), chart.Name)
+		} else {
+			fmt.Fprintf(&sb, "helm repo add %s %s\n", chart.Name, repo)
+			fmt.Fprintf(&sb, "helm install %s %s/%s", chart.Name, chart.Name, chart.Name)
+		}
+		if chart.Version != "" {
+			fmt.Fprintf(&sb, " --version %s", chart.Version)
+		}
+		sb.WriteString("\n```\n")
+	}
+
+	return "\n" + strings.TrimSuffix(sb.String(), "\n")
+}
+
+// badge returns the Markdown image of a shields.io badge, in which dashes
+// and underscores are escaped by doubling them.
+func badge(label string, value string) string {
+	escape := strings.NewReplacer("-", "--", "_", "__")
+	return fmt.Sprintf("![%s: %s](https://img.shields.io/badge/%s-%s-informational?style=flat-square)", label, value, url.PathEscape(escape.Replace(label)), url.PathEscape(escape.Replace(value)))
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderChartHeader(t *testing.T) {
+	chart := &parser.Chart{
+		Name:        "cert-manager",
+		Version:     "v1.14.0-rc_1",
+		AppVersion:  "v1.14.0",
+		Description: "A cloud native certificate manager",
+	}
+
+	require.Equal(t, `
+# cert-manager
+
+![Version: v1.14.0-rc_1](https://img.shields.io/badge/Version-v1.14.0--rc__1-informational?style=flat-square) ![AppVersion: v1.14.0](https://img.shields.io/badge/AppVersion-v1.14.0-informational?style=flat-square)
+
+A cloud native certificate manager
+
+## Installing
+
+`+"```bash"+`
+helm repo add cert-manager https://charts.jetstack.io
+helm install cert-manager cert-manager/cert-manager --version v1.14.0-rc_1
+`+"```", RenderChartHeader(chart, "https://charts.jetstack.io"))
+
+	require.Contains(t, RenderChartHeader(chart, "oci://quay.io/jetstack/charts/"), "\nhelm install cert-manager oci://quay.io/jetstack/charts/cert-manager --version v1.14.0-rc_1\n")
+	require.Equal(t, "\n# example", RenderChartHeader(&parser.Chart{Name: "example"}, ""))
+}
+
+func TestInjectChartHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	headerMatch := regexp.MustCompile(`(?m)^##\s+Parameters *$`)
+	require.NoError(t, CreateChartHeaderTarget(path, headerMatch))
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, InjectRendered(path, RenderChartHeader(&parser.Chart{Name: "example", Description: "An example"}, ""), ChartHeaderStartMatch, ChartHeaderEndMatch, nil))
+		require.NoError(t, InjectRendered(path, "\nparameters", headerMatch, regexp.MustCompile(`(?m)^##?\s+.*$`), nil))
+	}
+
+	result, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, ChartHeaderStart+"\n# example\n\nAn example\n"+ChartHeaderEnd+"\n\n"+DefaultHeader+"\nparameters\n", string(result))
+}
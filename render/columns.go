@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TableColumns are the columns of the markdown-table template that can be
+// selected using Options.Columns, the property column is always rendered.
+var TableColumns = []string{"description", "type", "default"}
+
+// column returns whether the named column of a table is rendered, all the
+// columns are rendered if no columns are selected.
+func (o Options) column(name string) bool {
+	return len(o.Columns) == 0 || slices.Contains(o.Columns, name)
+}
+
+func (o Options) validateColumns() error {
+	for _, column := range o.Columns {
+		if !slices.Contains(TableColumns, column) {
+			return fmt.Errorf("unknown column %q, must be one of %s", column, strings.Join(TableColumns, ", "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// confluenceText converts a text comment to paragraphs in the Confluence
+// storage format, which is XHTML.
+func confluenceText(text string) string {
+	return strings.ReplaceAll(htmlText(text), "<br>", "<br />")
+}
+
+// confluenceCode returns a Confluence code macro showing the code, the code
+// is written as CDATA so it does not need escaping.
+func confluenceCode(language string, code string) string {
+	return fmt.Sprintf(`<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">%s</ac:parameter><ac:plain-text-body>%s</ac:plain-text-body></ac:structured-macro>`,
+		html.EscapeString(language),
+		cdata(code),
+	)
+}
+
+// confluenceAnchor returns a Confluence anchor macro, which can be linked to
+// using "#<anchor>".
+func confluenceAnchor(anchor string) string {
+	return fmt.Sprintf(`<ac:structured-macro ac:name="anchor"><ac:parameter ac:name="">%s</ac:parameter></ac:structured-macro>`, html.EscapeString(anchor))
+}
+
+// confluenceLink returns a link to an anchor on the same page, Confluence
+// prefixes anchors with the page title so a plain link would not work.
+func confluenceLink(anchor string, text string) string {
+	return fmt.Sprintf(`<ac:link ac:anchor="%s"><ac:plain-text-link-body>%s</ac:plain-text-link-body></ac:link>`,
+		html.EscapeString(anchor),
+		cdata(text),
+	)
+}
+
+// cdata returns text as a CDATA section, a CDATA section cannot contain "]]>"
+// so it is split over two sections.
+func cdata(text string) string {
+	return "<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderConfluence(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image <tag>
+# +docs:see=digest
+tag: v1
+# The image digest
+digest: "]]>"
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("confluence", document)
+	require.NoError(t, err)
+
+	require.Contains(t, output, "<h2>Image</h2>\n")
+	require.Contains(t, output, `<td><ac:structured-macro ac:name="anchor"><ac:parameter ac:name="">tag</ac:parameter></ac:structured-macro><code>tag</code></td>`)
+	require.Contains(t, output, "<p>The image &lt;tag&gt;</p>\n")
+	require.Contains(t, output, `See also: <ac:link ac:anchor="digest"><ac:plain-text-link-body><![CDATA[digest]]></ac:plain-text-link-body></ac:link>`)
+	require.Contains(t, output, `<ac:plain-text-body><![CDATA[v1]]></ac:plain-text-body>`)
+	require.Contains(t, output, `<ac:plain-text-body><![CDATA[']]]]><![CDATA[>']]></ac:plain-text-body>`)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// FormatCSV is the format that renders a CSV table with a row per property,
+// eg. to import the values of a chart into a spreadsheet.
+const FormatCSV = "csv"
+
+// csvHeader are the columns of the CSV format.
+var csvHeader = []string{"path", "type", "default", "description", "section"}
+
+// MarshalCSV returns the properties of the document as CSV, with a header row
+// followed by a row per property. Multi-line defaults and descriptions are
+// kept, as they are quoted.
+func MarshalCSV(document *parser.Document) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if err := writer.Write([]string{
+				property.Path.String(),
+				property.Type.String(),
+				property.Default,
+				property.Description.String(),
+				section.Name,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
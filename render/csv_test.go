@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCSV(t *testing.T) {
+	values := `# The number of replicas
+replicas: 1
+
+# +docs:section=Image
+
+# The image tag, eg. "v1"
+# +docs:type=string
+tag:
+# The image pull secrets
+# +docs:property
+pullSecrets:
+  - name: secret
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("markdown-plain", document, Options{Format: FormatCSV})
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"path", "type", "default", "description", "section"},
+		{"replicas", "number", "1", "The number of replicas", ""},
+		{"tag", "string", "null", `The image tag, eg. "v1"`, "Image"},
+		{"pullSecrets", "array", "- name: secret", "The image pull secrets", "Image"},
+	}, records)
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDefaultFrom(t *testing.T) {
+	values := `# The image tag
+# +docs:default-from=.Chart.AppVersion
+tag: ""
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("markdown-table", document)
+	require.NoError(t, err)
+	require.Contains(t, output, "<td>\n\nComputed: `.Chart.AppVersion`\n\n</td>")
+	require.NotContains(t, output, "```yaml\n\"\"\n```")
+
+	output, err = Render("markdown-plain", document)
+	require.NoError(t, err)
+	require.Contains(t, output, "> Computed default: `.Chart.AppVersion`\n")
+
+	output, err = RenderWithOptions("", document, Options{Format: FormatJSON})
+	require.NoError(t, err)
+	require.Contains(t, output, `"defaultFrom": ".Chart.AppVersion"`)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// The styles in which array indices in property paths are displayed.
+const (
+	// ArrayIndexNumber displays the index, eg. "args[0]".
+	ArrayIndexNumber = "number"
+	// ArrayIndexEmpty displays empty brackets, eg. "args[]".
+	ArrayIndexEmpty = "empty"
+	// ArrayIndexWildcard displays a wildcard, eg. "args[*]".
+	ArrayIndexWildcard = "wildcard"
+)
+
+func (o Options) validateArrayIndex() error {
+	switch o.ArrayIndex {
+	case "", ArrayIndexNumber, ArrayIndexEmpty, ArrayIndexWildcard:
+		return nil
+	default:
+		return fmt.Errorf("unknown array index style %q, must be one of %s, %s or %s", o.ArrayIndex, ArrayIndexNumber, ArrayIndexEmpty, ArrayIndexWildcard)
+	}
+}
+
+// displayPath returns the path as it is displayed in the documentation, this
+// only differs from the path string in the style of the array indices.
+func (o Options) displayPath(path paths.Path) string {
+	var sb strings.Builder
+	for i := range path {
+		if index, ok := o.arrayIndex(path, i); ok {
+			sb.WriteString(index)
+			continue
+		}
+
+		path[i].Append(i, &sb)
+	}
+
+	return sb.String()
+}
+
+// displaySegment returns the i-th component of the path on its own, as it is
+// displayed in the documentation.
+func (o Options) displaySegment(path paths.Path, i int) string {
+	if index, ok := o.arrayIndex(path, i); ok {
+		return index
+	}
+
+	return paths.SegmentString(path[i])
+}
+
+// arrayIndex returns the replacement of the i-th component of the path if it
+// is an array index and indices are not displayed as numbers.
+func (o Options) arrayIndex(path paths.Path, i int) (string, bool) {
+	if !paths.IsArrayPathComponent(path[i]) {
+		return "", false
+	}
+
+	switch o.ArrayIndex {
+	case ArrayIndexEmpty:
+		return "[]", true
+	case ArrayIndexWildcard:
+		return "[*]", true
+	default:
+		return "", false
+	}
+}
+
+// displayName returns the display name of the property if it has one, or
+// otherwise its displayed path.
+func (o Options) displayName(property parser.Property) string {
+	if name := property.Name(); name != "" {
+		return name
+	}
+
+	return o.displayPath(property.Path)
+}
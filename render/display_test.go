@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayPath(t *testing.T) {
+	path, err := paths.Parse("webhook.volumes[0].items[12]")
+	require.NoError(t, err)
+	path = path.WithProperty("a.b")
+
+	require.Equal(t, `webhook.volumes[0].items[12]["a.b"]`, Options{}.displayPath(path))
+	require.Equal(t, `webhook.volumes[0].items[12]["a.b"]`, Options{ArrayIndex: ArrayIndexNumber}.displayPath(path))
+	require.Equal(t, `webhook.volumes[].items[]["a.b"]`, Options{ArrayIndex: ArrayIndexEmpty}.displayPath(path))
+	require.Equal(t, `webhook.volumes[*].items[*]["a.b"]`, Options{ArrayIndex: ArrayIndexWildcard}.displayPath(path))
+	require.Equal(t, "[*]", Options{ArrayIndex: ArrayIndexWildcard}.displaySegment(path, 2))
+	require.Equal(t, "items", Options{ArrayIndex: ArrayIndexWildcard}.displaySegment(path, 3))
+}
+
+func TestRenderArrayIndex(t *testing.T) {
+	path, err := paths.Parse("extraArgs[0]")
+	require.NoError(t, err)
+
+	document := &parser.Document{Sections: []parser.Section{{
+		Properties: []parser.Property{{Path: path, Type: parser.TypeString}},
+	}}}
+
+	for _, templateName := range []string{"markdown-plain", "markdown-table", "markdown-table-vertical"} {
+		output, err := RenderWithOptions(templateName, document, Options{ArrayIndex: ArrayIndexEmpty, PropertyAnchors: true})
+		require.NoError(t, err)
+		require.Contains(t, output, "extraArgs[]", templateName)
+		require.NotContains(t, output, "[0]", templateName)
+		require.Contains(t, output, `id="extraargs-0"`, templateName)
+	}
+
+	// The path is relative to the top-level object
+	output, err := RenderWithOptions("markdown-table-objects", document, Options{ArrayIndex: ArrayIndexEmpty})
+	require.NoError(t, err)
+	require.Contains(t, output, "#### extraArgs")
+	require.Contains(t, output, "[]</td>")
+
+	_, err = RenderWithOptions("markdown-plain", document, Options{ArrayIndex: "unknown"})
+	require.Error(t, err)
+}
+
+func TestRenderLastCommits(t *testing.T) {
+	path, err := paths.Parse("replicas")
+	require.NoError(t, err)
+
+	document := &parser.Document{Sections: []parser.Section{{
+		Properties: []parser.Property{{
+			Path: path,
+			Type: parser.TypeNumber,
+			LastCommit: &parser.Commit{
+				Hash:    "0123456789abcdef",
+				Date:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+				Summary: "Increase replicas",
+				URL:     "https://example.com/commit/0123456789abcdef",
+			},
+		}},
+	}}}
+
+	output, err := RenderWithOptions("markdown-table", document, Options{})
+	require.NoError(t, err)
+	require.NotContains(t, output, "Last changed")
+
+	output, err = RenderWithOptions("markdown-table", document, Options{LastCommits: true})
+	require.NoError(t, err)
+	require.Contains(t, output, "<th>Last changed</th>")
+	require.Contains(t, output, `<td><a href="https://example.com/commit/0123456789abcdef" title="Increase replicas">0123456</a> 2024-03-01</td>`)
+
+	jsonDocument := NewJSONDocument(document)
+	require.Equal(t, "0123456789abcdef", jsonDocument.Sections[0].Properties[0].LastCommit.Hash)
+}
+
+func TestRenderOwners(t *testing.T) {
+	path, err := paths.Parse("webhook.port")
+	require.NoError(t, err)
+
+	document := &parser.Document{Sections: []parser.Section{{
+		Properties: []parser.Property{{Path: path, Type: parser.TypeNumber}},
+	}}}
+
+	output, err := RenderWithOptions("markdown-table", document, Options{Tree: true})
+	require.NoError(t, err)
+	require.NotContains(t, output, "<th>Owner</th>")
+	require.Contains(t, output, `<td colspan="3"></td>`)
+
+	document.Sections[0].Properties[0].Owners = []string{"@org/a", "@org/b"}
+	output, err = RenderWithOptions("markdown-table", document, Options{Tree: true})
+	require.NoError(t, err)
+	require.Contains(t, output, "<th>Owner</th>")
+	require.Contains(t, output, "<td>@org/a, @org/b</td>")
+	require.Contains(t, output, `<td colspan="4"></td>`)
+}
+
+func TestRenderNameTitle(t *testing.T) {
+	values := `annotations:
+  # The hook annotation
+  # +docs:name=Hook
+  "helm.sh/hook<\"x\">": pre-install
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	// The path is escaped in the title attribute
+	for _, templateName := range []string{"markdown-table", "markdown-table-objects", "markdown-nested"} {
+		output, err := Render(templateName, document)
+		require.NoError(t, err)
+		require.Contains(t, output, `<span title="annotations[&#34;helm.sh/hook&lt;\&#34;x\&#34;&gt;&#34;]">Hook</span>`, templateName)
+	}
+}
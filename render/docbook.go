@@ -0,0 +1,30 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import "strings"
+
+// docbookText converts a text comment to DocBook paragraphs: the text is
+// escaped, blank lines separate paragraphs and `code spans` are rendered as
+// code. DocBook has no line breaks, the lines of a paragraph are joined.
+func docbookText(text string) string {
+	return strings.NewReplacer(
+		"<p>", "<para>",
+		"</p>", "</para>",
+		"<br>", "",
+	).Replace(htmlText(text))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocbookText(t *testing.T) {
+	require.Equal(t, "<para>Use <code>a &lt; b</code>\nor not</para>\n<para>Second</para>", docbookText("Use `a < b`\nor not\n\nSecond"))
+}
+
+func TestRenderDocbook(t *testing.T) {
+	values := `# Replicas
+replicaCount: 1
+
+# +docs:section=Image
+
+# The image <tag>
+# +docs:see=replicaCount
+tag: v1
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("docbook", document)
+	require.NoError(t, err)
+
+	// The output is well-formed XML
+	decoder := xml.NewDecoder(strings.NewReader(output))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+	}
+
+	require.True(t, strings.HasPrefix(output, "<?xml"))
+	require.Contains(t, output, "<varlistentry xml:id=\"replicacount\">\n<term><varname>replicaCount</varname></term>\n")
+	require.Contains(t, output, "<section>\n<title>Image</title>\n<variablelist>\n")
+	require.Contains(t, output, "<para>The image &lt;tag&gt;</para>\n")
+	require.Contains(t, output, `<para>See also: <link linkend="replicacount"><varname>replicaCount</varname></link></para>`)
+	require.Contains(t, output, "<programlisting language=\"yaml\">v1</programlisting>")
+}
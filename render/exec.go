@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/internal/shellwords"
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// FormatExecPrefix is the prefix of formats that are rendered by an external
+// program, eg. "exec:./my-renderer --flag".
+const FormatExecPrefix = "exec:"
+
+// renderExec pipes the JSON representation of the document to the stdin of
+// the command, and returns its stdout. The stderr of the command is passed
+// through so renderers can report problems.
+func renderExec(command string, document *parser.Document) (string, error) {
+	args, err := shellwords.Split(command)
+	if err != nil {
+		return "", fmt.Errorf("renderer %q: %w", command, err)
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("no renderer command provided")
+	}
+
+	input, err := MarshalDocument(document)
+	if err != nil {
+		return "", err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("renderer %q failed: %w", command, err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderExec(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not available")
+	}
+
+	path, err := paths.Parse("image.tag")
+	require.NoError(t, err)
+
+	document := &parser.Document{
+		Chart: &parser.Chart{Name: "example"},
+		Sections: []parser.Section{{
+			Properties: []parser.Property{{Path: path, Type: parser.TypeString, Default: "v1"}},
+		}},
+	}
+
+	output, err := RenderWithOptions("markdown-plain", document, Options{Format: "exec:cat"})
+	require.NoError(t, err)
+
+	var result JSONDocument
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Equal(t, "example", result.Chart.Name)
+	require.Equal(t, JSONProperty{
+		Path:        "image.tag",
+		Anchor:      "image-tag",
+		SetPath:     "image.tag",
+		Description: JSONComment{Segments: []JSONSegment{}},
+		Type:        "string",
+		Default:     "v1",
+	}, result.Sections[0].Properties[0])
+
+	_, err = RenderWithOptions("markdown-plain", document, Options{Format: "unknown"})
+	require.Error(t, err)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterValue returns the value of a front matter field as a YAML
+// scalar, so eg. "3" is written as a number and "true" as a boolean.
+func frontMatterValue(value string) any {
+	var result any
+	if err := yaml.Unmarshal([]byte(value), &result); err != nil {
+		return value
+	}
+
+	switch result.(type) {
+	case int, float64, bool:
+		return result
+	default:
+		return value
+	}
+}
+
+// Formats of the front matter.
+const (
+	FrontMatterYAML = "yaml"
+	FrontMatterTOML = "toml"
+)
+
+// frontMatter returns the front matter block used by documentation site
+// generators (eg. Docusaurus or Hugo), with the fields of Options.FrontMatter
+// sorted by name. It returns an empty string if no fields are set.
+func (o Options) frontMatter() (string, error) {
+	if len(o.FrontMatter) == 0 {
+		return "", nil
+	}
+
+	keys := make([]string, 0, len(o.FrontMatter))
+	fields := map[string]any{}
+	for key, value := range o.FrontMatter {
+		keys = append(keys, key)
+		fields[key] = frontMatterValue(value)
+	}
+	sort.Strings(keys)
+
+	switch o.FrontMatterFormat {
+	case "", FrontMatterYAML:
+		output, err := yaml.Marshal(fields)
+		if err != nil {
+			return "", err
+		}
+
+		return "---\n" + string(output) + "---\n", nil
+	case FrontMatterTOML:
+		var sb strings.Builder
+		sb.WriteString("+++\n")
+		for _, key := range keys {
+			fmt.Fprintf(&sb, "%s = %s\n", tomlKey(key), tomlValue(fields[key]))
+		}
+		sb.WriteString("+++\n")
+		return sb.String(), nil
+	default:
+		return "", fmt.Errorf("unknown front matter format %q", o.FrontMatterFormat)
+	}
+}
+
+// tomlBareKeyExp matches the keys that can be written without quotes in TOML.
+var tomlBareKeyExp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlEscaper escapes the characters that can not be used as is in TOML basic
+// strings.
+var tomlEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func tomlKey(key string) string {
+	if tomlBareKeyExp.MatchString(key) {
+		return key
+	}
+
+	return `"` + tomlEscaper.Replace(key) + `"`
+}
+
+func tomlValue(value any) string {
+	if s, ok := value.(string); ok {
+		return `"` + tomlEscaper.Replace(s) + `"`
+	}
+
+	return fmt.Sprint(value)
+}
+
+// mdxEscaper escapes the characters that start JSX elements and expressions
+// in MDX.
+var mdxEscaper = strings.NewReplacer("{", `\{`, "}", `\}`, "<", "&lt;", ">", "&gt;")
+
+// mdxText escapes text for MDX outside of `code spans`, which are kept as is.
+// Line breaks are kept by ending the lines in two spaces, like in Markdown.
+func mdxText(text string) string {
+	parts := strings.Split(text, "`")
+	for i := range parts {
+		// Text between an odd number of backticks is a code span, unless
+		// the last span is unterminated
+		if i%2 == 0 || i == len(parts)-1 {
+			parts[i] = mdxEscaper.Replace(parts[i])
+		}
+	}
+
+	return strings.ReplaceAll(strings.Join(parts, "`"), "\n", "  \n")
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontMatter(t *testing.T) {
+	output, err := Options{}.frontMatter()
+	require.NoError(t, err)
+	require.Empty(t, output)
+
+	output, err = Options{FrontMatter: map[string]string{
+		"title":            "cert-manager: values",
+		"sidebar_position": "3",
+		"slug":             "/values",
+		"draft":            "false",
+		"version":          "1.0.0",
+	}}.frontMatter()
+	require.NoError(t, err)
+	require.Equal(t, `---
+draft: false
+sidebar_position: 3
+slug: /values
+title: 'cert-manager: values'
+version: 1.0.0
+---
+`, output)
+
+	output, err = Options{FrontMatterFormat: FrontMatterTOML, FrontMatter: map[string]string{
+		"title":        `say "hi"`,
+		"weight":       "3",
+		"draft":        "false",
+		"linkTitle.en": "Values",
+	}}.frontMatter()
+	require.NoError(t, err)
+	require.Equal(t, `+++
+draft = false
+"linkTitle.en" = "Values"
+title = "say \"hi\""
+weight = 3
++++
+`, output)
+
+	_, err = Options{FrontMatterFormat: "json", FrontMatter: map[string]string{"title": "Values"}}.frontMatter()
+	require.Error(t, err)
+}
+
+func TestMDXText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "", expected: ""},
+		{text: "The image tag", expected: "The image tag"},
+		{text: "Run image:<version> with {}", expected: `Run image:&lt;version&gt; with \{\}`},
+		{text: "Use `{{ .Values }}` or <b>", expected: "Use `{{ .Values }}` or &lt;b&gt;"},
+		{text: "line 1\nline 2", expected: "line 1  \nline 2"},
+		{text: "unterminated `{code", expected: "unterminated `\\{code"},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, mdxText(test.text), test.text)
+	}
+}
+
+func TestRenderMDX(t *testing.T) {
+	values := `# The image tag, eg. <version>
+tag: "{{ .Chart.AppVersion }}"
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("mdx", document, Options{FrontMatter: map[string]string{"title": "Values"}})
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(output, "---\ntitle: Values\n---\n"))
+	require.Contains(t, output, "The image tag, eg. &lt;version&gt;")
+	// Defaults are code blocks, which are not parsed as JSX
+	require.Contains(t, output, "> ```yaml\n> '{{ .Chart.AppVersion }}'\n> ```")
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/paths"
+
+	"gopkg.in/yaml.v3"
+)
+
+// toCompactJSON converts a YAML default (eg. the Default of a property) to a
+// single line of JSON, which fits in a table cell. Defaults that can not be
+// converted are returned unchanged.
+func toCompactJSON(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+
+	var parsed any
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return value
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(parsed); err != nil {
+		return value
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// yamlSnippet returns a values file snippet setting the value at the path to
+// the default, with the objects and arrays above it nested down to the value
+// (eg. "image:\n  tag: v1" for "image.tag"), so users can copy it into their
+// values file with the right indentation. Defaults that are not valid YAML
+// are set as strings.
+func yamlSnippet(path paths.Path, value string) string {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &document); err != nil {
+		node = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	} else if len(document.Content) > 0 {
+		node = document.Content[0]
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if paths.IsArrayPathComponent(path[i]) {
+			node = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{node}}
+			continue
+		}
+
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: paths.SegmentString(path[i])}
+		node = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{key, node}}
+	}
+
+	var sb strings.Builder
+	encoder := yaml.NewEncoder(&sb)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return value
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
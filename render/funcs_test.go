@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToCompactJSON(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: "", expected: ""},
+		{value: "1", expected: "1"},
+		{value: "true", expected: "true"},
+		{value: "quay.io/jetstack", expected: `"quay.io/jetstack"`},
+		{value: "{}", expected: "{}"},
+		{value: "[]", expected: "[]"},
+		{value: "null", expected: "null"},
+		{value: "- --v=2\n- --a=<b>", expected: `["--v=2","--a=<b>"]`},
+		{value: "limits:\n  cpu: 100m\nrequests: {}", expected: `{"limits":{"cpu":"100m"},"requests":{}}`},
+		{value: "? [a]\n: b", expected: "? [a]\n: b"},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, toCompactJSON(test.value), test.value)
+	}
+}
+
+func TestYAMLSnippet(t *testing.T) {
+	tests := []struct {
+		path     string
+		value    string
+		expected string
+	}{
+		{path: "replicaCount", value: "1", expected: "replicaCount: 1"},
+		{path: "controller.image.tag", value: "v1.14.0", expected: "controller:\n  image:\n    tag: v1.14.0"},
+		{path: "webhook.resources", value: "limits:\n  cpu: 100m", expected: "webhook:\n  resources:\n    limits:\n      cpu: 100m"},
+		{path: "extraArgs[0]", value: "--v=2", expected: "extraArgs:\n  - --v=2"},
+		{path: `podAnnotations["linkerd.io/inject"]`, value: "enabled", expected: "podAnnotations:\n  linkerd.io/inject: enabled"},
+		{path: `labels["true"]`, value: "", expected: "labels:\n  \"true\": null"},
+		{path: "tolerations", value: "[]", expected: "tolerations: []"},
+	}
+
+	for _, test := range tests {
+		path, err := paths.Parse(test.path)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, yamlSnippet(path, test.value), test.path)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+
+	"gopkg.in/yaml.v3"
+)
+
+// helmDocsValue is a row of the values table of helm-docs, the cells are
+// formatted the way helm-docs formats them.
+type helmDocsValue struct {
+	Key         string
+	Type        string
+	Default     string
+	Description string
+}
+
+// helmDocsValues returns the rows of the values table of the default README
+// template of helm-docs: the properties of all sections sorted by key, with
+// the types and JSON encoded defaults that helm-docs uses.
+func helmDocsValues(sections []parser.Section) []helmDocsValue {
+	var values []helmDocsValue
+	for _, section := range sections {
+		for _, property := range section.Properties {
+			value := helmDocsValue{
+				Key:         helmDocsKey(property.Path),
+				Type:        helmDocsType(property),
+				Default:     helmDocsDefault(property),
+				Description: markdownCell(property.Description.String()),
+			}
+
+			values = append(values, value)
+		}
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].Key < values[j].Key
+	})
+
+	return values
+}
+
+// helmDocsKey returns the key of a value in helm-docs, keys containing dots
+// are quoted, eg. `podAnnotations."linkerd.io/inject"`.
+func helmDocsKey(path paths.Path) string {
+	var sb strings.Builder
+	for i, component := range path {
+		key := paths.SegmentString(component)
+		switch {
+		case paths.IsArrayPathComponent(component):
+		case i > 0 && strings.Contains(key, "."):
+			key = `."` + key + `"`
+		case strings.Contains(key, "."):
+			key = `"` + key + `"`
+		case i > 0:
+			key = "." + key
+		}
+
+		sb.WriteString(key)
+	}
+
+	return sb.String()
+}
+
+// decodeDefault returns the default of the property decoded from YAML, ok is
+// false if the property has no default or it is not valid YAML.
+func decodeDefault(property parser.Property) (value any, ok bool) {
+	if strings.TrimSpace(property.Default) == "" {
+		return nil, false
+	}
+
+	if err := yaml.Unmarshal([]byte(property.Default), &value); err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// helmDocsType returns the type name helm-docs uses for the property, eg.
+// "list" for arrays. Types that are set explicitly (eg. "(int)" type hints of
+// the helm-docs dialect) are used as they are.
+func helmDocsType(property parser.Property) string {
+	value, _ := decodeDefault(property)
+
+	switch property.Type {
+	case parser.TypeArray:
+		return "list"
+	case parser.TypeObject:
+		return "object"
+	case parser.TypeBool:
+		return "bool"
+	case parser.TypeString, parser.TypeTimestamp:
+		return "string"
+	case parser.TypeNumber:
+		if _, ok := value.(float64); ok {
+			return "float"
+		}
+		return "int"
+	case parser.TypeUnknown:
+		// helm-docs documents null values as strings
+		return "string"
+	default:
+		return string(property.Type)
+	}
+}
+
+// helmDocsDefault returns the default of the property as helm-docs shows it:
+// JSON encoded in a code span, or `nil` for null values. Computed defaults
+// (the "# @default --" annotation of helm-docs) are shown as they are.
+func helmDocsDefault(property parser.Property) string {
+	if defaultFrom := property.DefaultFrom(); defaultFrom != "" {
+		return markdownCell(defaultFrom)
+	}
+
+	value, ok := decodeDefault(property)
+	if !ok || value == nil {
+		return "`nil`"
+	}
+
+	// Like helm-docs, the JSON is encoded using json.Marshal which escapes
+	// HTML characters
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "`" + markdownCell(property.Default) + "`"
+	}
+
+	return "`" + strings.ReplaceAll(string(encoded), "|", `\|`) + "`"
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHelmDocs(t *testing.T) {
+	values := `# -- (int) The number of replicas
+replicaCount: 1
+image:
+  # -- The image tag,
+  # eg. v1|v2
+  # @default -- The chart appVersion
+  tag: ""
+  repository: nginx
+ratio: 0.5
+tolerations: []
+nodeSelector: {}
+priorityClassName:
+# -- Pod annotations
+podAnnotations:
+  linkerd.io/inject: enabled
+`
+
+	document, err := parser.ParseWithOptions(strings.NewReader(values), t.TempDir(), parser.Options{Dialect: parser.DialectHelmDocs})
+	require.NoError(t, err)
+
+	output, err := Render("helm-docs", document)
+	require.NoError(t, err)
+
+	require.Equal(t, `| Key | Type | Default | Description |
+|-----|------|---------|-------------|
+| image.repository | string | `+"`\"nginx\"`"+` |  |
+| image.tag | string | The chart appVersion | The image tag, eg. v1\|v2 |
+| nodeSelector | object | `+"`{}`"+` |  |
+| podAnnotations | object | `+"`{\"linkerd.io/inject\":\"enabled\"}`"+` | Pod annotations |
+| priorityClassName | string | `+"`nil`"+` |  |
+| ratio | float | `+"`0.5`"+` |  |
+| replicaCount | int | `+"`1`"+` | The number of replicas |
+| tolerations | list | `+"`[]`"+` |  |`, strings.TrimSpace(output))
+}
+
+func TestHelmDocsKey(t *testing.T) {
+	path, err := paths.Parse(`podAnnotations["linkerd.io/inject"].hosts[0]`)
+	require.NoError(t, err)
+	require.Equal(t, `podAnnotations."linkerd.io/inject".hosts[0]`, helmDocsKey(path))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"html"
+	"strings"
+)
+
+// htmlText converts a text comment to HTML paragraphs: the text is escaped,
+// blank lines separate paragraphs and `code spans` are rendered as code.
+func htmlText(text string) string {
+	var sb strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		sb.WriteString("<p>")
+		for i, part := range strings.Split(paragraph, "`") {
+			switch {
+			case i%2 == 0:
+				sb.WriteString(strings.ReplaceAll(html.EscapeString(part), "\n", "<br>\n"))
+			case strings.Count(paragraph, "`")%2 == 1 && i == strings.Count(paragraph, "`"):
+				// Unterminated code span
+				sb.WriteString("`" + html.EscapeString(part))
+			default:
+				sb.WriteString("<code>" + html.EscapeString(part) + "</code>")
+			}
+		}
+		sb.WriteString("</p>\n")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// lineCount returns the number of lines of a (default) value, the html
+// template collapses long defaults.
+func lineCount(value string) int {
+	if value == "" {
+		return 0
+	}
+
+	return strings.Count(strings.TrimSuffix(value, "\n"), "\n") + 1
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "", expected: ""},
+		{text: "The image tag", expected: "<p>The image tag</p>"},
+		{text: "Use `a<b>` or <c>", expected: "<p>Use <code>a&lt;b&gt;</code> or &lt;c&gt;</p>"},
+		{text: "line 1\nline 2\n\nparagraph 2", expected: "<p>line 1<br>\nline 2</p>\n<p>paragraph 2</p>"},
+		{text: "unterminated `code", expected: "<p>unterminated `code</p>"},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, htmlText(test.text), test.text)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image <tag>
+# +docs:see=args
+tag: v1
+# The arguments of the container
+# +docs:property
+args:
+  - --v=2
+  - --a
+  - --b
+  - --c
+  - --d
+  - --e
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("html", document)
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"))
+	require.Contains(t, output, "<h2>Image</h2>")
+	require.Contains(t, output, `<tr id="tag">`)
+	require.Contains(t, output, `<a class="anchor" href="#tag"`)
+	require.Contains(t, output, "<p>The image &lt;tag&gt;</p>")
+	require.Contains(t, output, `<a href="#args"><code>args</code></a>`)
+
+	// Short defaults are shown, long defaults are collapsed
+	require.Contains(t, output, "<td>\n<pre><code class=\"language-yaml\">v1</code></pre>")
+	require.Contains(t, output, "<details>\n<summary>6 lines</summary>")
+}
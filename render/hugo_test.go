@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHugo(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image tag,
+# eg. v1|v2
+#
+# tag: v1
+tag: v1
+# The image pull secrets
+pullSecrets: []
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("hugo", document, Options{
+		FrontMatter:       map[string]string{"title": "Values", "weight": "3"},
+		FrontMatterFormat: FrontMatterTOML,
+		TableShortcode:    "values-table",
+	})
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(output, "+++\ntitle = \"Values\"\nweight = 3\n+++\n"))
+	require.Contains(t, output, "## Image\n\n{{< values-table >}}\n\n| Property | Description | Type | Default |\n| --- | --- | --- | --- |\n")
+	require.Contains(t, output, "| `tag` | The image tag, eg. v1\\|v2 `{\"tag\":\"v1\"}` | string | `\"v1\"` |\n")
+	require.Contains(t, output, "| `pullSecrets` | The image pull secrets | array | `[]` |\n\n{{< /values-table >}}")
+	require.NotContains(t, output, "<table>")
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// IndexEntry is a chart listed on an index page.
+type IndexEntry struct {
+	Chart parser.Chart
+	// Docs is the link to the generated documentation of the chart.
+	Docs string
+}
+
+// WriteIndex writes a Markdown index page listing the charts with their
+// version and description, linking to their documentation, eg. for the
+// landing page of a charts repository.
+func WriteIndex(w io.Writer, title string, entries []IndexEntry) {
+	fmt.Fprintf(w, "# %s\n\n", title)
+	fmt.Fprintf(w, "| Chart | Version | App version | Description |\n")
+	fmt.Fprintf(w, "| --- | --- | --- | --- |\n")
+
+	for _, entry := range entries {
+		fmt.Fprintf(w, "| [%s](%s) | %s | %s | %s |\n",
+			markdownCell(entry.Chart.Name),
+			filepath.ToSlash(filepath.Clean(entry.Docs)),
+			markdownCell(entry.Chart.Version),
+			markdownCell(entry.Chart.AppVersion),
+			markdownCell(entry.Chart.Description),
+		)
+	}
+}
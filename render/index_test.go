@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteIndex(t *testing.T) {
+	var sb strings.Builder
+	WriteIndex(&sb, "Charts", []IndexEntry{
+		{Chart: parser.Chart{Name: "cert-manager", Version: "v1.14.0", AppVersion: "v1.14.0", Description: "A Helm chart for\ncert-manager"}, Docs: "charts/cert-manager/README.md"},
+		{Chart: parser.Chart{Name: "trust-manager", Version: "v0.8.0", Description: "Distributes | trust bundles"}, Docs: "./charts/trust-manager/README.md"},
+	})
+
+	require.Equal(t, `# Charts
+
+| Chart | Version | App version | Description |
+| --- | --- | --- | --- |
+| [cert-manager](charts/cert-manager/README.md) | v1.14.0 | v1.14.0 | A Helm chart for cert-manager |
+| [trust-manager](charts/trust-manager/README.md) | v0.8.0 |  | Distributes \| trust bundles |
+`, sb.String())
+}
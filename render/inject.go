@@ -0,0 +1,339 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// ErrReadOnly is returned when the file to inject into is read-only, in that
+// case the file is left untouched.
+var ErrReadOnly = errors.New("file is read-only")
+
+// DefaultHeader is the header written to new files by CreateInjectTarget.
+const DefaultHeader = "## Parameters"
+
+// CreateInjectTarget creates the file with only the DefaultHeader if it does
+// not exist yet, so the documentation can be injected into it.
+func CreateInjectTarget(path string, headerMatch *regexp.Regexp) error {
+	return createInjectTarget(path, headerMatch, "")
+}
+
+// CreateChartHeaderTarget is CreateInjectTarget for files the chart header is
+// injected into too, new files start with the chart header markers.
+func CreateChartHeaderTarget(path string, headerMatch *regexp.Regexp) error {
+	return createInjectTarget(path, headerMatch, ChartHeaderStart+"\n"+ChartHeaderEnd+"\n\n")
+}
+
+func createInjectTarget(path string, headerMatch *regexp.Regexp, prefix string) error {
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return err
+	}
+
+	if !headerMatch.MatchString(DefaultHeader) {
+		return fmt.Errorf("the header regex %q does not match the header %q that new files are created with", headerMatch, DefaultHeader)
+	}
+
+	return os.WriteFile(path, []byte(prefix+DefaultHeader+"\n"), 0644)
+}
+
+func Inject(path, templateName string, document *parser.Document, headerMatch, footerMatch *regexp.Regexp) error {
+	return InjectWithOptions(path, templateName, document, headerMatch, footerMatch, Options{})
+}
+
+// InjectWithOptions replaces the content between the header and footer in the
+// file with the rendered documentation, see InjectRendered.
+func InjectWithOptions(path, templateName string, document *parser.Document, headerMatch, footerMatch *regexp.Regexp, options Options) error {
+	renderedDocument, err := RenderWithOptions(templateName, document, options)
+	if err != nil {
+		return errors.New("could not render documentation from template")
+	}
+
+	return InjectRendered(path, renderedDocument, headerMatch, footerMatch, options.Provenance)
+}
+
+// InjectRendered replaces the content between the header and footer in the
+// file with the already rendered documentation, preceded by the provenance
+// comment if it is not nil. The header and footer are matched against single
+// lines, so the file is processed line by line and never read into memory as
+// a whole. The file is rewritten in place, so its permissions and ownership
+// are kept.
+func InjectRendered(path, renderedDocument string, headerMatch, footerMatch *regexp.Regexp, provenance *Provenance) error {
+	// Open the file
+	file, err := os.OpenFile(path, os.O_RDWR, 0666)
+	if errors.Is(err, fs.ErrPermission) {
+		return fmt.Errorf("%w: %w", ErrReadOnly, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Privileged users can open read-only files for writing
+	if info.Mode().Perm()&0222 == 0 {
+		return fmt.Errorf("%w: %s", ErrReadOnly, path)
+	}
+
+	if provenance != nil {
+		renderedDocument = "\n" + provenance.Comment() + renderedDocument
+	}
+
+	// The header and footer regexes expect "\n" line endings, the original
+	// line endings are restored when writing the file.
+	endings, err := detectLineEndings(file)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// The result is written to a temporary file first, so the file is not
+	// changed if the markers can not be found
+	tmp, err := os.CreateTemp("", "helm-tool-inject-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	buffered := bufio.NewWriter(tmp)
+	out := &lineWriter{w: buffered, endings: endings}
+	if err := inject(file, out, strings.ReplaceAll(renderedDocument, "\r\n", "\n"), headerMatch, footerMatch); err != nil {
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// The new contents are written before truncating, so the file is not left
+	// empty if writing fails
+	written, err := io.Copy(file, tmp)
+	if err != nil {
+		return err
+	}
+
+	return file.Truncate(written)
+}
+
+// ErrNotIdempotent is returned by VerifyIdempotent if injecting the
+// documentation a second time changes the result.
+var ErrNotIdempotent = errors.New("injection is not idempotent")
+
+// Injection is a rendered document injected between the lines matching
+// HeaderMatch and FooterMatch, with the arguments of InjectRendered.
+type Injection struct {
+	RenderedDocument string
+	HeaderMatch      *regexp.Regexp
+	FooterMatch      *regexp.Regexp
+	Provenance       *Provenance
+}
+
+// VerifyIdempotent applies the injections in order to the contents twice in
+// memory, like InjectRendered does with a file, and returns an
+// ErrNotIdempotent error describing the first changed line if the results
+// differ. This happens if a rendered document contains lines that match its
+// footer or the markers of another injection, or if the markers match in
+// unexpected places, in which case the file grows or shifts on every run.
+func VerifyIdempotent(contents string, injections ...Injection) error {
+	inject := func(contents string) (string, error) {
+		for _, injection := range injections {
+			var err error
+			contents, err = injectString(contents, injection.RenderedDocument, injection.HeaderMatch, injection.FooterMatch, injection.Provenance)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return contents, nil
+	}
+
+	first, err := inject(contents)
+	if err != nil {
+		return err
+	}
+
+	second, err := inject(first)
+	if err != nil {
+		return err
+	}
+
+	if first == second {
+		return nil
+	}
+
+	firstLines := strings.Split(strings.ReplaceAll(first, "\r\n", "\n"), "\n")
+	secondLines := strings.Split(strings.ReplaceAll(second, "\r\n", "\n"), "\n")
+	for i := 0; ; i++ {
+		switch {
+		case i >= len(firstLines):
+			return fmt.Errorf("%w: the second run added line %d %q", ErrNotIdempotent, i+1, secondLines[i])
+		case i >= len(secondLines):
+			return fmt.Errorf("%w: the second run removed line %d %q", ErrNotIdempotent, i+1, firstLines[i])
+		case firstLines[i] != secondLines[i]:
+			return fmt.Errorf("%w: the second run changed line %d from %q to %q", ErrNotIdempotent, i+1, firstLines[i], secondLines[i])
+		}
+	}
+}
+
+// injectString is InjectRendered for contents in memory.
+func injectString(contents, renderedDocument string, headerMatch, footerMatch *regexp.Regexp, provenance *Provenance) (string, error) {
+	if provenance != nil {
+		renderedDocument = "\n" + provenance.Comment() + renderedDocument
+	}
+
+	endings, err := detectLineEndings(strings.NewReader(contents))
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	out := &lineWriter{w: &sb, endings: endings}
+	if err := inject(strings.NewReader(contents), out, strings.ReplaceAll(renderedDocument, "\r\n", "\n"), headerMatch, footerMatch); err != nil {
+		return "", err
+	}
+
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// CheckSingleLine returns an error if the header or footer regex contains a
+// newline. The file is matched line by line, so such a regex would never
+// match.
+func CheckSingleLine(match *regexp.Regexp) error {
+	re, err := syntax.Parse(match.String(), syntax.Perl)
+	if err != nil {
+		return err
+	}
+
+	if containsNewline(re) {
+		return fmt.Errorf("the regex %q contains a newline, but it is matched against single lines", match)
+	}
+
+	return nil
+}
+
+func containsNewline(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpLiteral && slices.Contains(re.Rune, '\n') {
+		return true
+	}
+
+	for _, sub := range re.Sub {
+		if containsNewline(sub) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inject copies the lines of r to w, replacing the lines after the first line
+// matching the header up to the first line matching the footer with the
+// content.
+func inject(r io.Reader, w *lineWriter, content string, headerMatch, footerMatch *regexp.Regexp) error {
+	for _, match := range []*regexp.Regexp{headerMatch, footerMatch} {
+		if err := CheckSingleLine(match); err != nil {
+			return err
+		}
+	}
+
+	const (
+		beforeHeader = iota
+		beforeFooter
+		afterFooter
+	)
+
+	state := beforeHeader
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		text, hasNewline := strings.CutSuffix(line, "\n")
+		if hasNewline {
+			text = strings.TrimSuffix(text, "\r")
+		}
+
+		switch state {
+		case beforeHeader:
+			if loc := headerMatch.FindStringIndex(text); loc != nil {
+				w.WriteString(text[:loc[1]])
+				w.WriteString(content + "\n")
+				state = beforeFooter
+				break
+			}
+
+			w.WriteLine(text, hasNewline)
+
+		case beforeFooter:
+			if loc := footerMatch.FindStringIndex(text); loc != nil {
+				w.WriteLine(text[loc[0]:], hasNewline)
+				state = afterFooter
+			}
+
+		case afterFooter:
+			w.WriteLine(text, hasNewline)
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	if state == beforeHeader {
+		return errors.New("could not find parameters tag")
+	}
+
+	return nil
+}
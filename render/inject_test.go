@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	contents := "## Parameters\nold\n"
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0444))
+
+	err := InjectWithOptions(path, "markdown-plain", &parser.Document{}, regexp.MustCompile(`(?m)^## Parameters$`), regexp.MustCompile(`(?m)^## .*$`), Options{})
+	require.ErrorIs(t, err, ErrReadOnly)
+
+	result, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, contents, string(result))
+}
+
+func TestInjectKeepsPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	require.NoError(t, os.WriteFile(path, []byte("## Parameters\nold\n"), 0600))
+	require.NoError(t, os.Chmod(path, 0640))
+
+	require.NoError(t, InjectWithOptions(path, "markdown-plain", &parser.Document{}, regexp.MustCompile(`(?m)^## Parameters$`), regexp.MustCompile(`(?m)^## .*$`), Options{}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), info.Mode().Perm())
+}
+
+func TestCreateInjectTarget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	headerMatch := regexp.MustCompile(`(?m)^##\s+Parameters *$`)
+
+	require.NoError(t, CreateInjectTarget(path, headerMatch))
+	result, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, DefaultHeader+"\n", string(result))
+
+	// Existing files are left untouched
+	require.NoError(t, os.WriteFile(path, []byte("existing"), 0644))
+	require.NoError(t, CreateInjectTarget(path, headerMatch))
+	result, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "existing", string(result))
+
+	require.Error(t, CreateInjectTarget(filepath.Join(t.TempDir(), "other.md"), regexp.MustCompile(`<!-- start -->`)))
+}
+
+func TestInjectStreaming(t *testing.T) {
+	var sb strings.Builder
+	w := &lineWriter{w: &sb, endings: lineEndings{finalNewline: true}}
+
+	input := "intro\n## Parameters\nold\nold\n## Other\n## Parameters\nkept\n"
+	require.NoError(t, inject(strings.NewReader(input), w, "\nnew", regexp.MustCompile(`^## Parameters$`), regexp.MustCompile(`^## `)))
+	require.NoError(t, w.Close())
+	require.Equal(t, "intro\n## Parameters\nnew\n## Other\n## Parameters\nkept\n", sb.String())
+
+	err := inject(strings.NewReader("no header\n"), w, "", regexp.MustCompile(`^## Parameters$`), regexp.MustCompile(`^## `))
+	require.Error(t, err)
+
+	// Regexes matching across lines are rejected instead of never matching
+	err = inject(strings.NewReader(input), w, "", regexp.MustCompile(`(?m)^## Parameters\n\n`), regexp.MustCompile(`^## `))
+	require.ErrorContains(t, err, "contains a newline")
+}
+
+func TestCheckSingleLine(t *testing.T) {
+	require.NoError(t, CheckSingleLine(regexp.MustCompile(`(?m)^##\s+Parameters *$`)))
+	require.NoError(t, CheckSingleLine(regexp.MustCompile(`<!-- start -->`)))
+	require.Error(t, CheckSingleLine(regexp.MustCompile(`## Parameters\n`)))
+	require.Error(t, CheckSingleLine(regexp.MustCompile("## Parameters\n\n<!-- x -->")))
+	require.Error(t, CheckSingleLine(regexp.MustCompile(`(a|b\nc)`)))
+}
+
+func TestVerifyIdempotent(t *testing.T) {
+	headerMatch, footerMatch := regexp.MustCompile(`^## Parameters$`), regexp.MustCompile(`^## `)
+	contents := "# Chart\r\n\r\n## Parameters\r\n\r\nold\r\n\r\n## License\r\n"
+
+	require.NoError(t, VerifyIdempotent(contents, Injection{"\n### Global\n\nvalues\n", headerMatch, footerMatch, nil}))
+	require.NoError(t, VerifyIdempotent(contents, Injection{"\nvalues\n", headerMatch, footerMatch, NewProvenance("v1.0.0", "values.yaml", []byte("replicaCount: 1\n"))}))
+
+	// A heading in the documentation matches the footer, so the content
+	// after it is injected again on every run
+	err := VerifyIdempotent(contents, Injection{"\n## Global\n\nvalues\n", headerMatch, footerMatch, nil})
+	require.ErrorIs(t, err, ErrNotIdempotent)
+	require.ErrorContains(t, err, `line 8 from "## License" to "## Global"`)
+
+	// The chart header is injected before the documentation
+	contents = "<!-- x-start -->\n<!-- x-end -->\n\n## Parameters\n\nold\n\n## License\n"
+	start, end := regexp.MustCompile(`^<!-- x-start -->$`), regexp.MustCompile(`^<!-- x-end -->$`)
+	require.NoError(t, VerifyIdempotent(contents, Injection{"\n# Chart\n", start, end, nil}, Injection{"\nvalues\n", headerMatch, footerMatch, nil}))
+
+	// The chart header contains a line matching the header of the documentation
+	err = VerifyIdempotent(contents, Injection{"\n## Parameters\n", start, end, nil}, Injection{"\nvalues\n", headerMatch, footerMatch, nil})
+	require.ErrorIs(t, err, ErrNotIdempotent)
+
+	_, err = injectString("no header\n", "", headerMatch, footerMatch, nil)
+	require.Error(t, err)
+}
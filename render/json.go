@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// FormatJSON is the format that renders the JSON representation of the
+// document instead of using a template.
+const FormatJSON = "json"
+
+// JSONDocument is the JSON representation of a document, this is the input
+// of exec renderers.
+type JSONDocument struct {
+	Chart *parser.Chart `json:"chart,omitempty"`
+	// Title, Intro and VersionNote are the document metadata set using
+	// +docs:title, +docs:intro and +docs:version-note tags.
+	Title       string        `json:"title,omitempty"`
+	Intro       *JSONComment  `json:"intro,omitempty"`
+	VersionNote string        `json:"versionNote,omitempty"`
+	Sections    []JSONSection `json:"sections"`
+}
+
+type JSONSection struct {
+	Name        string         `json:"name"`
+	Description JSONComment    `json:"description"`
+	Properties  []JSONProperty `json:"properties"`
+}
+
+type JSONProperty struct {
+	// ID is the stable identifier of the property, if known.
+	ID     string `json:"id,omitempty"`
+	Path   string `json:"path"`
+	Anchor string `json:"anchor"`
+	// SetPath is the path in the syntax of helm's --set flag.
+	SetPath     string      `json:"setPath"`
+	Description JSONComment `json:"description"`
+	Type        string      `json:"type"`
+	Default     string      `json:"default"`
+	// DefaultFrom is where the effective default comes from when it is
+	// computed in the templates of the chart.
+	DefaultFrom string `json:"defaultFrom,omitempty"`
+	// Owners are the teams responsible for the property.
+	Owners []string `json:"owners,omitempty"`
+	// LastCommit is the last commit that changed the property, if known.
+	LastCommit *parser.Commit `json:"lastCommit,omitempty"`
+	// UserValue is the value a user values file sets for the property, if
+	// one was given.
+	UserValue *parser.UserValue `json:"userValue,omitempty"`
+	// SameAs is the path of the property defining the YAML anchor the value
+	// of the property is an alias of, if any.
+	SameAs string `json:"sameAs,omitempty"`
+	// Source is where the value is used: the chart, global or the name of a
+	// dependency of the chart.
+	Source string `json:"source,omitempty"`
+}
+
+// JSONComment contains both the plain text of a comment and its segments, so
+// consumers can either use the text directly or render code blocks
+// themselves.
+type JSONComment struct {
+	Text     string              `json:"text"`
+	Segments []JSONSegment       `json:"segments"`
+	Tags     map[string][]string `json:"tags,omitempty"`
+}
+
+type JSONSegment struct {
+	// Type is either "text" or "yaml"
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// NewJSONDocument converts the document to its JSON representation.
+func NewJSONDocument(document *parser.Document) JSONDocument {
+	result := JSONDocument{
+		Chart:       document.Chart,
+		Title:       document.Title,
+		VersionNote: document.VersionNote,
+		Sections:    []JSONSection{},
+	}
+
+	if document.Intro.Tags.GetBool(parser.TagIntro) {
+		intro := newJSONComment(document.Intro)
+		result.Intro = &intro
+	}
+
+	for _, section := range document.Sections {
+		jsonSection := JSONSection{
+			Name:        section.Name,
+			Description: newJSONComment(section.Description),
+			Properties:  []JSONProperty{},
+		}
+
+		for _, property := range section.Properties {
+			jsonSection.Properties = append(jsonSection.Properties, newJSONProperty(property))
+		}
+
+		result.Sections = append(result.Sections, jsonSection)
+	}
+
+	return result
+}
+
+func newJSONProperty(property parser.Property) JSONProperty {
+	return JSONProperty{
+		ID:          property.ID,
+		Path:        property.Path.String(),
+		Anchor:      propertyAnchor(property),
+		SetPath:     property.Path.SetString(),
+		Description: newJSONComment(property.Description),
+		Type:        property.Type.String(),
+		Default:     property.Default,
+		DefaultFrom: property.DefaultFrom(),
+		Owners:      property.Owners,
+		LastCommit:  property.LastCommit,
+		UserValue:   property.UserValue,
+		SameAs:      property.SameAs,
+		Source:      property.Source,
+	}
+}
+
+func newJSONComment(comment parser.Comment) JSONComment {
+	result := JSONComment{
+		Text:     comment.String(),
+		Segments: []JSONSegment{},
+		Tags:     comment.Tags,
+	}
+
+	for _, segment := range comment.Segments {
+		if segment.Type == heuristics.ContentTypeTag {
+			continue
+		}
+
+		result.Segments = append(result.Segments, JSONSegment{
+			Type:    string(segment.Type),
+			Content: segment.String(),
+		})
+	}
+
+	return result
+}
+
+// MarshalDocument returns the JSON representation of the document.
+func MarshalDocument(document *parser.Document) ([]byte, error) {
+	return json.MarshalIndent(NewJSONDocument(document), "", "  ")
+}
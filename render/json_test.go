@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderJSON(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image tag
+# +docs:see=replicas
+tag: v1
+# The number of replicas
+replicas: 1
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("markdown-plain", document, Options{Format: FormatJSON})
+	require.NoError(t, err)
+
+	var result JSONDocument
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Equal(t, NewJSONDocument(document), result)
+
+	require.Len(t, result.Sections, 2)
+	require.Equal(t, "Image", result.Sections[1].Name)
+	require.Equal(t, "tag", result.Sections[1].Properties[0].Path)
+	require.Equal(t, "The image tag", result.Sections[1].Properties[0].Description.Text)
+	require.Equal(t, []string{"replicas"}, result.Sections[1].Properties[0].Description.Tags[parser.TagSee])
+	require.Equal(t, "number", result.Sections[1].Properties[1].Type)
+	require.Equal(t, "1", result.Sections[1].Properties[1].Default)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// FormatJSONL is the format that renders a JSON object per property, one per
+// line, eg. to query the properties using jq.
+const FormatJSONL = "jsonl"
+
+// JSONLProperty is a line of the JSON lines format, which is the JSON
+// representation of a property with the name of its section.
+type JSONLProperty struct {
+	JSONProperty
+	Section string `json:"section"`
+}
+
+// MarshalJSONL returns the properties of the document as JSON lines.
+func MarshalJSONL(document *parser.Document) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if err := encoder.Encode(JSONLProperty{
+				JSONProperty: newJSONProperty(property),
+				Section:      section.Name,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return buf.Bytes(), nil
+}
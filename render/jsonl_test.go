@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderJSONL(t *testing.T) {
+	values := `# The number of replicas
+replicas: 1
+
+# +docs:section=Image
+
+# The image tag
+tag: v1
+pullPolicy: IfNotPresent
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("markdown-plain", document, Options{Format: FormatJSONL})
+	require.NoError(t, err)
+
+	var lines []JSONLProperty
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		var line JSONLProperty
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+
+	require.Len(t, lines, 3)
+	require.Equal(t, "replicas", lines[0].Path)
+	require.Equal(t, "", lines[0].Section)
+	require.Equal(t, "The number of replicas", lines[0].Description.Text)
+	require.Equal(t, "tag", lines[1].Path)
+	require.Equal(t, "Image", lines[1].Section)
+	require.Equal(t, "pullPolicy", lines[2].Path)
+	require.Equal(t, "", lines[2].Description.Text)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// lineEndings describes the line ending conventions of a file.
+type lineEndings struct {
+	// crlf is true if the file uses "\r\n" line endings.
+	crlf bool
+	// finalNewline is true if the file ends with a line ending.
+	finalNewline bool
+}
+
+// detectLineEndings detects the line endings used by the majority of the lines
+// read from r. Empty files are assumed to use "\n" with a final newline.
+func detectLineEndings(r io.Reader) (lineEndings, error) {
+	var crlf, lf int
+	var previous, last byte
+	empty := true
+
+	reader := bufio.NewReader(r)
+	for {
+		b, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return lineEndings{}, err
+		}
+
+		if b == '\n' {
+			lf++
+			if previous == '\r' {
+				crlf++
+			}
+		}
+
+		previous, last, empty = b, b, false
+	}
+
+	return lineEndings{
+		crlf:         crlf > 0 && crlf*2 >= lf,
+		finalNewline: empty || last == '\n',
+	}, nil
+}
+
+// lineWriter writes text with "\n" line endings using the line endings of the
+// file. Trailing line endings are held back until more text is written, so
+// they can be dropped if the file has no final newline.
+type lineWriter struct {
+	w       io.StringWriter
+	endings lineEndings
+	pending int
+	err     error
+}
+
+// WriteString writes the text, which must use "\n" line endings.
+func (lw *lineWriter) WriteString(s string) {
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			lw.write(s)
+			return
+		}
+
+		lw.write(s[:i])
+		lw.pending++
+		s = s[i+1:]
+	}
+}
+
+// WriteLine writes the line followed by a line ending if newline is true.
+func (lw *lineWriter) WriteLine(line string, newline bool) {
+	lw.write(line)
+	if newline {
+		lw.pending++
+	}
+}
+
+// Close writes the held back line endings if the file has a final newline.
+func (lw *lineWriter) Close() error {
+	if lw.endings.finalNewline {
+		lw.pending = max(lw.pending, 1)
+		lw.flush()
+	}
+
+	return lw.err
+}
+
+func (lw *lineWriter) write(s string) {
+	if s == "" {
+		return
+	}
+
+	lw.flush()
+	if lw.err == nil {
+		_, lw.err = lw.w.WriteString(s)
+	}
+}
+
+// flush writes the held back line endings.
+func (lw *lineWriter) flush() {
+	if lw.err != nil || lw.pending == 0 {
+		return
+	}
+
+	newline := "\n"
+	if lw.endings.crlf {
+		newline = "\r\n"
+	}
+
+	_, lw.err = lw.w.WriteString(strings.Repeat(newline, lw.pending))
+	lw.pending = 0
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectLineEndings(t *testing.T) {
+	headerMatch := regexp.MustCompile(`(?m)^##\s+Parameters *$`)
+	footerMatch := regexp.MustCompile(`(?m)^##?\s+.*$`)
+
+	tests := []struct {
+		name     string
+		contents string
+		expected string
+	}{
+		{
+			name:     "lf",
+			contents: "# Chart\n\n## Parameters\nold\n## Other\n",
+			expected: "# Chart\n\n## Parameters\n\n\n## Other\n",
+		},
+		{
+			name:     "crlf",
+			contents: "# Chart\r\n\r\n## Parameters\r\nold\r\n## Other\r\n",
+			expected: "# Chart\r\n\r\n## Parameters\r\n\r\n\r\n## Other\r\n",
+		},
+		{
+			name:     "crlf without footer",
+			contents: "# Chart\r\n## Parameters\r\nold",
+			expected: "# Chart\r\n## Parameters",
+		},
+		{
+			name:     "no final newline",
+			contents: "## Parameters\nold\n## Other",
+			expected: "## Parameters\n\n\n## Other",
+		},
+		{
+			name:     "no final newline without footer",
+			contents: "## Parameters\nold",
+			expected: "## Parameters",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "README.md")
+			require.NoError(t, os.WriteFile(path, []byte(test.contents), 0644))
+
+			require.NoError(t, InjectWithOptions(path, "markdown-plain", &parser.Document{}, headerMatch, footerMatch, Options{}))
+
+			contents, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, string(contents))
+			if strings.Contains(test.contents, "\r\n") {
+				require.Equal(t, strings.Count(string(contents), "\n"), strings.Count(string(contents), "\r\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"io/fs"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// TemplateIssue is a problem found in a template by LintTemplate.
+type TemplateIssue struct {
+	// Location is the name of the template with the line and column of the
+	// problem, eg. "my-template:3:12".
+	Location string
+	Message  string
+}
+
+func (i TemplateIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Location, i.Message)
+}
+
+// LintTemplate checks a template like LintTemplateFS, the template name is a
+// path on disk or the name of a built-in template.
+func LintTemplate(templateName string, options Options) (string, []TemplateIssue, error) {
+	return LintTemplateFS(defaultTemplates{}, templateName, options)
+}
+
+// LintTemplateFS checks a template read from fsys: it reports syntax errors,
+// references to fields that do not exist on any type of the document data
+// model, calls of templates that are not defined and templates that do not
+// render the sections of the document. The template is then rendered against
+// parser.SampleDocument, which reports the errors of the data it uses, and
+// the result is returned.
+func LintTemplateFS(fsys fs.FS, templateName string, options Options) (string, []TemplateIssue, error) {
+	templateBytes, err := fs.ReadFile(fsys, templateName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	document := substituteDescriptions(parser.SampleDocument())
+
+	funcMap := options.funcMap(document)
+	tmpl, err := template.New(templateName).Funcs(funcMap).Parse(string(templateBytes))
+	if err != nil {
+		return "", []TemplateIssue{{Location: templateName, Message: err.Error()}}, nil
+	}
+
+	fields := knownFields(funcMap)
+	var issues []TemplateIssue
+	usesSections := false
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+
+		walkTemplate(t.Tree.Root, func(node parse.Node, idents []string) {
+			location, _ := t.Tree.ErrorContext(node)
+
+			if templateNode, ok := node.(*parse.TemplateNode); ok {
+				if tmpl.Lookup(templateNode.Name) == nil {
+					issues = append(issues, TemplateIssue{Location: location, Message: fmt.Sprintf("template %q is not defined", templateNode.Name)})
+				}
+				return
+			}
+
+			for _, ident := range idents {
+				if ident == "Sections" {
+					usesSections = true
+				}
+
+				if !fields[ident] {
+					issues = append(issues, TemplateIssue{Location: location, Message: fmt.Sprintf("unknown field %q, it is not a field or method of the documentation", ident)})
+				}
+			}
+		})
+	}
+
+	if !usesSections {
+		issues = append(issues, TemplateIssue{Location: templateName, Message: "the template does not use .Sections, so it does not render any properties"})
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, document); err != nil {
+		issues = append(issues, TemplateIssue{Location: templateName, Message: fmt.Sprintf("could not render the sample document: %s", err)})
+	}
+
+	return sb.String(), issues, nil
+}
+
+// walkTemplate calls fn for the nodes of the template that access fields,
+// with the names of the fields, and for the nodes calling other templates.
+func walkTemplate(node parse.Node, fn func(node parse.Node, idents []string)) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			walkTemplate(child, fn)
+		}
+	case *parse.ActionNode:
+		walkTemplate(node.Pipe, fn)
+	case *parse.IfNode:
+		walkBranch(&node.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranch(&node.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranch(&node.BranchNode, fn)
+	case *parse.TemplateNode:
+		fn(node, nil)
+		walkTemplate(node.Pipe, fn)
+	case *parse.PipeNode:
+		if node == nil {
+			return
+		}
+		for _, cmd := range node.Cmds {
+			for _, arg := range cmd.Args {
+				walkTemplate(arg, fn)
+			}
+		}
+	case *parse.FieldNode:
+		fn(node, node.Ident)
+	case *parse.ChainNode:
+		walkTemplate(node.Node, fn)
+		fn(node, node.Field)
+	case *parse.VariableNode:
+		fn(node, node.Ident[1:])
+	}
+}
+
+func walkBranch(node *parse.BranchNode, fn func(node parse.Node, idents []string)) {
+	walkTemplate(node.Pipe, fn)
+	walkTemplate(node.List, fn)
+	walkTemplate(node.ElseList, fn)
+}
+
+// knownFields returns the names of the fields and methods of the types that
+// are available in templates: the document and the types returned by the
+// template functions, including the types they contain.
+func knownFields(funcMap template.FuncMap) map[string]bool {
+	fields := map[string]bool{}
+	seen := map[reflect.Type]bool{}
+
+	var add func(t reflect.Type)
+	add = func(t reflect.Type) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+
+		for i := 0; i < t.NumMethod(); i++ {
+			fields[t.Method(i).Name] = true
+		}
+		if t.Kind() != reflect.Pointer && t.Kind() != reflect.Interface {
+			add(reflect.PointerTo(t))
+		}
+
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			add(t.Elem())
+		case reflect.Map:
+			add(t.Key())
+			add(t.Elem())
+		case reflect.Struct:
+			for _, field := range reflect.VisibleFields(t) {
+				if field.IsExported() {
+					fields[field.Name] = true
+					add(field.Type)
+				}
+			}
+		}
+	}
+
+	add(reflect.TypeOf(parser.Document{}))
+	for _, fn := range funcMap {
+		fnType := reflect.TypeOf(fn)
+		for i := 0; i < fnType.NumOut(); i++ {
+			add(fnType.Out(i))
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintTemplate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"valid": {Data: []byte(`{{ range .Sections }}## {{ .Name }}
+{{ range .Properties }}- {{ displayName . }}: {{ .Type }} {{ .Default }}
+{{ end }}{{ end }}`)},
+		"invalid":     {Data: []byte(`{{ range .Sections }}{{ .Nmae }}{{ template "row" . }}{{ end }}`)},
+		"no-sections": {Data: []byte(`{{ with .Chart }}{{ .Name }}{{ end }}`)},
+		"syntax":      {Data: []byte(`{{ range .Sections }}`)},
+		"execution":   {Data: []byte(`{{ (index .Sections 10).Name }}`)},
+	}
+
+	output, issues, err := LintTemplateFS(fsys, "valid", Options{})
+	require.NoError(t, err)
+	require.Empty(t, issues)
+	require.Contains(t, output, "## Controller\n- replicaCount: number 1\n")
+
+	_, issues, err = LintTemplateFS(fsys, "invalid", Options{})
+	require.NoError(t, err)
+	require.Equal(t, []TemplateIssue{
+		{Location: "invalid:1:24", Message: `unknown field "Nmae", it is not a field or method of the documentation`},
+		{Location: "invalid:1:44", Message: `template "row" is not defined`},
+		{Location: "invalid", Message: `could not render the sample document: template: invalid:1:24: executing "invalid" at <.Nmae>: can't evaluate field Nmae in type parser.Section`},
+	}, issues)
+
+	_, issues, err = LintTemplateFS(fsys, "no-sections", Options{})
+	require.NoError(t, err)
+	require.Equal(t, []TemplateIssue{
+		{Location: "no-sections", Message: "the template does not use .Sections, so it does not render any properties"},
+	}, issues)
+
+	_, issues, err = LintTemplateFS(fsys, "syntax", Options{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0].Message, "unexpected EOF")
+
+	_, issues, err = LintTemplateFS(fsys, "execution", Options{})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0].Message, "could not render the sample document")
+
+	for _, name := range []string{"markdown-plain", "markdown-table", "markdown-list", "html", "hugo", "confluence", "docbook", "bitnami", "helm-docs"} {
+		_, issues, err := LintTemplate(name, Options{})
+		require.NoError(t, err)
+		require.Empty(t, issues, name)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// The orders in which the properties of a section are listed.
+const (
+	// SortFile lists the properties in the order of the values file, after
+	// the properties with a +docs:weight tag.
+	SortFile = "file"
+	// SortAlphabetical lists the properties sorted by their display name,
+	// using the collation of the locale.
+	SortAlphabetical = "alphabetical"
+)
+
+// locale contains the conventions of a language for formatting numbers,
+// properties are sorted using the collation of golang.org/x/text.
+type locale struct {
+	// decimal and group are the decimal separator and the thousands
+	// separator of numbers.
+	decimal string
+	group   string
+}
+
+// locales are the number formats keyed by language, English is used for the
+// other languages and when no locale is set.
+var locales = map[string]locale{
+	"en": {decimal: ".", group: ","},
+	"da": {decimal: ",", group: "."},
+	"de": {decimal: ",", group: "."},
+	"es": {decimal: ",", group: "."},
+	"fi": {decimal: ",", group: " "},
+	"fr": {decimal: ",", group: " "},
+	"it": {decimal: ",", group: "."},
+	"nb": {decimal: ",", group: " "},
+	"nl": {decimal: ",", group: "."},
+	"pl": {decimal: ",", group: " "},
+	"pt": {decimal: ",", group: "."},
+	"sv": {decimal: ",", group: " "},
+}
+
+// localeLanguage returns the language of a locale, eg. "de" for "de-DE" or
+// "de_DE.UTF-8".
+func localeLanguage(name string) string {
+	language, _, _ := strings.Cut(strings.ToLower(name), "_")
+	language, _, _ = strings.Cut(language, "-")
+	language, _, _ = strings.Cut(language, ".")
+	if language == "no" {
+		return "nb"
+	}
+
+	return language
+}
+
+// languageTag returns the language of the locale of the options, English if
+// no locale is set.
+func (o Options) languageTag() (language.Tag, error) {
+	if o.Locale == "" {
+		return language.English, nil
+	}
+
+	// POSIX locales (eg. de_DE.UTF-8) are accepted as well
+	name, _, _ := strings.Cut(o.Locale, ".")
+	tag, err := language.Parse(strings.ReplaceAll(name, "_", "-"))
+	if err != nil {
+		return language.Und, fmt.Errorf("unknown locale %q: %w", o.Locale, err)
+	}
+
+	return tag, nil
+}
+
+func (o Options) locale() (locale, error) {
+	if _, err := o.languageTag(); err != nil {
+		return locale{}, err
+	}
+
+	if result, ok := locales[localeLanguage(o.Locale)]; ok {
+		return result, nil
+	}
+
+	return locales["en"], nil
+}
+
+// boolNames returns the names true and false are displayed as.
+func (o Options) boolNames() (string, string, error) {
+	if o.BoolFormat == "" {
+		return "true", "false", nil
+	}
+
+	trueName, falseName, ok := strings.Cut(o.BoolFormat, ",")
+	trueName, falseName = strings.TrimSpace(trueName), strings.TrimSpace(falseName)
+	if !ok || trueName == "" || falseName == "" || strings.Contains(falseName, ",") {
+		return "", "", fmt.Errorf("invalid bool format %q, must be the names of true and false separated by a comma, eg. enabled,disabled", o.BoolFormat)
+	}
+
+	return trueName, falseName, nil
+}
+
+func (o Options) validateLocale() error {
+	switch o.Sort {
+	case "", SortFile, SortAlphabetical:
+	default:
+		return fmt.Errorf("unknown sort order %q, must be one of %s or %s", o.Sort, SortFile, SortAlphabetical)
+	}
+
+	if _, err := o.locale(); err != nil {
+		return err
+	}
+
+	_, _, err := o.boolNames()
+	return err
+}
+
+// decimalExp matches the numbers that are formatted for the locale, other
+// numbers (eg. 1e3 or 0x10) are displayed as they are.
+var decimalExp = regexp.MustCompile(`^(-?)([0-9]+)(\.[0-9]+)?$`)
+
+// formatNumber formats a number using the separators of the locale, the
+// digits of integer parts of 5 or more digits are grouped by thousands so
+// ports (eg. 8080) are not split.
+func (l locale) formatNumber(number string) string {
+	match := decimalExp.FindStringSubmatch(number)
+	if match == nil {
+		return number
+	}
+
+	integer := match[2]
+	if len(integer) >= 5 {
+		var groups []string
+		for len(integer) > 3 {
+			groups = append([]string{integer[len(integer)-3:]}, groups...)
+			integer = integer[:len(integer)-3]
+		}
+		integer = strings.Join(append([]string{integer}, groups...), l.group)
+	}
+
+	result := match[1] + integer
+	if match[3] != "" {
+		result += l.decimal + match[3][1:]
+	}
+
+	return result
+}
+
+// localizedDefault returns a default of the type as it is displayed in the
+// prose of the documentation, for the locale and bool format of the options:
+// bools are displayed using the bool format and numbers are formatted if a
+// locale is set. Other defaults are returned as they are, the templates
+// render these in code blocks, which are never localized.
+func (o Options) localizedDefault(valueType parser.Type, value string) string {
+	l, err := o.locale()
+	if err != nil {
+		return value
+	}
+
+	trueName, falseName, err := o.boolNames()
+	if err != nil {
+		return value
+	}
+
+	switch {
+	case valueType == parser.TypeBool && value == "true":
+		return trueName
+	case valueType == parser.TypeBool && value == "false":
+		return falseName
+	case valueType == parser.TypeNumber && o.Locale != "":
+		return l.formatNumber(value)
+	}
+
+	return value
+}
+
+// localize sorts the properties of the document using the collation of the
+// locale of the options: letters are compared ignoring case and diacritics,
+// except for the letters the locale sorts separately (eg. "ä" after "z" in
+// Swedish). The properties of the document must not be shared with the
+// caller, see substituteDescriptions.
+func (o Options) localize(document *parser.Document) (*parser.Document, error) {
+	if err := o.validateLocale(); err != nil {
+		return nil, err
+	}
+
+	if o.Sort != SortAlphabetical {
+		return document, nil
+	}
+
+	tag, _ := o.languageTag()
+	collator := collate.New(tag, collate.Loose)
+	for _, section := range document.Sections {
+		properties := section.Properties
+		sort.SliceStable(properties, func(i, j int) bool {
+			a, b := properties[i].DisplayName(), properties[j].DisplayName()
+			if order := collator.CompareString(a, b); order != 0 {
+				return order < 0
+			}
+
+			return a < b
+		})
+	}
+
+	return document, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderLocale(t *testing.T) {
+	values := `# +docs:name=Zeitüberschreitung
+timeout: 12500.5
+# +docs:name=Äpfel
+apples: 1
+# +docs:name=Ordner
+folder: true
+# +docs:name=aktiviert
+enabled: false
+# +docs:name=Grenzen
+# +docs:property
+limits:
+  cpu: 1000
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	names := func(options Options) []string {
+		output, err := RenderWithOptions("markdown-plain", document, options)
+		require.NoError(t, err)
+
+		var result []string
+		for _, line := range strings.Split(output, "\n") {
+			if name, ok := strings.CutPrefix(line, "#### **"); ok {
+				result = append(result, name[:strings.Index(name, "**")])
+			}
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"Zeitüberschreitung", "Äpfel", "Ordner", "aktiviert", "Grenzen"}, names(Options{}))
+	require.Equal(t, []string{"aktiviert", "Äpfel", "Grenzen", "Ordner", "Zeitüberschreitung"}, names(Options{Sort: SortAlphabetical, Locale: "de-DE"}))
+	require.Equal(t, []string{"aktiviert", "Grenzen", "Ordner", "Zeitüberschreitung", "Äpfel"}, names(Options{Sort: SortAlphabetical, Locale: "sv_SE.UTF-8"}))
+
+	// Only the defaults displayed as prose are localized, the defaults in
+	// code blocks are kept as they are
+	output, err := RenderWithOptions("markdown-plain", document, Options{Locale: "de", BoolFormat: "aktiviert,deaktiviert"})
+	require.NoError(t, err)
+	require.Contains(t, output, "> Default value: 12.500,5\n")
+	require.Contains(t, output, "> Default value: deaktiviert\n")
+	require.Contains(t, output, "> Default value: aktiviert\n")
+	require.Contains(t, output, "> ```yaml\n> cpu: 1000\n> ```")
+	require.NotContains(t, output, "true")
+
+	// The formats contain the values as they are
+	output, err = RenderWithOptions("markdown-plain", document, Options{Format: FormatJSON, Locale: "de", BoolFormat: "aktiviert,deaktiviert"})
+	require.NoError(t, err)
+	require.Contains(t, output, `"default": "12500.5"`)
+
+	_, err = RenderWithOptions("markdown-plain", document, Options{Locale: "xx"})
+	require.ErrorContains(t, err, `unknown locale "xx"`)
+	_, err = RenderWithOptions("markdown-plain", document, Options{BoolFormat: "on"})
+	require.ErrorContains(t, err, `invalid bool format "on"`)
+	_, err = RenderWithOptions("markdown-plain", document, Options{Sort: "random"})
+	require.ErrorContains(t, err, `unknown sort order "random"`)
+}
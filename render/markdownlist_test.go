@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdownList(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image tag
+# +docs:name=Tag
+# +docs:deprecated=Use image.digest instead
+tag: v1
+# The security context
+# +docs:property
+securityContext:
+  runAsNonRoot: true
+  runAsUser: 1000
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("markdown-list", document)
+	require.NoError(t, err)
+
+	require.Contains(t, output, "## Image\n")
+	require.Contains(t, output, "\n### Tag\n\nThe image tag\n")
+	require.NotContains(t, output, "<a id=")
+
+	output, err = RenderWithOptions("markdown-list", document, Options{PropertyAnchors: true})
+	require.NoError(t, err)
+	require.Contains(t, output, "\n\n<a id=\"tag\"></a>\n### Tag\n\nThe image tag\n")
+	require.Contains(t, output, "\n\n- **Path**: `tag`\n- **Type**: `string`\n- **Default**: `v1`\n- **Deprecated**: Use image.digest instead\n")
+	require.Contains(t, output, "\n\n- **Type**: `object`\n- **Default**:\n\n  ```yaml\n  runAsNonRoot: true\n  runAsUser: 1000\n  ```\n")
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDocumentMetadata(t *testing.T) {
+	values := `# +docs:title=Example configuration
+# +docs:version-note=These values apply to v2 and later.
+
+# +docs:intro
+# This page documents the values of the example chart.
+
+# The number of replicas
+replicas: 1
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("markdown-table", document)
+	require.NoError(t, err)
+	require.Less(t, strings.Index(output, "This page documents the values of the example chart."), strings.Index(output, "> These values apply to v2 and later."))
+	require.Less(t, strings.Index(output, "> These values apply to v2 and later."), strings.Index(output, "<table>"))
+
+	// The blockquote ends before the content following it, eg. an anchor
+	output, err = RenderWithOptions("markdown-plain", document, Options{PropertyAnchors: true})
+	require.NoError(t, err)
+	require.Contains(t, output, "> These values apply to v2 and later.\n\n")
+
+	output, err = Render("html", document)
+	require.NoError(t, err)
+	require.Contains(t, output, "<title>Example configuration</title>")
+	require.Contains(t, output, "<h1>Example configuration</h1>\n<p><em>These values apply to v2 and later.</em></p>\n<p>This page documents the values of the example chart.</p>")
+
+	output, err = RenderWithOptions("markdown-table", document, Options{Format: FormatJSON})
+	require.NoError(t, err)
+
+	var jsonDocument JSONDocument
+	require.NoError(t, json.Unmarshal([]byte(output), &jsonDocument))
+	require.Equal(t, "Example configuration", jsonDocument.Title)
+	require.Equal(t, "These values apply to v2 and later.", jsonDocument.VersionNote)
+	require.Equal(t, "This page documents the values of the example chart.", jsonDocument.Intro.Text)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// mkDocsPage is a Markdown page written by WriteMkDocs.
+type mkDocsPage struct {
+	File    string
+	Title   string
+	Section parser.Section
+}
+
+// WriteMkDocs writes the documentation as pages of a MkDocs site to dir: an
+// index.md page with the chart description and the properties that are not
+// part of a section, and a page per section rendered using the template. The
+// returned nav fragment lists the pages under title for the nav of
+// mkdocs.yml, navPrefix is the path of dir relative to the docs_dir of the
+// site (eg. "reference/values").
+func WriteMkDocs(dir string, navPrefix string, title string, templateName string, document *parser.Document, options Options) (string, error) {
+	if options.AdvancedAppendix {
+		document = document.WithAdvancedAppendix()
+		options.AdvancedAppendix = false
+	}
+
+	if title == "" {
+		title = "Helm Values"
+		if document.Title != "" {
+			title = document.Title
+		} else if document.Chart != nil && document.Chart.Name != "" {
+			title = document.Chart.Name + " Helm Values"
+		}
+	}
+
+	pages := []mkDocsPage{{File: "index.md", Title: "Overview"}}
+	used := map[string]bool{"index": true}
+	for _, section := range document.Sections {
+		if section.Name == "" {
+			pages[0].Section.Properties = append(pages[0].Section.Properties, section.Properties...)
+			pages[0].Section.Description = section.Description
+			continue
+		}
+
+		pages = append(pages, mkDocsPage{File: sectionFileName(section.Name, used) + ".md", Title: section.Name, Section: section})
+	}
+
+	propertyPages := map[string]string{}
+	for _, page := range pages {
+		for _, property := range page.Section.Properties {
+			propertyPages[propertyAnchor(property)] = page.File
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	var nav []map[string]string
+	for _, page := range pages {
+		// The page title is the heading of the page, so the section is
+		// rendered without a name
+		section := page.Section
+		section.Name = ""
+		section.Appendix = false
+
+		rendered, err := RenderWithOptions(templateName, &parser.Document{Chart: document.Chart, Sections: []parser.Section{section}}, options)
+		if err != nil {
+			return "", fmt.Errorf("could not render %s: %w", page.File, err)
+		}
+
+		rendered = linkFiles(rendered, page.File, propertyPages)
+
+		var sb strings.Builder
+		sb.WriteString("# " + page.Title + "\n\n")
+		if page.File == "index.md" && document.Chart != nil && document.Chart.Description != "" {
+			sb.WriteString(document.Chart.Description + "\n\n")
+		}
+		if rendered = strings.Trim(rendered, "\n"); rendered != "" {
+			sb.WriteString(rendered + "\n")
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, page.File), []byte(sb.String()), 0o644); err != nil {
+			return "", fmt.Errorf("could not write %s: %w", page.File, err)
+		}
+
+		nav = append(nav, map[string]string{page.Title: path.Join(navPrefix, page.File)})
+	}
+
+	var sb strings.Builder
+	encoder := yaml.NewEncoder(&sb)
+	encoder.SetIndent(2)
+	if err := encoder.Encode([]map[string]any{{title: nav}}); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMkDocs(t *testing.T) {
+	values := `# Replicas
+replicaCount: 1
+
+# +docs:section=Image settings
+# The settings of the image.
+
+# The image tag
+# +docs:see=replicaCount
+tag: v1
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "docs")
+	nav, err := WriteMkDocs(dir, "reference/values", "", "markdown-plain", document, Options{})
+	require.NoError(t, err)
+	require.Equal(t, `- Helm Values:
+    - Overview: reference/values/index.md
+    - Image settings: reference/values/image-settings.md
+`, nav)
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.md"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(index), "# Overview\n\n"), string(index))
+	require.Contains(t, string(index), "#### **replicaCount** ~ `number`")
+
+	page, err := os.ReadFile(filepath.Join(dir, "image-settings.md"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(page), "# Image settings\n\nThe settings of the image.\n"), string(page))
+	require.NotContains(t, string(page), "### Image settings")
+	require.Contains(t, string(page), "See also: [`replicaCount`](index.md#replicacount)")
+}
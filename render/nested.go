@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// headingGroups groups the properties by their parent object, with a group
+// for every object that contains properties and for the objects in between,
+// which are rendered as headings nested under the heading of their parent.
+func (o Options) headingGroups(properties []parser.Property) []propertyGroup {
+	return o.groupProperties(properties, func(path paths.Path) int {
+		return len(path) - 1
+	}, true)
+}
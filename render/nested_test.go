@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadingGroups(t *testing.T) {
+	values := `replicas: 1
+webhook:
+  timeoutSeconds: 30
+  image:
+    tag: v1
+  config:
+    server:
+      port: 443
+logLevel: 2
+webhook2:
+  enabled: true
+args:
+  - --v=2
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	type group struct {
+		Name       string
+		Depth      int
+		Properties []string
+	}
+
+	var groups []group
+	for _, g := range (Options{}).headingGroups(document.Sections[0].Properties) {
+		var properties []string
+		for _, property := range g.Properties {
+			properties = append(properties, property.RelativePath)
+		}
+		groups = append(groups, group{Name: g.Name, Depth: g.Depth, Properties: properties})
+	}
+
+	require.Equal(t, []group{
+		{Name: "", Depth: 0, Properties: []string{"replicas", "logLevel"}},
+		{Name: "webhook", Depth: 1, Properties: []string{"timeoutSeconds"}},
+		{Name: "webhook.image", Depth: 2, Properties: []string{"tag"}},
+		{Name: "webhook.config", Depth: 2},
+		{Name: "webhook.config.server", Depth: 3, Properties: []string{"port"}},
+		{Name: "webhook2", Depth: 1, Properties: []string{"enabled"}},
+		{Name: "args", Depth: 1, Properties: []string{"[0]"}},
+	}, groups)
+
+	output, err := Render("markdown-nested", document)
+	require.NoError(t, err)
+	require.Contains(t, output, "\n### webhook\n")
+	require.Contains(t, output, "\n#### webhook.image\n")
+	require.Contains(t, output, "\n#### webhook.config\n\n\n##### webhook.config.server\n")
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"sort"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// propertyGroup contains the properties of a section that belong to the same
+// object, which is rendered as its own table or heading.
+type propertyGroup struct {
+	// Name is the path of the object (eg. "webhook.image"), it is empty for
+	// the group of properties that are not part of an object.
+	Name string
+	// Depth is the number of components of the path of the object, the
+	// heading level of nested groups is derived from it.
+	Depth      int
+	Properties []objectProperty
+}
+
+type objectProperty struct {
+	parser.Property
+	// RelativePath is the path of the property relative to the object of
+	// its group.
+	RelativePath string
+}
+
+// ParsedProperties returns the properties of the group without their
+// relative paths, eg. for propertyRows.
+func (g propertyGroup) ParsedProperties() []parser.Property {
+	properties := make([]parser.Property, len(g.Properties))
+	for i, property := range g.Properties {
+		properties[i] = property.Property
+	}
+
+	return properties
+}
+
+type groupNode struct {
+	group    propertyGroup
+	children []*groupNode
+	// weight is the lowest +docs:weight of the properties of the group and
+	// of its child groups.
+	weight   int
+	weighted bool
+}
+
+func (n *groupNode) addWeight(property parser.Property) {
+	if weight, ok := property.Weight(); ok && (!n.weighted || weight < n.weight) {
+		n.weight, n.weighted = weight, true
+	}
+}
+
+// groupProperties groups the properties by their object, objectLength
+// returns the number of components of the path of a property that make up
+// its object (0 for properties that are not part of an object). With nested,
+// a group is added for every object in between as well and the groups are
+// ordered depth-first, so each group is followed by the groups of its child
+// objects. The group of the properties that are not part of an object comes
+// first. Like the properties, groups containing properties with a
+// +docs:weight tag are ordered first by ascending weight, the other groups
+// are ordered as the objects first appear in.
+func (o Options) groupProperties(properties []parser.Property, objectLength func(path paths.Path) int, nested bool) []propertyGroup {
+	root := &groupNode{}
+	nodes := map[string]*groupNode{"": root}
+
+	for _, property := range properties {
+		length := objectLength(property.Path)
+
+		node := root
+		node.addWeight(property)
+
+		start := length
+		if nested {
+			start = 1
+		}
+		for l := start; l <= length && l > 0; l++ {
+			name := o.displayPath(property.Path[:l])
+			child, ok := nodes[name]
+			if !ok {
+				child = &groupNode{group: propertyGroup{Name: name, Depth: l}}
+				nodes[name] = child
+				node.children = append(node.children, child)
+			}
+			node = child
+			node.addWeight(property)
+		}
+
+		node.group.Properties = append(node.group.Properties, objectProperty{
+			Property:     property,
+			RelativePath: o.displayPath(property.Path[length:]),
+		})
+	}
+
+	var groups []propertyGroup
+	var walk func(node *groupNode)
+	walk = func(node *groupNode) {
+		if node != root || len(node.group.Properties) > 0 {
+			groups = append(groups, node.group)
+		}
+
+		sort.SliceStable(node.children, func(a, b int) bool {
+			wa, wb := node.children[a], node.children[b]
+			if wa.weighted != wb.weighted {
+				return wa.weighted
+			}
+
+			return wa.weight < wb.weight
+		})
+
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return groups
+}
+
+// groupByObject groups the properties by their top-level object, top-level
+// properties are grouped together in a group without name.
+func (o Options) groupByObject(properties []parser.Property) []propertyGroup {
+	return o.groupProperties(properties, func(path paths.Path) int {
+		return min(len(path)-1, 1)
+	}, false)
+}
+
+// propertyGroups splits the properties of a section with more than
+// MaxSectionProperties properties into groups by their top-level object, so
+// they can be rendered as a table per object. Objects are relative to the
+// parent that all properties of the section share (eg. the groups of a
+// section containing only "webhook" properties are "webhook.image",
+// "webhook.serviceAccount", ...). Smaller sections are returned as a single
+// group.
+func (o Options) propertyGroups(properties []parser.Property) []propertyGroup {
+	depth := commonParentLength(properties)
+	if o.MaxSectionProperties <= 0 || len(properties) <= o.MaxSectionProperties {
+		return o.groupProperties(properties, func(path paths.Path) int { return 0 }, false)
+	}
+
+	return o.groupProperties(properties, func(path paths.Path) int {
+		if len(path) > depth+1 {
+			return depth + 1
+		}
+		return 0
+	}, false)
+}
+
+// commonParentLength returns the length of the longest path that is a parent
+// of all properties.
+func commonParentLength(properties []parser.Property) int {
+	if len(properties) == 0 {
+		return 0
+	}
+
+	length := len(properties[0].Path) - 1
+	for _, property := range properties[1:] {
+		length = min(length, len(property.Path)-1)
+		for i := 0; i < length; i++ {
+			if !property.Path[:i+1].Equal(properties[0].Path[:i+1]) {
+				length = i
+				break
+			}
+		}
+	}
+
+	return max(length, 0)
+}
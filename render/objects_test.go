@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupByObject(t *testing.T) {
+	var properties []parser.Property
+	for _, pathString := range []string{"replicaCount", "webhook.image.tag", "webhook.replicas", "nodeSelector", "cainjector.args[0]"} {
+		path, err := paths.Parse(pathString)
+		require.NoError(t, err)
+		properties = append(properties, parser.Property{Path: path})
+	}
+
+	type group struct {
+		name  string
+		paths []string
+	}
+
+	var result []group
+	for _, g := range (Options{}).groupByObject(properties) {
+		entry := group{name: g.Name}
+		for _, property := range g.Properties {
+			entry.paths = append(entry.paths, property.RelativePath)
+		}
+		result = append(result, entry)
+	}
+
+	require.Equal(t, []group{
+		{name: "", paths: []string{"replicaCount", "nodeSelector"}},
+		{name: "webhook", paths: []string{"image.tag", "replicas"}},
+		{name: "cainjector", paths: []string{"args[0]"}},
+	}, result)
+}
+
+func TestPropertyGroups(t *testing.T) {
+	parseProperties := func(pathStrings ...string) []parser.Property {
+		var properties []parser.Property
+		for _, pathString := range pathStrings {
+			path, err := paths.Parse(pathString)
+			require.NoError(t, err)
+			properties = append(properties, parser.Property{Path: path})
+		}
+		return properties
+	}
+
+	type group struct {
+		name  string
+		paths []string
+	}
+
+	groups := func(options Options, properties []parser.Property) []group {
+		var result []group
+		for _, g := range options.propertyGroups(properties) {
+			entry := group{name: g.Name}
+			for _, property := range g.Properties {
+				entry.paths = append(entry.paths, property.Path.String())
+			}
+			result = append(result, entry)
+		}
+		return result
+	}
+
+	properties := parseProperties("webhook.image.tag", "webhook.replicas", "webhook.image.repository", "webhook.config.enabled")
+
+	// Sections are not split by default or if they are small enough
+	require.Equal(t, []group{{paths: []string{"webhook.image.tag", "webhook.replicas", "webhook.image.repository", "webhook.config.enabled"}}}, groups(Options{}, properties))
+	require.Equal(t, []group{{paths: []string{"webhook.image.tag", "webhook.replicas", "webhook.image.repository", "webhook.config.enabled"}}}, groups(Options{MaxSectionProperties: 4}, properties))
+
+	// Objects are relative to the common parent of the section
+	require.Equal(t, []group{
+		{paths: []string{"webhook.replicas"}},
+		{name: "webhook.image", paths: []string{"webhook.image.tag", "webhook.image.repository"}},
+		{name: "webhook.config", paths: []string{"webhook.config.enabled"}},
+	}, groups(Options{MaxSectionProperties: 3}, properties))
+
+	properties = parseProperties("webhook.image.tag", "cainjector.args[0]", "cainjector.replicas")
+	require.Equal(t, []group{
+		{name: "webhook", paths: []string{"webhook.image.tag"}},
+		{name: "cainjector", paths: []string{"cainjector.args[0]", "cainjector.replicas"}},
+	}, groups(Options{MaxSectionProperties: 1}, properties))
+}
+
+func TestGroupWeights(t *testing.T) {
+	var properties []parser.Property
+	for _, pathString := range []string{"replicas", "webhook.replicas", "cainjector.replicas=2", "startupapicheck.image.tag=1"} {
+		pathString, weight, _ := strings.Cut(pathString, "=")
+		path, err := paths.Parse(pathString)
+		require.NoError(t, err)
+
+		property := parser.Property{Path: path}
+		if weight != "" {
+			property.Description.Tags.Push("+docs:weight=" + weight)
+		}
+		properties = append(properties, property)
+	}
+
+	names := func(groups []propertyGroup) []string {
+		var result []string
+		for _, g := range groups {
+			result = append(result, g.Name)
+		}
+		return result
+	}
+
+	// Groups containing weighted properties are ordered first, by weight
+	require.Equal(t, []string{"", "startupapicheck", "cainjector", "webhook"}, names((Options{}).groupByObject(properties)))
+	require.Equal(t, []string{"", "startupapicheck", "startupapicheck.image", "cainjector", "webhook"}, names((Options{}).headingGroups(properties)))
+	require.Equal(t, []string{"", "startupapicheck", "cainjector", "webhook"}, names((Options{MaxSectionProperties: 1}).propertyGroups(properties)))
+}
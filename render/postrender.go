@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/internal/shellwords"
+)
+
+// PostRenderHook processes the rendered documentation before it is written,
+// eg. to format or sanitize it. Hooks are set using Options.PostRender.
+type PostRenderHook func(output string) (string, error)
+
+// CommandHook returns a hook running an external program on the rendered
+// documentation, eg. "prettier --write" or "markdownlint --fix". The command is
+// split into arguments like a shell would, so arguments can be quoted. The
+// output is written to a temporary file with the extension in dir, whose path
+// is appended to the arguments of the command, and read back after the
+// command succeeded. dir should be the directory the documentation is written
+// to, so that configuration files of the command (eg. .prettierrc) are found.
+// The output of a failing command is included in the returned error.
+func CommandHook(command string, dir string, extension string) PostRenderHook {
+	return func(output string) (string, error) {
+		args, err := shellwords.Split(command)
+		if err != nil {
+			return "", fmt.Errorf("post-render command %q: %w", command, err)
+		}
+		if len(args) == 0 {
+			return "", fmt.Errorf("no post-render command provided")
+		}
+
+		file, err := os.CreateTemp(dir, ".helm-tool-*"+extension)
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(file.Name())
+
+		_, err = file.WriteString(output)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", err
+		}
+
+		cmd := exec.Command(args[0], append(args[1:], file.Name())...)
+		if commandOutput, err := cmd.CombinedOutput(); err != nil {
+			if commandOutput := strings.TrimRight(string(commandOutput), "\n"); commandOutput != "" {
+				return "", fmt.Errorf("post-render command %q failed: %w\n%s", command, err, commandOutput)
+			}
+			return "", fmt.Errorf("post-render command %q failed: %w", command, err)
+		}
+
+		processed, err := os.ReadFile(file.Name())
+		if err != nil {
+			return "", err
+		}
+
+		// The documentation is rendered without a trailing newline, formatters
+		// usually add one
+		if !strings.HasSuffix(output, "\n") {
+			processed = []byte(strings.TrimRight(string(processed), "\n"))
+		}
+
+		return string(processed), nil
+	}
+}
+
+// OutputExtension returns the file extension of the documentation rendered
+// using the template or format, so post-render commands recognize the
+// language of the temporary file.
+func OutputExtension(templateName string, format string) string {
+	switch {
+	case format == FormatJSON, format == FormatJSONL, format == FormatSearchIndex:
+		return ".json"
+	case format == FormatCSV:
+		return ".csv"
+	case format == FormatXML:
+		return ".xml"
+	case format == FormatYAML, format == FormatArtifactHub:
+		return ".yaml"
+	case format != "":
+		return ".txt"
+	}
+
+	switch templateName {
+	case "html":
+		return ".html"
+	case "asciidoc":
+		return ".adoc"
+	case "rst":
+		return ".rst"
+	case "mdx":
+		return ".mdx"
+	case "confluence", "docbook":
+		return ".xml"
+	default:
+		return ".md"
+	}
+}
+
+// postRender applies the post-render hooks to the output in order.
+func (o Options) postRender(output string) (string, error) {
+	for _, hook := range o.PostRender {
+		var err error
+		if output, err = hook(output); err != nil {
+			return "", err
+		}
+	}
+
+	return output, nil
+}
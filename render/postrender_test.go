@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPostRender(t *testing.T) {
+	values := `# The image tag.
+tag: v1.0.0
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	// The temporary file is created in dir and removed afterwards
+	dir := t.TempDir()
+	output, err := RenderWithOptions("markdown-plain", document, Options{
+		PostRender: []PostRenderHook{
+			CommandHook("sed -i 's/image tag/container tag/'", dir, ".md"),
+			func(output string) (string, error) {
+				return strings.ToUpper(output), nil
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, output, "THE CONTAINER TAG.")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	_, err = RenderWithOptions("markdown-plain", document, Options{
+		PostRender: []PostRenderHook{CommandHook("cat /nonexistent", dir, ".md")},
+	})
+	require.ErrorContains(t, err, `post-render command "cat /nonexistent" failed`)
+	require.ErrorContains(t, err, "/nonexistent")
+}
+
+func TestOutputExtension(t *testing.T) {
+	require.Equal(t, ".md", OutputExtension("markdown-table", ""))
+	require.Equal(t, ".html", OutputExtension("html", ""))
+	require.Equal(t, ".json", OutputExtension("markdown-plain", FormatJSON))
+	require.Equal(t, ".xml", OutputExtension("docbook", ""))
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Provenance describes how injected documentation was generated, it is
+// written as a comment at the start of the injected content.
+type Provenance struct {
+	// Version is the version of helm-tool.
+	Version string
+	// Source is the name of the values file the documentation was generated
+	// from.
+	Source string
+	// SourceHash is the sha256 hash of the contents of the values file.
+	SourceHash string
+	// Timestamp is the time the documentation was generated at, it is
+	// omitted if zero so that the output is reproducible.
+	Timestamp time.Time
+}
+
+// NewProvenance returns the provenance of documentation generated from the
+// values file contents.
+func NewProvenance(version, source string, contents []byte) *Provenance {
+	hash := sha256.Sum256(contents)
+	return &Provenance{
+		Version:    version,
+		Source:     source,
+		SourceHash: hex.EncodeToString(hash[:]),
+	}
+}
+
+// Comment returns the provenance as a markdown comment.
+func (p *Provenance) Comment() string {
+	comment := fmt.Sprintf("<!-- generated by helm-tool %s from %s (sha256:%s)", p.Version, p.Source, p.SourceHash)
+	if !p.Timestamp.IsZero() {
+		comment += " at " + p.Timestamp.UTC().Format(time.RFC3339)
+	}
+	return comment + " -->"
+}
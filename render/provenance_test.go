@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenanceComment(t *testing.T) {
+	provenance := NewProvenance("v1.0.0", "values.yaml", []byte("a: b\n"))
+	require.Equal(t, "<!-- generated by helm-tool v1.0.0 from values.yaml (sha256:"+provenance.SourceHash+") -->", provenance.Comment())
+
+	provenance.Timestamp = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.True(t, strings.HasSuffix(provenance.Comment(), " at 2024-01-02T03:04:05Z -->"))
+}
+
+func TestInjectProvenance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "README.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Chart\n\n## Parameters\n\nold\n\n## Other\n"), 0644))
+
+	headerMatch := regexp.MustCompile(`(?m)^##\s+Parameters *$`)
+	footerMatch := regexp.MustCompile(`(?m)^##?\s+.*$`)
+	options := Options{Provenance: NewProvenance("v1.0.0", "values.yaml", []byte("a: b\n"))}
+
+	// Injecting twice replaces the previous comment
+	for i := 0; i < 2; i++ {
+		require.NoError(t, InjectWithOptions(path, "markdown-plain", &parser.Document{}, headerMatch, footerMatch, options))
+	}
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(contents), "<!-- generated by helm-tool"))
+	require.NotContains(t, string(contents), "old")
+	require.Contains(t, string(contents), "## Other")
+}
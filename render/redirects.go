@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// Redirect maps the anchor of a previous path of a renamed property to the
+// anchor of its current path.
+type Redirect struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// FromPath is the previous path of the property, recorded using a
+	// +docs:alias tag.
+	FromPath string `json:"fromPath"`
+	ToPath   string `json:"toPath"`
+}
+
+// Redirects returns a redirect for every +docs:alias tag of the document,
+// sorted by the previous path, so links to the anchors of renamed properties
+// keep working.
+func Redirects(document *parser.Document) ([]Redirect, error) {
+	var result []Redirect
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			for _, alias := range property.Aliases() {
+				path, err := paths.Parse(alias)
+				if err != nil {
+					return nil, fmt.Errorf("could not parse alias %q of %s: %w", alias, property.Path, err)
+				}
+
+				result = append(result, Redirect{
+					From:     path.Anchor(),
+					To:       propertyAnchor(property),
+					FromPath: path.String(),
+					ToPath:   property.Path.String(),
+				})
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].FromPath < result[j].FromPath })
+	return result, nil
+}
+
+// redirectsScript is included in the page of the documentation, it replaces
+// the anchor in the URL with the anchor of the renamed property.
+var redirectsScript = template.Must(template.New("redirects").Parse(`<script>
+(function () {
+  var redirects = {
+{{- range $i, $redirect := . }}{{ if $i }},{{ end }}
+    {{ $redirect.From }}: {{ $redirect.To }}
+{{- end }}
+  };
+  var anchor = window.location.hash.slice(1);
+  if (Object.prototype.hasOwnProperty.call(redirects, anchor)) {
+    window.location.replace("#" + redirects[anchor]);
+  }
+})();
+</script>
+`))
+
+// WriteRedirectsHTML writes a script to include in the page of the
+// documentation, which redirects links to the anchors of the previous paths
+// of renamed properties.
+func WriteRedirectsHTML(w io.Writer, redirects []Redirect) error {
+	return redirectsScript.Execute(w, redirects)
+}
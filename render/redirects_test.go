@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirects(t *testing.T) {
+	values := `image:
+  # The image tag
+  # +docs:alias=imageTag
+  # +docs:alias=image.version
+  tag: v1
+replicas: 1
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	redirects, err := Redirects(document)
+	require.NoError(t, err)
+	require.Equal(t, []Redirect{
+		{From: "image-version", To: "image-tag", FromPath: "image.version", ToPath: "image.tag"},
+		{From: "imagetag", To: "image-tag", FromPath: "imageTag", ToPath: "image.tag"},
+	}, redirects)
+
+	var sb strings.Builder
+	require.NoError(t, WriteRedirectsHTML(&sb, redirects))
+	require.Contains(t, sb.String(), `"image-version": "image-tag",`)
+	require.Contains(t, sb.String(), `"imagetag": "image-tag"`)
+}
@@ -18,15 +18,14 @@ package render
 
 import (
 	"embed"
-	"errors"
-	"io"
+	"fmt"
 	"io/fs"
 	"os"
-	"regexp"
 	"strings"
 	"text/template"
 
 	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
 
 	"github.com/Masterminds/sprig/v3"
 )
@@ -34,9 +33,28 @@ import (
 //go:embed markdown-plain
 //go:embed markdown-table
 //go:embed markdown-table-vertical
+//go:embed markdown-table-objects
+//go:embed markdown-list
+//go:embed markdown-nested
+//go:embed html
+//go:embed asciidoc
+//go:embed rst
+//go:embed mdx
+//go:embed hugo
+//go:embed confluence
+//go:embed docbook
+//go:embed bitnami
+//go:embed helm-docs
 var templates embed.FS
 
+// openTemplate opens the template file at the path, falling back to the
+// built-in template of that name. Directories are skipped, so a directory
+// named like a built-in template (eg. html) does not shadow it.
 func openTemplate(path string) (fs.File, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return templates.Open(path)
+	}
+
 	file, err := os.Open(path)
 	if os.IsNotExist(err) {
 		return templates.Open(path)
@@ -49,25 +67,164 @@ func openTemplate(path string) (fs.File, error) {
 	return file, nil
 }
 
+// defaultTemplates is the filesystem templates are read from when rendering
+// without a filesystem: template names are paths on disk, falling back to the
+// built-in templates.
+type defaultTemplates struct{}
+
+func (defaultTemplates) Open(name string) (fs.File, error) {
+	return openTemplate(name)
+}
+
+// Options configure how documentation is rendered.
+type Options struct {
+	// LinkTypes enables rendering property types as links to their
+	// reference documentation.
+	LinkTypes bool
+	// TypeLinks maps type names to the URL of their documentation, these
+	// take precedence over the built-in Kubernetes type links.
+	TypeLinks map[string]string
+	// Format selects a different output format than the template, formats
+	// starting with "exec:" are rendered by an external program.
+	Format string
+	// Tree renders the property names in tables as an indented tree instead
+	// of full paths, the templates use propertyRows to render the rows.
+	Tree bool
+	// ArrayIndex is the style in which array indices in property paths are
+	// displayed, see ArrayIndexNumber, ArrayIndexEmpty and ArrayIndexWildcard.
+	ArrayIndex string
+	// Provenance is written as a comment at the start of injected content
+	// if set.
+	Provenance *Provenance
+	// MaxSectionProperties is the number of properties above which the
+	// table of a section is split into a table per top-level object (0 never
+	// splits), the templates use propertyGroups to split the tables.
+	MaxSectionProperties int
+	// FrontMatter are the fields of the front matter written at the start
+	// of the page by the templates of documentation site generators (eg.
+	// title, sidebar_position and slug for Docusaurus).
+	FrontMatter map[string]string
+	// FrontMatterFormat is the format of the front matter, see
+	// FrontMatterYAML (the default) and FrontMatterTOML.
+	FrontMatterFormat string
+	// TableShortcode is the name of a Hugo shortcode the hugo template wraps
+	// the tables in, eg. to style them.
+	TableShortcode string
+	// LastCommits renders the last commit that changed each property, these
+	// are set using parser.Document.SetLastCommits.
+	LastCommits bool
+	// UserValues renders the values a user values file sets for each
+	// property, these are set using parser.Document.SetUserValues.
+	UserValues bool
+	// AdvancedAppendix moves the properties marked using +docs:advanced tags
+	// to a collapsed appendix section at the end of the documentation, see
+	// parser.Document.WithAdvancedAppendix.
+	AdvancedAppendix bool
+	// PostRender are the hooks the rendered documentation is passed through
+	// in order, eg. a CommandHook running a formatter.
+	PostRender []PostRenderHook
+	// Sort is the order in which the properties of a section are listed, see
+	// SortFile (the default) and SortAlphabetical.
+	Sort string
+	// Locale is the locale (eg. "de-DE") used to sort the properties
+	// alphabetically and to format numbers, English is used if empty.
+	Locale string
+	// BoolFormat are the names true and false defaults are displayed as,
+	// separated by a comma (eg. "enabled,disabled").
+	BoolFormat string
+	// SameAsAnchors renders the default of properties whose value is a YAML
+	// alias as a link to the property defining the anchor (see
+	// parser.Property.SameAs), instead of repeating the default.
+	SameAsAnchors bool
+	// PropertyAnchors renders an HTML anchor before every property of the
+	// markdown templates, so the properties can be linked to. The anchors
+	// are always rendered if the document links to its properties, using
+	// +docs:see references or SameAsAnchors.
+	PropertyAnchors bool
+	// Columns are the columns rendered by the markdown-table template, see
+	// TableColumns, all the columns are rendered if empty.
+	Columns []string
+}
+
 func Render(templateName string, document *parser.Document) (string, error) {
-	tpl, err := openTemplate(templateName)
+	return RenderWithOptions(templateName, document, Options{})
+}
+
+func RenderWithOptions(templateName string, document *parser.Document, options Options) (string, error) {
+	return RenderFSWithOptions(defaultTemplates{}, templateName, document, options)
+}
+
+// RenderFS renders the document using the template templateName read from
+// fsys, so programs embedding helm-tool can provide their own (embedded)
+// templates instead of the built-in templates and the files on disk.
+func RenderFS(fsys fs.FS, templateName string, document *parser.Document) (string, error) {
+	return RenderFSWithOptions(fsys, templateName, document, Options{})
+}
+
+// RenderFSWithOptions is RenderFS with options.
+func RenderFSWithOptions(fsys fs.FS, templateName string, document *parser.Document, options Options) (string, error) {
+	output, err := renderFS(fsys, templateName, document, options)
 	if err != nil {
 		return "", err
 	}
 
-	defer tpl.Close()
+	return options.postRender(output)
+}
+
+func renderFS(fsys fs.FS, templateName string, document *parser.Document, options Options) (string, error) {
+	document = substituteDescriptions(document)
+	if options.AdvancedAppendix {
+		document = document.WithAdvancedAppendix()
+	}
 
-	templateBytes, err := io.ReadAll(tpl)
+	switch {
+	case options.Format == "":
+	case options.Format == FormatJSON:
+		output, err := MarshalDocument(document)
+		return string(output), err
+	case options.Format == FormatJSONL:
+		output, err := MarshalJSONL(document)
+		return string(output), err
+	case options.Format == FormatCSV:
+		output, err := MarshalCSV(document)
+		return string(output), err
+	case options.Format == FormatXML:
+		output, err := MarshalXML(document)
+		return string(output), err
+	case options.Format == FormatYAML:
+		output, err := MarshalYAML(document)
+		return string(output), err
+	case options.Format == FormatSearchIndex:
+		output, err := MarshalSearchIndex(document)
+		return string(output), err
+	case options.Format == FormatArtifactHub:
+		output, err := MarshalArtifactHub(document)
+		return string(output), err
+	case strings.HasPrefix(options.Format, FormatExecPrefix):
+		return renderExec(strings.TrimPrefix(options.Format, FormatExecPrefix), document)
+	default:
+		return "", fmt.Errorf("unknown format %q", options.Format)
+	}
+
+	if err := options.validateArrayIndex(); err != nil {
+		return "", err
+	}
+
+	if err := options.validateColumns(); err != nil {
+		return "", err
+	}
+
+	document, err := options.localize(document)
 	if err != nil {
 		return "", err
 	}
 
-	funcMap := sprig.TxtFuncMap()
-	funcMap["indentWith"] = func(pad string, v string) string {
-		return pad + strings.Replace(v, "\n", "\n"+pad, -1)
+	templateBytes, err := fs.ReadFile(fsys, templateName)
+	if err != nil {
+		return "", err
 	}
 
-	template, err := template.New(templateName).Funcs(funcMap).Parse(string(templateBytes))
+	template, err := template.New(templateName).Funcs(options.funcMap(document)).Parse(string(templateBytes))
 	if err != nil {
 		return "", err
 	}
@@ -80,47 +237,137 @@ func Render(templateName string, document *parser.Document) (string, error) {
 	return sb.String(), nil
 }
 
-func Inject(path, templateName string, document *parser.Document, headerMatch, footerMatch *regexp.Regexp) error {
-	// Open the file
-	file, err := os.OpenFile(path, os.O_RDWR, 0666)
-	if err != nil {
-		return err
+// funcMap returns the functions available in the templates, these are the
+// sprig functions and the helpers of the built-in templates.
+func (o Options) funcMap(document *parser.Document) template.FuncMap {
+	funcMap := sprig.TxtFuncMap()
+	funcMap["indentWith"] = func(pad string, v string) string {
+		return pad + strings.Replace(v, "\n", "\n"+pad, -1)
 	}
+	funcMap["anchor"] = documentAnchor(document)
+	funcMap["setPath"] = setPath
+	funcMap["typeLink"] = o.typeLink
+	funcMap["groupByObject"] = o.groupByObject
+	funcMap["headingGroups"] = o.headingGroups
+	funcMap["displayPath"] = o.displayPath
+	funcMap["displayName"] = o.displayName
+	funcMap["toCompactJson"] = toCompactJSON
+	funcMap["yamlSnippet"] = yamlSnippet
+	funcMap["propertyRows"] = o.propertyRows
+	funcMap["propertyGroups"] = o.propertyGroups
+	funcMap["lastCommits"] = func() bool { return o.LastCommits }
+	funcMap["userValues"] = func() bool { return o.UserValues }
+	funcMap["sameAsAnchors"] = func() bool { return o.SameAsAnchors }
+	funcMap["propertyAnchors"] = func() bool { return o.PropertyAnchors || o.hasPropertyLinks(document) }
+	funcMap["column"] = o.column
+	funcMap["localizedDefault"] = o.localizedDefault
+	funcMap["hasOwners"] = func() bool { return hasOwners(document) }
+	funcMap["hasDependencies"] = document.HasDependencies
+	funcMap["htmlText"] = htmlText
+	funcMap["lineCount"] = lineCount
+	funcMap["asciidocCell"] = asciidocCell
+	funcMap["asciidocText"] = asciidocText
+	funcMap["rstText"] = rstText
+	funcMap["rstInline"] = rstInline
+	funcMap["mdxText"] = mdxText
+	funcMap["frontMatter"] = o.frontMatter
+	funcMap["markdownCell"] = markdownCell
+	funcMap["confluenceText"] = confluenceText
+	funcMap["confluenceCode"] = confluenceCode
+	funcMap["confluenceAnchor"] = confluenceAnchor
+	funcMap["confluenceLink"] = confluenceLink
+	funcMap["docbookText"] = docbookText
+	funcMap["helmDocsValues"] = helmDocsValues
+	funcMap["tableShortcode"] = func() string { return o.TableShortcode }
 
-	// Read the contents
-	fileContents, err := io.ReadAll(file)
+	return funcMap
+}
+
+// anchor returns the HTML anchor of a property path, which can either be a
+// parsed path or a path string.
+func anchor(path any) string {
+	if p, ok := path.(paths.Path); ok {
+		return p.Anchor()
+	}
+
+	p, err := paths.Parse(fmt.Sprint(path))
 	if err != nil {
-		return err
+		return ""
 	}
 
-	// Find the start of where to inject
-	startIdx := headerMatch.FindIndex(fileContents)
-	if startIdx == nil {
-		return errors.New("could not find parameters tag")
+	return p.Anchor()
+}
+
+// documentAnchor returns the anchor function of the templates, which returns
+// the Anchor of the documented property at the path: these are numbered if
+// the paths of several properties have the same anchor.
+func documentAnchor(document *parser.Document) func(path any) string {
+	anchors := map[string]string{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			anchors[property.Path.String()] = propertyAnchor(property)
+		}
 	}
-	start := startIdx[1]
 
-	// Find the end of where to inject
-	endIdx := footerMatch.FindIndex(fileContents[start:])
-	end := len(fileContents)
-	if endIdx != nil {
-		end = start + endIdx[0]
+	return func(path any) string {
+		if a, ok := anchors[fmt.Sprint(path)]; ok {
+			return a
+		}
+
+		return anchor(path)
+	}
+}
+
+// hasPropertyLinks returns whether the rendered document links to its
+// properties, in which case the property anchors are needed.
+func (o Options) hasPropertyLinks(document *parser.Document) bool {
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if len(property.SeeAlso()) > 0 || (o.SameAsAnchors && property.SameAs != "") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// propertyAnchor returns the Anchor of a property, falling back to the
+// anchor of its path for properties of documents that are not parsed from a
+// values file.
+func propertyAnchor(property parser.Property) string {
+	if property.Anchor != "" {
+		return property.Anchor
 	}
 
-	renderedDocument, err := Render(templateName, document)
+	return property.Path.Anchor()
+}
+
+// setPath returns the path of a property in the syntax of helm's --set flag,
+// the path can either be a parsed path or a path string.
+func setPath(path any) string {
+	if p, ok := path.(paths.Path); ok {
+		return p.SetString()
+	}
+
+	p, err := paths.Parse(fmt.Sprint(path))
 	if err != nil {
-		return errors.New("could not render documentation from template")
+		return ""
 	}
 
-	header := fileContents[:start]
-	content := []byte(renderedDocument + "\n")
-	footer := fileContents[end:]
+	return p.SetString()
+}
 
-	file.Truncate(0)
-	file.Seek(0, 0)
-	file.Write(header)
-	file.Write(content)
-	file.Write(footer)
+// hasOwners returns whether any property of the document has an owner, the
+// owner column is only rendered for documents with owners.
+func hasOwners(document *parser.Document) bool {
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			if len(property.Owners) > 0 {
+				return true
+			}
+		}
+	}
 
-	return nil
+	return false
 }
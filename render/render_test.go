@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderFS(t *testing.T) {
+	path, err := paths.Parse("image.tag")
+	require.NoError(t, err)
+
+	document := &parser.Document{Sections: []parser.Section{{
+		Name:       "Image",
+		Properties: []parser.Property{{Path: path, Type: parser.TypeString, Default: "v1"}},
+	}}}
+
+	fsys := fstest.MapFS{
+		"templates/list": {Data: []byte(`{{ range .Sections }}{{ range .Properties }}- {{ displayPath .Path }} ({{ .Type }}): {{ .Default }}{{ end }}{{ end }}`)},
+	}
+
+	output, err := RenderFS(fsys, "templates/list", document)
+	require.NoError(t, err)
+	require.Equal(t, "- image.tag (string): v1", output)
+
+	output, err = RenderFSWithOptions(fsys, "templates/list", document, Options{ArrayIndex: ArrayIndexEmpty})
+	require.NoError(t, err)
+	require.Equal(t, "- image.tag (string): v1", output)
+
+	// The built-in templates and files on disk are not used
+	_, err = RenderFS(fsys, "markdown-plain", document)
+	require.Error(t, err)
+}
+
+func TestOpenTemplateDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "markdown-plain"), 0o755))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	document, err := parser.Parse(strings.NewReader("# The image tag.\ntag: v1.0.0\n"), dir, false)
+	require.NoError(t, err)
+
+	// The directory does not shadow the built-in template
+	output, err := Render("markdown-plain", document)
+	require.NoError(t, err)
+	require.Contains(t, output, "The image tag.")
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import "strings"
+
+// rstEscaper escapes the characters that start inline markup in
+// reStructuredText outside of literals.
+var rstEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `|`, `\|`, "`", "\\`")
+
+// rstText converts a text comment to reStructuredText: `code spans` become
+// inline literals, other markup characters are escaped and paragraphs with
+// several lines become line blocks, so the line breaks are kept.
+func rstText(text string) string {
+	var paragraphs []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+
+		lines := strings.Split(rstInline(paragraph), "\n")
+		if len(lines) > 1 {
+			for i, line := range lines {
+				lines[i] = "| " + strings.TrimSpace(line)
+			}
+		}
+
+		paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// rstInline converts the `code spans` of the text to inline literals and
+// escapes the rest of the text.
+func rstInline(text string) string {
+	parts := strings.Split(text, "`")
+	if len(parts)%2 == 0 {
+		// Unterminated code span, escape the last backtick
+		last := len(parts) - 1
+		parts[last-1] += "`" + parts[last]
+		parts = parts[:last]
+	}
+
+	var sb strings.Builder
+	for i, part := range parts {
+		if i%2 == 0 || part == "" {
+			sb.WriteString(rstEscaper.Replace(part))
+			continue
+		}
+
+		sb.WriteString("``" + part + "``")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSTText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "", expected: ""},
+		{text: "The image tag", expected: "The image tag"},
+		{text: "Use `*.example.com` or *", expected: "Use ``*.example.com`` or \\*"},
+		{text: "line 1\nline 2\n\nparagraph 2", expected: "| line 1\n| line 2\n\nparagraph 2"},
+		{text: "unterminated `code", expected: "unterminated \\`code"},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, rstText(test.text), test.text)
+	}
+}
+
+func TestRenderRST(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image tag
+# +docs:see=pullPolicy
+tag: v1
+# The pull policy
+pullPolicy: IfNotPresent
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := Render("rst", document)
+	require.NoError(t, err)
+
+	require.Contains(t, output, "Image\n=====\n")
+	require.Contains(t, output, ".. list-table::\n   :header-rows: 1\n\n   * - Property\n     - Description\n     - Type\n     - Default\n")
+	require.Contains(t, output, "   * - .. _tag:\n\n       ``tag``\n     -\n\n       The image tag\n")
+	require.Contains(t, output, "       See also: :ref:`pullPolicy <pullpolicy>`\n")
+	require.Contains(t, output, "     - string\n     - .. code-block:: yaml\n\n          v1\n")
+}
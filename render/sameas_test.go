@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSameAsAnchors(t *testing.T) {
+	values := `# Tolerations of all pods
+# +docs:property
+tolerations: &tolerations
+  - key: example
+    operator: Exists
+webhook:
+  # Tolerations of the webhook
+  # +docs:property
+  tolerations: *tolerations
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	for _, templateName := range []string{"markdown-table", "markdown-plain", "markdown-list"} {
+		output, err := RenderWithOptions(templateName, document, Options{})
+		require.NoError(t, err)
+		require.Equal(t, 2, strings.Count(output, "key: example"), templateName)
+		require.NotContains(t, output, "ame as", templateName)
+
+		output, err = RenderWithOptions(templateName, document, Options{SameAsAnchors: true})
+		require.NoError(t, err)
+		require.Equal(t, 1, strings.Count(output, "key: example"), templateName)
+		require.Contains(t, output, "ame as [`tolerations`](#tolerations)", templateName)
+	}
+
+	output, err := RenderWithOptions("html", document, Options{SameAsAnchors: true})
+	require.NoError(t, err)
+	require.Contains(t, output, `<p>Same as <a href="#tolerations"><code>tolerations</code></a></p>`)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// FormatSearchIndex is the format that renders a JSON search index of the
+// properties, which documentation sites can load into Lunr or upload to
+// Algolia as is.
+const FormatSearchIndex = "search-index"
+
+// SearchRecord is a record of the search index. ObjectID is the unique
+// identifier of the record Algolia requires, Lunr indexes use it as the ref.
+type SearchRecord struct {
+	ObjectID    string `json:"objectID"`
+	Name        string `json:"name"`
+	Section     string `json:"section"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	// Anchor is the HTML anchor of the property in the rendered
+	// documentation, to link the search results to.
+	Anchor string `json:"anchor"`
+}
+
+// MarshalSearchIndex returns the search index of the document, an array with
+// a record per property.
+func MarshalSearchIndex(document *parser.Document) ([]byte, error) {
+	records := []SearchRecord{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			anchor := propertyAnchor(property)
+			records = append(records, SearchRecord{
+				ObjectID:    anchor,
+				Name:        property.Path.String(),
+				Section:     section.Name,
+				Description: property.Description.String(),
+				Type:        string(property.Type),
+				Anchor:      anchor,
+			})
+		}
+	}
+
+	return json.MarshalIndent(records, "", "  ")
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSearchIndex(t *testing.T) {
+	values := `# The number of replicas
+replicas: 1
+
+# +docs:section=Image
+
+image:
+  # The image tag
+  tag: v1
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("markdown-plain", document, Options{Format: FormatSearchIndex})
+	require.NoError(t, err)
+
+	var records []SearchRecord
+	require.NoError(t, json.Unmarshal([]byte(output), &records))
+	require.Equal(t, []SearchRecord{
+		{ObjectID: "replicas", Name: "replicas", Description: "The number of replicas", Type: "number", Anchor: "replicas"},
+		{ObjectID: "image-tag", Name: "image.tag", Section: "Image", Description: "The image tag", Type: "string", Anchor: "image-tag"},
+	}, records)
+}
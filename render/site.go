@@ -0,0 +1,307 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// sitePage is a page of the static site written by WriteSite, the index page
+// contains the chart description and the properties that are not part of a
+// section.
+type sitePage struct {
+	File    string
+	Title   string
+	Section parser.Section
+}
+
+// siteSearchEntry is an entry of the search index of the static site.
+type siteSearchEntry struct {
+	Path        string `json:"path"`
+	Section     string `json:"section"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
+
+var nonFileNameCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// WriteSite writes a static site documenting the values to dir: an index
+// page, a page per section, a stylesheet and a search script with an index of
+// all properties. The site has no external dependencies, so it can be
+// published as-is, eg. to GitHub Pages.
+func WriteSite(dir string, title string, document *parser.Document, options Options) error {
+	document = substituteDescriptions(document)
+	if options.AdvancedAppendix {
+		document = document.WithAdvancedAppendix()
+	}
+
+	if err := options.validateArrayIndex(); err != nil {
+		return err
+	}
+
+	if title == "" {
+		title = "Helm Values"
+		if document.Title != "" {
+			title = document.Title
+		} else if document.Chart != nil && document.Chart.Name != "" {
+			title = document.Chart.Name + " Helm Values"
+		}
+	}
+
+	pages := []sitePage{{File: "index.html", Title: "Overview"}}
+	used := map[string]bool{"index": true}
+	for _, section := range document.Sections {
+		if section.Name == "" {
+			pages[0].Section.Properties = append(pages[0].Section.Properties, section.Properties...)
+			pages[0].Section.Description = section.Description
+			continue
+		}
+
+		pages = append(pages, sitePage{File: sectionFileName(section.Name, used) + ".html", Title: section.Name, Section: section})
+	}
+
+	// Links to properties (eg. +docs:see tags) point to the page of the
+	// property
+	propertyPages := map[string]string{}
+	var searchIndex []siteSearchEntry
+	for _, page := range pages {
+		for _, property := range page.Section.Properties {
+			propertyPages[propertyAnchor(property)] = page.File
+			searchIndex = append(searchIndex, siteSearchEntry{
+				Path:        options.displayName(property),
+				Section:     page.Title,
+				URL:         page.File + "#" + propertyAnchor(property),
+				Description: property.Description.String(),
+			})
+		}
+	}
+
+	anchor := documentAnchor(document)
+	funcMap := template.FuncMap{
+		"anchor":      anchor,
+		"displayName": options.displayName,
+		"displayPath": options.displayPath,
+		"typeLink":    options.typeLink,
+		"comment":     siteComment,
+		"propertyURL": func(path string) string {
+			return propertyPages[anchor(path)] + "#" + anchor(path)
+		},
+	}
+
+	tmpl, err := template.New("page").Funcs(funcMap).Parse(sitePageTemplate)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		file, err := os.Create(filepath.Join(dir, page.File))
+		if err != nil {
+			return err
+		}
+
+		err = tmpl.Execute(file, map[string]any{
+			"Title": title,
+			"Chart": document.Chart,
+			"Pages": pages,
+			"Page":  page,
+		})
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("could not write %s: %w", page.File, err)
+		}
+	}
+
+	index, err := json.Marshal(searchIndex)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "search.js"), []byte("var searchIndex = "+string(index)+";\n"+siteSearchScript), 0o644); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "style.css"), []byte(siteStyle), 0o644)
+}
+
+// sectionFileName returns the name of the file (without extension) of the
+// page of a section, names that are already used get a number suffix.
+func sectionFileName(sectionName string, used map[string]bool) string {
+	name := strings.Trim(nonFileNameCharacters.ReplaceAllString(strings.ToLower(sectionName), "-"), "-")
+	if name == "" {
+		name = "section"
+	}
+	for base, i := name, 2; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	used[name] = true
+
+	return name
+}
+
+// siteComment renders the segments of a comment as HTML, text as paragraphs
+// and yaml as code blocks.
+func siteComment(comment parser.Comment) template.HTML {
+	var sb strings.Builder
+	for _, segment := range comment.Segments {
+		switch segment.Type {
+		case heuristics.ContentTypeText:
+			sb.WriteString(htmlText(segment.String()))
+		case heuristics.ContentTypeYaml:
+			sb.WriteString(`<pre><code class="language-yaml">` + template.HTMLEscapeString(segment.String()) + "</code></pre>")
+		default:
+			continue
+		}
+		sb.WriteString("\n")
+	}
+
+	return template.HTML(strings.TrimSuffix(sb.String(), "\n"))
+}
+
+const sitePageTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Page.Title }} - {{ .Title }}</title>
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<nav>
+<a class="title" href="index.html">{{ .Title }}</a>
+<input id="search" type="search" placeholder="Search values" autocomplete="off">
+<ul id="search-results"></ul>
+<ul>
+{{- range .Pages }}
+<li><a href="{{ .File }}"{{ if eq .File $.Page.File }} class="current"{{ end }}>{{ .Title }}</a></li>
+{{- end }}
+</ul>
+</nav>
+<main>
+<h1>{{ .Page.Title }}</h1>
+{{- if eq .Page.File "index.html" }}
+{{- with .Chart }}
+<p>{{ .Description }}</p>
+<p>Chart version {{ .Version }}{{ with .AppVersion }}, app version {{ . }}{{ end }}</p>
+{{- end }}
+{{- end }}
+{{ comment .Page.Section.Description }}
+{{- with .Page.Section.Properties }}
+<table>
+<thead>
+<tr>
+<th>Property</th>
+<th>Description</th>
+<th>Type</th>
+<th>Default</th>
+</tr>
+</thead>
+<tbody>
+{{- range . }}
+{{- $type := .Type }}
+<tr id="{{ anchor .Path }}">
+<td><a href="#{{ anchor .Path }}"><code>{{ displayName . }}</code></a>{{ if .Name }}<br><code class="path">{{ displayPath .Path }}</code>{{ end }}</td>
+<td>
+{{- if .Deprecated }}
+<p><strong>Deprecated</strong>: {{ .DeprecationMessage }}</p>
+{{- end }}
+{{ comment .Description }}
+{{- with .SeeAlso }}
+<p>See also: {{ range $i, $see := . }}{{ if $i }}, {{ end }}<a href="{{ propertyURL $see }}"><code>{{ $see }}</code></a>{{ end }}</p>
+{{- end }}
+</td>
+<td>{{ with typeLink $type }}<a href="{{ . }}">{{ $type }}</a>{{ else }}{{ $type }}{{ end }}</td>
+<td>{{ if .DefaultFrom }}Computed: <code>{{ .DefaultFrom }}</code>{{ else }}<pre><code class="language-yaml">{{ .Default }}</code></pre>{{ end }}</td>
+</tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+{{- if eq .Page.File "index.html" }}
+<ul>
+{{- range .Pages }}
+{{- if ne .File "index.html" }}
+<li><a href="{{ .File }}">{{ .Title }}</a> ({{ len .Section.Properties }} values)</li>
+{{- end }}
+{{- end }}
+</ul>
+{{- end }}
+</main>
+<script src="search.js"></script>
+</body>
+</html>
+`
+
+// siteSearchScript filters the search index while typing in the search box,
+// it is written after the index in search.js.
+const siteSearchScript = `(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  input.addEventListener("input", function () {
+    var query = input.value.trim().toLowerCase();
+    results.innerHTML = "";
+    if (query === "") {
+      return;
+    }
+    searchIndex.filter(function (entry) {
+      return entry.path.toLowerCase().indexOf(query) !== -1 || entry.description.toLowerCase().indexOf(query) !== -1;
+    }).slice(0, 20).forEach(function (entry) {
+      var link = document.createElement("a");
+      link.href = entry.url;
+      link.textContent = entry.path;
+      var section = document.createElement("span");
+      section.textContent = entry.section;
+      var item = document.createElement("li");
+      item.appendChild(link);
+      item.appendChild(section);
+      results.appendChild(item);
+    });
+  });
+})();
+`
+
+const siteStyle = `body { display: flex; margin: 0; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.5; color: #1f2328; }
+nav { flex: 0 0 16em; min-height: 100vh; padding: 1em; background: #f6f8fa; border-right: 1px solid #d0d7de; }
+nav .title { display: block; font-weight: bold; margin-bottom: 1em; }
+nav ul { list-style: none; padding: 0; }
+nav a { color: inherit; text-decoration: none; }
+nav a.current { font-weight: bold; }
+#search { width: 100%; box-sizing: border-box; padding: 0.3em; }
+#search-results span { display: block; font-size: 0.8em; color: #59636e; }
+main { flex: 1; min-width: 0; padding: 0 2em 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #d0d7de; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+tr:target { background: #fff8c5; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 0.9em; }
+pre { background: #f6f8fa; margin: 0; padding: 0.5em; overflow-x: auto; }
+.path { color: #59636e; }
+`
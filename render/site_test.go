@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSite(t *testing.T) {
+	values := `# Replicas
+replicaCount: 1
+
+# +docs:section=Image <settings>
+
+# The image tag
+# +docs:see=replicaCount
+tag: v1
+
+# +docs:section=Image settings
+
+# The image digest
+digest: ""
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "site")
+	require.NoError(t, WriteSite(dir, "", document, Options{}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var files []string
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+	require.Equal(t, []string{"image-settings-2.html", "image-settings.html", "index.html", "search.js", "style.css"}, files)
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(index), "<title>Overview - Helm Values</title>")
+	require.Contains(t, string(index), `<tr id="replicacount">`)
+	require.Contains(t, string(index), `<li><a href="image-settings.html">Image &lt;settings&gt;</a> (1 values)</li>`)
+
+	page, err := os.ReadFile(filepath.Join(dir, "image-settings.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(page), `<li><a href="image-settings.html" class="current">Image &lt;settings&gt;</a></li>`)
+	require.Contains(t, string(page), `See also: <a href="index.html#replicacount"><code>replicaCount</code></a>`)
+
+	search, err := os.ReadFile(filepath.Join(dir, "search.js"))
+	require.NoError(t, err)
+	require.Contains(t, string(search), `{"path":"digest","section":"Image settings","url":"image-settings-2.html#digest","description":"The image digest"}`)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// SectionFile is the documentation of a section rendered by RenderSections.
+type SectionFile struct {
+	// FileName is the name of the file the section is written to.
+	FileName string
+	// Contents is the rendered documentation, without leading and trailing
+	// newlines.
+	Contents string
+}
+
+// sectionFileData is the data of the file name templates of RenderSections.
+type sectionFileData struct {
+	// Name is the name of the section, it is empty for the properties that
+	// are not part of a section.
+	Name string
+	// Slug is the name of the section in lower case with dashes, or "index"
+	// for the properties that are not part of a section.
+	Slug string
+	// Index is the position of the section in the documentation, starting
+	// at 1.
+	Index int
+}
+
+// RenderSections renders every section of the document to its own file
+// using the template, for documentation that is too large for a single file.
+// The name of each file is rendered using the Go template fileNameTemplate
+// (eg. "{{ .Index }}-{{ .Slug }}.md"), which defaults to the slug of the
+// section with the extension of the template. Links to properties in other
+// sections point to the file of the section.
+func RenderSections(fileNameTemplate string, templateName string, document *parser.Document, options Options) ([]SectionFile, error) {
+	if fileNameTemplate == "" {
+		fileNameTemplate = "{{ .Slug }}" + OutputExtension(templateName, options.Format)
+	}
+
+	fileName, err := template.New("file name").Parse(fileNameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file name template: %w", err)
+	}
+
+	if options.AdvancedAppendix {
+		document = document.WithAdvancedAppendix()
+		options.AdvancedAppendix = false
+	}
+
+	// The sections link to the properties of the other sections
+	options.PropertyAnchors = options.PropertyAnchors || options.hasPropertyLinks(document)
+
+	var files []SectionFile
+	var sections []parser.Section
+	used := map[string]bool{}
+	for _, section := range document.Sections {
+		if section.Name == "" && len(section.Properties) == 0 && section.Description.String() == "" {
+			continue
+		}
+
+		data := sectionFileData{Name: section.Name, Slug: "index", Index: len(files) + 1}
+		if section.Name != "" {
+			data.Slug = sectionFileName(section.Name, used)
+		} else {
+			used["index"] = true
+		}
+
+		var sb strings.Builder
+		if err := fileName.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("invalid file name template: %w", err)
+		}
+
+		files = append(files, SectionFile{FileName: sb.String()})
+		sections = append(sections, section)
+	}
+
+	propertyFiles := map[string]string{}
+	for i, section := range sections {
+		for _, property := range section.Properties {
+			propertyFiles[propertyAnchor(property)] = files[i].FileName
+		}
+	}
+
+	for i, section := range sections {
+		rendered, err := RenderWithOptions(templateName, &parser.Document{Chart: document.Chart, Sections: []parser.Section{section}}, options)
+		if err != nil {
+			return nil, fmt.Errorf("could not render %s: %w", files[i].FileName, err)
+		}
+
+		files[i].Contents = strings.Trim(linkFiles(rendered, files[i].FileName, propertyFiles), "\n")
+	}
+
+	return files, nil
+}
+
+// linkFiles changes the links to properties (eg. of +docs:see tags), which
+// are rendered as links within the file, to point to the file documenting
+// the property. propertyFiles maps the anchors of the properties to files.
+func linkFiles(rendered string, fileName string, propertyFiles map[string]string) string {
+	for anchor, file := range propertyFiles {
+		if file == fileName {
+			continue
+		}
+
+		rendered = strings.ReplaceAll(rendered, "](#"+anchor+")", "]("+file+"#"+anchor+")")
+		rendered = strings.ReplaceAll(rendered, `href="#`+anchor+`"`, `href="`+file+"#"+anchor+`"`)
+	}
+
+	return rendered
+}
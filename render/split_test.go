@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSections(t *testing.T) {
+	values := `# Replicas
+replicaCount: 1
+
+# +docs:section=Image settings
+
+# The image tag
+# +docs:see=replicaCount
+tag: v1
+
+# +docs:section=Image settings
+
+# The image digest
+digest: ""
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	files, err := RenderSections("", "markdown-plain", document, Options{})
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	require.Equal(t, []string{"index.md", "image-settings.md", "image-settings-2.md"}, []string{files[0].FileName, files[1].FileName, files[2].FileName})
+	require.True(t, strings.HasPrefix(files[0].Contents, `<a id="replicacount"></a>`), files[0].Contents)
+	require.True(t, strings.HasPrefix(files[1].Contents, "### Image settings\n"), files[1].Contents)
+	require.Contains(t, files[1].Contents, "See also: [`replicaCount`](index.md#replicacount)")
+	require.NotContains(t, files[1].Contents, "digest")
+
+	files, err = RenderSections("{{ .Index }}-{{ .Slug }}.html", "html", document, Options{})
+	require.NoError(t, err)
+	require.Equal(t, "3-image-settings-2.html", files[2].FileName)
+
+	files, err = RenderSections("", "html", document, Options{})
+	require.NoError(t, err)
+	require.Equal(t, "index.html", files[0].FileName)
+
+	_, err = RenderSections("{{ .Missing }}", "markdown-plain", document, Options{})
+	require.ErrorContains(t, err, "invalid file name template")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// substitutionData is the data available to template expressions inside
+// values.yaml comments. It is intentionally limited to chart metadata.
+type substitutionData struct {
+	Chart parser.Chart
+}
+
+// substituteDescriptions returns a copy of the document where template
+// expressions (eg. "{{ .Chart.AppVersion }}") in the text of section and
+// property descriptions are expanded. Text that fails to expand is left as-is.
+func substituteDescriptions(document *parser.Document) *parser.Document {
+	data := substitutionData{}
+	if document.Chart != nil {
+		data.Chart = *document.Chart
+	}
+
+	result := *document
+	result.Sections = make([]parser.Section, len(document.Sections))
+	for i, section := range document.Sections {
+		section.Description = substituteComment(section.Description, data)
+
+		properties := make([]parser.Property, len(section.Properties))
+		for j, property := range section.Properties {
+			property.Description = substituteComment(property.Description, data)
+			properties[j] = property
+		}
+		section.Properties = properties
+
+		result.Sections[i] = section
+	}
+
+	return &result
+}
+
+func substituteComment(comment parser.Comment, data substitutionData) parser.Comment {
+	segments := make([]heuristics.CommentBlockSegment, len(comment.Segments))
+	for i, segment := range comment.Segments {
+		if segment.Type == heuristics.ContentTypeText {
+			segment.Contents = strings.Split(substituteText(strings.Join(segment.Contents, "\n"), data), "\n")
+		}
+
+		segments[i] = segment
+	}
+
+	comment.Segments = segments
+	return comment
+}
+
+// substituteText expands the template expressions in a block of text as a
+// whole, so expressions can span multiple lines.
+func substituteText(text string, data substitutionData) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	tpl, err := template.New("description").Option("missingkey=error").Parse(text)
+	if err != nil {
+		log.Printf("could not parse template expression in %q: %s\n", text, err)
+		return text
+	}
+
+	var sb strings.Builder
+	if err := tpl.Execute(&sb, data); err != nil {
+		log.Printf("could not expand template expression in %q: %s\n", text, err)
+		return text
+	}
+
+	return sb.String()
+}
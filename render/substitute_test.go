@@ -0,0 +1,49 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteComment(t *testing.T) {
+	data := substitutionData{Chart: parser.Chart{Name: "example", AppVersion: "v1.2.0"}}
+	text := func(lines ...string) parser.Comment {
+		return parser.Comment{CommentBlock: heuristics.CommentBlock{Segments: []heuristics.CommentBlockSegment{{Type: heuristics.ContentTypeText, Contents: lines}}}}
+	}
+
+	// Expressions can span multiple lines
+	comment := substituteComment(text("The image of {{", ".Chart.Name }}, defaults to {{ .Chart.AppVersion }}."), data)
+	require.Equal(t, []string{"The image of example, defaults to v1.2.0."}, comment.Segments[0].Contents)
+
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	// Text that is not a template is kept and reported once
+	lines := []string{"Use {{ to open", "and {{ again"}
+	comment = substituteComment(text(lines...), data)
+	require.Equal(t, lines, comment.Segments[0].Contents)
+	require.Equal(t, 1, strings.Count(logs.String(), "could not parse template expression"))
+}
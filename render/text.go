@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// TextOptions configure the plain-text output for terminals.
+type TextOptions struct {
+	// Width is the number of columns of the terminal, descriptions are
+	// wrapped and defaults are truncated to fit.
+	Width int
+	// Color highlights the section names, types and deprecated properties
+	// using ANSI escape codes.
+	Color bool
+}
+
+const (
+	ansiBold   = "\x1b[1m"
+	ansiCyan   = "\x1b[36m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+
+	// textIndent is the indentation of the descriptions under the name of a
+	// property.
+	textIndent = "    "
+)
+
+func (o TextOptions) color(code, text string) string {
+	if !o.Color || text == "" {
+		return text
+	}
+
+	return code + text + ansiReset
+}
+
+// WriteText writes the document as plain text for terminals: a line per
+// property with its path, type and default in fixed-width columns, followed
+// by its description wrapped to the width.
+func WriteText(w io.Writer, document *parser.Document, options TextOptions) {
+	if options.Width <= 0 {
+		options.Width = 100
+	}
+
+	pathWidth, typeWidth := 0, 0
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			pathWidth = max(pathWidth, len(property.DisplayName()))
+			typeWidth = max(typeWidth, len(property.Type.String()))
+		}
+	}
+	pathWidth = min(pathWidth, options.Width/2)
+
+	first := true
+	for _, section := range document.Sections {
+		if section.Name == "" && len(section.Properties) == 0 {
+			continue
+		}
+
+		if !first {
+			fmt.Fprintln(w)
+		}
+		first = false
+
+		if section.Name != "" {
+			fmt.Fprintln(w, options.color(ansiBold, section.Name))
+			fmt.Fprintln(w, strings.Repeat("=", min(len(section.Name), options.Width)))
+			writeTextComment(w, section.Description, "", options.Width)
+			fmt.Fprintln(w)
+		}
+
+		for i, property := range section.Properties {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+
+			name := property.DisplayName()
+			typeName := property.Type.String()
+			defaultValue := property.Default
+			if firstLine, _, multiline := strings.Cut(defaultValue, "\n"); multiline {
+				defaultValue = firstLine + " ..."
+			}
+			if available := options.Width - max(pathWidth, len(name)) - typeWidth - 2; len(defaultValue) > available {
+				defaultValue = defaultValue[:max(available-3, 0)] + "..."
+			}
+
+			line := fmt.Sprintf("%s%s %s%s %s",
+				name, strings.Repeat(" ", max(pathWidth-len(name), 0)),
+				options.color(ansiCyan, typeName), strings.Repeat(" ", typeWidth-len(typeName)),
+				defaultValue,
+			)
+			fmt.Fprintln(w, strings.TrimRight(line, " "))
+
+			if property.Deprecated() {
+				for _, line := range wrapText("Deprecated: "+property.DeprecationMessage(), options.Width-len(textIndent)) {
+					fmt.Fprintln(w, textIndent+options.color(ansiYellow, line))
+				}
+			}
+			writeTextComment(w, property.Description, textIndent, options.Width)
+		}
+	}
+}
+
+// writeTextComment writes the text of the comment wrapped to the width, code
+// blocks are indented but not wrapped.
+func writeTextComment(w io.Writer, comment parser.Comment, indent string, width int) {
+	for _, segment := range comment.Segments {
+		switch segment.Type {
+		case heuristics.ContentTypeTag:
+			continue
+		case heuristics.ContentTypeYaml:
+			for _, line := range strings.Split(segment.String(), "\n") {
+				fmt.Fprintln(w, strings.TrimRight(indent+"  "+line, " "))
+			}
+			continue
+		}
+
+		for _, line := range strings.Split(segment.String(), "\n") {
+			for _, wrapped := range wrapText(line, width-len(indent)) {
+				fmt.Fprintln(w, strings.TrimRight(indent+wrapped, " "))
+			}
+		}
+	}
+}
+
+// wrapText splits text into lines of at most width characters, words longer
+// than the width are never split.
+func wrapText(text string, width int) []string {
+	if width <= 0 || len(text) <= width {
+		return []string{text}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		switch {
+		case current == "":
+			current = word
+		case len(current)+1+len(word) > width:
+			lines = append(lines, current)
+			current = word
+		default:
+			current += " " + word
+		}
+	}
+
+	return append(lines, current)
+}
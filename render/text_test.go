@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteText(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image tag, this description is long enough to be wrapped over
+# multiple lines.
+# +docs:deprecated=Use image.digest instead
+tag: v1
+# Extra arguments, eg.
+# args:
+#   - --v=2
+# +docs:property
+args:
+  - --v=2
+  - --logging-format=json
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	var sb strings.Builder
+	WriteText(&sb, document, TextOptions{Width: 40})
+	require.Equal(t, `Image
+=====
+
+tag  string v1
+    Deprecated: Use image.digest instead
+    The image tag, this description is
+    long enough to be wrapped over
+    multiple lines.
+
+args array  - --v=2 ...
+    Extra arguments, eg.
+      args:
+        - --v=2
+`, sb.String())
+
+	sb.Reset()
+	WriteText(&sb, document, TextOptions{Width: 40, Color: true})
+	require.Contains(t, sb.String(), "\x1b[1mImage\x1b[0m\n")
+	require.Contains(t, sb.String(), "tag  \x1b[36mstring\x1b[0m v1\n")
+	require.Contains(t, sb.String(), "    \x1b[33mDeprecated: Use image.digest instead\x1b[0m\n")
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// propertyRow is a row of a properties table.
+type propertyRow struct {
+	parser.Property
+	// Parent is true for rows of objects that are not documented themselves,
+	// but group the properties below them. Only the Label and Depth of these
+	// rows are set.
+	Parent bool
+	// Label is the name displayed for the property, this is the full path
+	// unless the table is rendered as a tree.
+	Label string
+	// Depth is the indentation level of the row in the tree.
+	Depth int
+}
+
+// propertyRows returns the table rows for the properties. When rendering a
+// tree, each row is labelled with the last component of the path only and
+// rows are added for the parent objects of the properties.
+func (o Options) propertyRows(properties []parser.Property) []propertyRow {
+	rows := make([]propertyRow, 0, len(properties))
+	if !o.Tree {
+		for _, property := range properties {
+			rows = append(rows, propertyRow{Property: property, Label: o.displayPath(property.Path)})
+		}
+		return rows
+	}
+
+	var previous paths.Path
+	for _, property := range properties {
+		path := property.Path
+		if len(path) == 0 {
+			continue
+		}
+
+		common := 0
+		for common < len(previous) && common < len(path)-1 && previous[common] == path[common] {
+			common++
+		}
+
+		for depth := common; depth < len(path)-1; depth++ {
+			rows = append(rows, propertyRow{
+				Parent: true,
+				Label:  o.displaySegment(path, depth),
+				Depth:  depth,
+			})
+		}
+
+		rows = append(rows, propertyRow{
+			Property: property,
+			Label:    o.displaySegment(path, len(path)-1),
+			Depth:    len(path) - 1,
+		})
+		previous = path
+	}
+
+	return rows
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyRows(t *testing.T) {
+	var properties []parser.Property
+	for _, pathString := range []string{"replicas", "webhook.image.registry", "webhook.image.tag", "webhook.config", "webhook.config.apiVersion", "webhook.args[0]"} {
+		path, err := paths.Parse(pathString)
+		require.NoError(t, err)
+		properties = append(properties, parser.Property{Path: path})
+	}
+
+	render := func(options Options) []string {
+		var lines []string
+		for _, row := range options.propertyRows(properties) {
+			line := strings.Repeat("  ", row.Depth) + row.Label
+			if row.Parent {
+				line += " (parent)"
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	require.Equal(t, []string{
+		"replicas",
+		"webhook.image.registry",
+		"webhook.image.tag",
+		"webhook.config",
+		"webhook.config.apiVersion",
+		"webhook.args[0]",
+	}, render(Options{}))
+
+	require.Equal(t, []string{
+		"replicas",
+		"webhook (parent)",
+		"  image (parent)",
+		"    registry",
+		"    tag",
+		"  config",
+		"    apiVersion",
+		"  args (parent)",
+		"    [0]",
+	}, render(Options{Tree: true}))
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// kubernetesAPIReference is the page that the Kubernetes type links point to.
+const kubernetesAPIReference = "https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.31/"
+
+// kubernetesTypes maps the Kubernetes API types that are commonly used in
+// values files to the group and version they belong to.
+var kubernetesTypes = map[string]string{
+	"Affinity":                  "core/v1",
+	"Capabilities":              "core/v1",
+	"ConfigMapKeySelector":      "core/v1",
+	"Container":                 "core/v1",
+	"ContainerPort":             "core/v1",
+	"EnvFromSource":             "core/v1",
+	"EnvVar":                    "core/v1",
+	"HostAlias":                 "core/v1",
+	"Lifecycle":                 "core/v1",
+	"LocalObjectReference":      "core/v1",
+	"NodeAffinity":              "core/v1",
+	"PodAffinity":               "core/v1",
+	"PodAntiAffinity":           "core/v1",
+	"PodDNSConfig":              "core/v1",
+	"PodSecurityContext":        "core/v1",
+	"Probe":                     "core/v1",
+	"ResourceRequirements":      "core/v1",
+	"SeccompProfile":            "core/v1",
+	"SecretKeySelector":         "core/v1",
+	"SecurityContext":           "core/v1",
+	"ServicePort":               "core/v1",
+	"Toleration":                "core/v1",
+	"TopologySpreadConstraint":  "core/v1",
+	"Volume":                    "core/v1",
+	"VolumeMount":               "core/v1",
+	"DeploymentStrategy":        "apps/v1",
+	"PodDisruptionBudgetSpec":   "policy/v1",
+	"HorizontalPodAutoscaler":   "autoscaling/v2",
+	"IngressTLS":                "networking/v1",
+	"NetworkPolicyIngressRule":  "networking/v1",
+	"NetworkPolicyEgressRule":   "networking/v1",
+	"LabelSelector":             "meta/v1",
+	"LabelSelectorRequirement":  "meta/v1",
+	"ObjectMeta":                "meta/v1",
+	"Duration":                  "meta/v1",
+	"PodTemplateSpec":           "core/v1",
+	"PersistentVolumeClaimSpec": "core/v1",
+}
+
+// kubernetesTypeExp matches type names like "ResourceRequirements",
+// "v1.ResourceRequirements", "core/v1.ResourceRequirements",
+// "corev1.ResourceRequirements" and "[]core/v1.Toleration".
+var kubernetesTypeExp = regexp.MustCompile(`^(?:\[\])?(?:(?:([a-z]+)/?)?(v[0-9]+(?:(?:alpha|beta)[0-9]+)?)\.)?([A-Z][A-Za-z0-9]*)$`)
+
+// kubernetesTypeLink returns a link to the Kubernetes API reference for the
+// type, or an empty string if the type is not a known Kubernetes type.
+func kubernetesTypeLink(typeName string) string {
+	match := kubernetesTypeExp.FindStringSubmatch(typeName)
+	if match == nil {
+		return ""
+	}
+
+	group, version, kind := match[1], match[2], match[3]
+
+	knownGroupVersion, ok := kubernetesTypes[kind]
+	if !ok {
+		return ""
+	}
+
+	knownGroup, knownVersion, _ := strings.Cut(knownGroupVersion, "/")
+	if group == "" {
+		group = knownGroup
+	}
+	if version == "" {
+		version = knownVersion
+	}
+
+	// The API reference uses anchors like "#resourcerequirements-v1-core"
+	return fmt.Sprintf("%s#%s-%s-%s", kubernetesAPIReference, strings.ToLower(kind), version, group)
+}
+
+// typeLink returns the link for the type name, custom links take precedence
+// over the built-in Kubernetes type links.
+func (o Options) typeLink(typeName any) string {
+	if !o.LinkTypes {
+		return ""
+	}
+
+	name := fmt.Sprint(typeName)
+	if link, ok := o.TypeLinks[name]; ok {
+		return link
+	}
+
+	return kubernetesTypeLink(name)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+)
+
+func TestKubernetesTypeLink(t *testing.T) {
+	tests := []struct {
+		typeName string
+		expected string
+	}{
+		{typeName: "ResourceRequirements", expected: kubernetesAPIReference + "#resourcerequirements-v1-core"},
+		{typeName: "v1.ResourceRequirements", expected: kubernetesAPIReference + "#resourcerequirements-v1-core"},
+		{typeName: "v1beta1.Toleration", expected: kubernetesAPIReference + "#toleration-v1beta1-core"},
+		{typeName: "core/v1.ResourceRequirements", expected: kubernetesAPIReference + "#resourcerequirements-v1-core"},
+		{typeName: "corev1.Toleration", expected: kubernetesAPIReference + "#toleration-v1-core"},
+		{typeName: "[]core/v1.Toleration", expected: kubernetesAPIReference + "#toleration-v1-core"},
+		{typeName: "metav1.LabelSelector", expected: kubernetesAPIReference + "#labelselector-v1-meta"},
+		{typeName: "autoscaling/v2beta2.HorizontalPodAutoscaler", expected: kubernetesAPIReference + "#horizontalpodautoscaler-v2beta2-autoscaling"},
+		{typeName: "string", expected: ""},
+		{typeName: "core/v1.Unknown", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			if got := kubernetesTypeLink(tt.typeName); got != tt.expected {
+				t.Errorf("kubernetesTypeLink() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// FormatXML is the format that renders the document as XML, eg. for
+// documentation pipelines based on DITA that cannot read the JSON output.
+const FormatXML = "xml"
+
+// XMLDocument is the XML representation of a document, it has the same
+// structure as the JSON output.
+type XMLDocument struct {
+	XMLName  xml.Name     `xml:"document"`
+	Chart    *XMLChart    `xml:"chart,omitempty"`
+	Sections []XMLSection `xml:"section"`
+}
+
+type XMLChart struct {
+	Name        string `xml:"name,attr,omitempty"`
+	Version     string `xml:"version,attr,omitempty"`
+	AppVersion  string `xml:"appVersion,attr,omitempty"`
+	Description string `xml:"description,omitempty"`
+}
+
+type XMLSection struct {
+	Name        string        `xml:"name,attr,omitempty"`
+	Description XMLComment    `xml:"description"`
+	Properties  []XMLProperty `xml:"property"`
+}
+
+type XMLProperty struct {
+	Path   string `xml:"path,attr"`
+	Anchor string `xml:"anchor,attr"`
+	// SetPath is the path in the syntax of helm's --set flag.
+	SetPath     string     `xml:"setPath,attr"`
+	Type        string     `xml:"type,attr"`
+	Description XMLComment `xml:"description"`
+	Default     string     `xml:"default"`
+	Deprecated  string     `xml:"deprecated,omitempty"`
+	Owners      []string   `xml:"owner"`
+}
+
+// XMLComment contains the paragraphs and code blocks of a comment, in the
+// order in which they are written.
+type XMLComment struct {
+	Blocks []XMLBlock `xml:",any"`
+}
+
+// XMLBlock is either a paragraph (p) or a code block (codeblock).
+type XMLBlock struct {
+	XMLName xml.Name
+	// Language is set for code blocks, eg. "yaml".
+	Language string `xml:"outputclass,attr,omitempty"`
+	Content  string `xml:",chardata"`
+}
+
+// NewXMLDocument converts the document to its XML representation.
+func NewXMLDocument(document *parser.Document) XMLDocument {
+	var result XMLDocument
+	if chart := document.Chart; chart != nil {
+		result.Chart = &XMLChart{
+			Name:        chart.Name,
+			Version:     chart.Version,
+			AppVersion:  chart.AppVersion,
+			Description: chart.Description,
+		}
+	}
+
+	for _, section := range document.Sections {
+		xmlSection := XMLSection{
+			Name:        section.Name,
+			Description: newXMLComment(section.Description),
+		}
+
+		for _, property := range section.Properties {
+			xmlProperty := XMLProperty{
+				Path:        property.Path.String(),
+				Anchor:      propertyAnchor(property),
+				SetPath:     property.Path.SetString(),
+				Type:        property.Type.String(),
+				Description: newXMLComment(property.Description),
+				Default:     property.Default,
+				Owners:      property.Owners,
+			}
+			if property.Deprecated() {
+				xmlProperty.Deprecated = property.DeprecationMessage()
+			}
+
+			xmlSection.Properties = append(xmlSection.Properties, xmlProperty)
+		}
+
+		result.Sections = append(result.Sections, xmlSection)
+	}
+
+	return result
+}
+
+// newXMLComment splits the text of the comment into paragraphs at empty
+// lines, so multi-paragraph descriptions keep their structure.
+func newXMLComment(comment parser.Comment) XMLComment {
+	var result XMLComment
+	for _, segment := range comment.Segments {
+		switch segment.Type {
+		case heuristics.ContentTypeTag:
+			continue
+		case heuristics.ContentTypeYaml:
+			result.Blocks = append(result.Blocks, XMLBlock{
+				XMLName:  xml.Name{Local: "codeblock"},
+				Language: "yaml",
+				Content:  segment.String(),
+			})
+			continue
+		}
+
+		for _, paragraph := range strings.Split(segment.String(), "\n\n") {
+			if paragraph = strings.TrimSpace(paragraph); paragraph == "" {
+				continue
+			}
+
+			result.Blocks = append(result.Blocks, XMLBlock{
+				XMLName: xml.Name{Local: "p"},
+				Content: paragraph,
+			})
+		}
+	}
+
+	return result
+}
+
+// MarshalXML returns the XML representation of the document.
+func MarshalXML(document *parser.Document) ([]byte, error) {
+	output, err := xml.MarshalIndent(NewXMLDocument(document), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(output, '\n')...), nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderXML(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image tag, eg. "v1" & "v2".
+#
+# Empty uses the appVersion.
+# +docs:deprecated=use image.digest
+tag: v1
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("markdown-plain", document, Options{Format: FormatXML})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(output, xml.Header))
+
+	var result XMLDocument
+	require.NoError(t, xml.Unmarshal([]byte(output), &result))
+	require.Len(t, result.Sections, 2)
+	require.Equal(t, "Image", result.Sections[1].Name)
+
+	property := result.Sections[1].Properties[0]
+	require.Equal(t, "tag", property.Path)
+	require.Equal(t, "string", property.Type)
+	require.Equal(t, "v1", property.Default)
+	require.Equal(t, "use image.digest", property.Deprecated)
+	require.Equal(t, []XMLBlock{
+		{XMLName: xml.Name{Local: "p"}, Content: `The image tag, eg. "v1" & "v2".`},
+		{XMLName: xml.Name{Local: "p"}, Content: "Empty uses the appVersion."},
+	}, property.Description.Blocks)
+}
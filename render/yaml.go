@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatYAML is the format that renders the YAML representation of the
+// document, which has the same structure as the JSON representation. It is
+// meant to be diffed between chart versions, as YAML diffs are easier to
+// review than diffs of JSON or of the rendered documentation.
+const FormatYAML = "yaml"
+
+// MarshalYAML returns the YAML representation of the document.
+func MarshalYAML(document *parser.Document) ([]byte, error) {
+	// The JSON representation is converted to YAML, so both formats use the
+	// same field names in the same order
+	output, err := json.Marshal(NewJSONDocument(document))
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(output, &node); err != nil {
+		return nil, err
+	}
+	resetYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// resetYAMLStyle replaces the JSON styles of the parsed nodes (flow mappings
+// and quoted strings) with block styles, multi-line strings are written as
+// literal blocks so their lines are diffed separately.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && strings.Contains(node.Value, "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
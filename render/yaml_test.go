@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderYAML(t *testing.T) {
+	values := `# +docs:section=Image
+
+# The image tag.
+#
+# Empty uses the appVersion.
+tag: "true"
+# Extra args
+args:
+  - --v=2
+`
+
+	document, err := parser.Parse(strings.NewReader(values), t.TempDir(), false)
+	require.NoError(t, err)
+
+	output, err := RenderWithOptions("markdown-plain", document, Options{Format: FormatYAML})
+	require.NoError(t, err)
+
+	require.Contains(t, output, "      - path: tag\n        anchor: tag\n        setPath: tag\n        description:\n          text: |-\n            The image tag.\n\n            Empty uses the appVersion.\n")
+	require.Contains(t, output, "        default: '\"true\"'\n")
+
+	// The output has the same structure as the JSON output
+	jsonOutput, err := MarshalDocument(document)
+	require.NoError(t, err)
+
+	var fromYAML, fromJSON any
+	require.NoError(t, yaml.Unmarshal([]byte(output), &fromYAML))
+	require.NoError(t, yaml.Unmarshal(jsonOutput, &fromJSON))
+	require.Equal(t, fromJSON, fromYAML)
+}
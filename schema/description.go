@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+)
+
+// The amounts of the description of a property that can be used as the
+// description in the schema, see Options.Description.
+const (
+	DescriptionFull      = "full"
+	DescriptionParagraph = "paragraph"
+	DescriptionSentence  = "sentence"
+)
+
+func (o Options) validateDescription() error {
+	switch o.Description {
+	case "", DescriptionFull, DescriptionParagraph, DescriptionSentence:
+		return nil
+	default:
+		return fmt.Errorf("unknown description length %q, use %s, %s or %s", o.Description, DescriptionFull, DescriptionParagraph, DescriptionSentence)
+	}
+}
+
+// shortDescription returns whether the descriptions in the schema are
+// shortened.
+func (o Options) shortDescription() bool {
+	return o.Description == DescriptionParagraph || o.Description == DescriptionSentence
+}
+
+// description returns the description of the property for the schema, this
+// is the full text or its first paragraph or sentence. Examples are never
+// part of a shortened description.
+func (o Options) description(property parser.Property) string {
+	if !o.shortDescription() {
+		return property.Description.String()
+	}
+
+	paragraph := ""
+	for _, segment := range property.Description.Segments {
+		if segment.Type == heuristics.ContentTypeText {
+			paragraph, _, _ = strings.Cut(strings.TrimSpace(segment.String()), "\n\n")
+			break
+		}
+	}
+
+	if o.Description == DescriptionSentence {
+		return firstSentence(paragraph)
+	}
+
+	return paragraph
+}
+
+// firstSentence returns the text up to the first sentence end, that is a
+// '.', '!' or '?' followed by whitespace and an upper case letter, so
+// abbreviations like "eg. foo" do not end the sentence.
+func firstSentence(text string) string {
+	runes := []rune(text)
+	for i := 0; i < len(runes)-1; i++ {
+		if !strings.ContainsRune(".!?", runes[i]) || !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+
+		next := i + 1
+		for next < len(runes) && unicode.IsSpace(runes[next]) {
+			next++
+		}
+
+		if next < len(runes) && unicode.IsUpper(runes[next]) {
+			return string(runes[:i+1])
+		}
+	}
+
+	return text
+}
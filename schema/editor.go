@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"strings"
+
+	"github.com/cert-manager/helm-tool/heuristics"
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// annotationProps returns the annotation keywords of the property that are
+// not part of spec.SchemaProps: deprecated (from JSON schema 2019-09, which
+// validators of older drafts ignore) and, if enabled, the examples of the
+// +docs:example tags.
+func (o Options) annotationProps(property parser.Property) map[string]interface{} {
+	props := map[string]interface{}{}
+
+	if property.Deprecated() {
+		props["deprecated"] = true
+	}
+
+	if examples := tagExamples(property); o.Examples && len(examples) > 0 {
+		props["examples"] = examples
+	}
+
+	if len(props) == 0 {
+		return nil
+	}
+
+	return props
+}
+
+// editorProps returns the schema properties supported by the YAML language
+// server, see https://github.com/redhat-developer/yaml-language-server
+func editorProps(property parser.Property) map[string]interface{} {
+	props := map[string]interface{}{}
+
+	if description := markdownDescription(property); description != "" {
+		props["markdownDescription"] = description
+	}
+
+	if property.Deprecated() {
+		props["deprecationMessage"] = property.DeprecationMessage()
+	}
+
+	if examples := examples(property); len(examples) > 0 {
+		props["examples"] = examples
+	}
+
+	if len(props) == 0 {
+		return nil
+	}
+
+	return props
+}
+
+// markdownDescription renders the description as Markdown, yaml segments are
+// rendered as code blocks.
+func markdownDescription(property parser.Property) string {
+	var parts []string
+	for _, segment := range property.Description.Segments {
+		switch segment.Type {
+		case heuristics.ContentTypeText:
+			if text := strings.TrimSpace(segment.String()); text != "" {
+				parts = append(parts, text)
+			}
+		case heuristics.ContentTypeYaml:
+			parts = append(parts, "```yaml\n"+segment.String()+"\n```")
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// tagExamples returns the values of the +docs:example tags of the property,
+// examples that are not valid YAML are used as strings.
+func tagExamples(property parser.Property) []interface{} {
+	var result []interface{}
+	for _, value := range property.Examples() {
+		var example interface{}
+		if err := yaml.Unmarshal([]byte(value), &example); err != nil {
+			example = value
+		}
+
+		result = append(result, example)
+	}
+
+	return result
+}
+
+// examples returns the values of the +docs:example tags or, if there are
+// none, the yaml segments in the description. Examples in the description
+// are usually written keyed by the name of the property, in that case only
+// the value is used.
+func examples(property parser.Property) []interface{} {
+	if result := tagExamples(property); len(result) > 0 {
+		return result
+	}
+
+	name := ""
+	if len(property.Path) > 0 {
+		name = paths.SegmentString(property.Path.Property())
+	}
+
+	var result []interface{}
+	for _, segment := range property.Description.Segments {
+		if segment.Type != heuristics.ContentTypeYaml {
+			continue
+		}
+
+		var example interface{}
+		if err := yaml.Unmarshal([]byte(segment.String()), &example); err != nil || example == nil {
+			continue
+		}
+
+		if m, ok := example.(map[string]interface{}); ok && len(m) == 1 {
+			if value, ok := m[name]; ok {
+				example = value
+			}
+		}
+
+		result = append(result, example)
+	}
+
+	return result
+}
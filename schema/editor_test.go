@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditorProps(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(`# The node selector.
+#
+# nodeSelector:
+#   kubernetes.io/os: linux
+#
+# +docs:deprecated=Use affinity instead.
+nodeSelector: {}
+`), t.TempDir(), false)
+	require.NoError(t, err)
+
+	property := document.Sections[0].Properties[0]
+	require.Equal(t, map[string]interface{}{
+		"markdownDescription": "The node selector.\n\n```yaml\nnodeSelector:\n  kubernetes.io/os: linux\n```",
+		"deprecationMessage":  "Use affinity instead.",
+		"examples": []interface{}{
+			map[string]interface{}{"kubernetes.io/os": "linux"},
+		},
+	}, editorProps(property))
+}
@@ -111,7 +111,32 @@ func buildTree(document *parser.Document) (treeLevel, error) {
 	return root, nil
 }
 
+// Options configure how the schema is rendered.
+type Options struct {
+	// Editor adds properties that are used by editors using the YAML language
+	// server (markdownDescription, deprecationMessage and examples), so the
+	// full documentation is shown in hovers.
+	Editor bool
+	// Examples adds the examples set using +docs:example tags as the
+	// examples keyword.
+	Examples bool
+	// Description is the amount of the description of a property that is
+	// used as its description in the schema, see DescriptionFull (the
+	// default), DescriptionParagraph and DescriptionSentence. Editors show
+	// long descriptions poorly, the rendered documentation always contains
+	// the full description.
+	Description string
+}
+
 func Render(document *parser.Document) (string, error) {
+	return RenderWithOptions(document, Options{})
+}
+
+func RenderWithOptions(document *parser.Document, options Options) (string, error) {
+	if err := options.validateDescription(); err != nil {
+		return "", err
+	}
+
 	tree, err := buildTree(document)
 	if err != nil {
 		return "", err
@@ -130,7 +155,7 @@ func Render(document *parser.Document) (string, error) {
 		}
 
 		if level.Property != nil {
-			newSchema.SchemaProps.Description = level.Property.Description.String()
+			newSchema.SchemaProps.Description = options.description(*level.Property)
 
 			if level.Property.Default != "" {
 				var defaultValue interface{}
@@ -139,6 +164,36 @@ func Render(document *parser.Document) (string, error) {
 				}
 				newSchema.SchemaProps.Default = defaultValue
 			}
+
+			if defaultFrom := level.Property.DefaultFrom(); defaultFrom != "" {
+				newSchema.AddExtension("x-default-from", defaultFrom)
+			}
+
+			for _, value := range level.Property.Enum() {
+				var enumValue interface{}
+				if err := yaml.Unmarshal([]byte(value), &enumValue); err != nil {
+					enumValue = value
+				}
+				newSchema.SchemaProps.Enum = append(newSchema.SchemaProps.Enum, enumValue)
+			}
+
+			newSchema.ReadOnly = level.Property.ReadOnly()
+			newSchema.ExtraProps = options.annotationProps(*level.Property)
+
+			if options.Editor {
+				for key, value := range editorProps(*level.Property) {
+					if newSchema.ExtraProps == nil {
+						newSchema.ExtraProps = map[string]interface{}{}
+					}
+					newSchema.ExtraProps[key] = value
+				}
+
+				// Editors show the Markdown description instead of the
+				// description, so it is shortened the same way
+				if _, ok := newSchema.ExtraProps["markdownDescription"]; ok && options.shortDescription() {
+					newSchema.ExtraProps["markdownDescription"] = newSchema.SchemaProps.Description
+				}
+			}
 		}
 
 		switch levelType {
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderEnum(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(`# The image pull policy
+# +docs:enum=Always, IfNotPresent,Never
+pullPolicy: IfNotPresent
+# The log level
+# +docs:enum=1,2,3
+logLevel: 2
+`), t.TempDir(), false)
+	require.NoError(t, err)
+
+	rendered, err := Render(document)
+	require.NoError(t, err)
+
+	var result struct {
+		Defs map[string]struct {
+			Enum []interface{} `json:"enum"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(rendered), &result))
+	require.Equal(t, []interface{}{"Always", "IfNotPresent", "Never"}, result.Defs["helm-values.pullPolicy"].Enum)
+	require.Equal(t, []interface{}{1.0, 2.0, 3.0}, result.Defs["helm-values.logLevel"].Enum)
+}
+
+func TestRenderDefaultFrom(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(`# The image tag
+# +docs:default-from=.Chart.AppVersion
+tag: ""
+`), t.TempDir(), false)
+	require.NoError(t, err)
+
+	rendered, err := Render(document)
+	require.NoError(t, err)
+
+	var result struct {
+		Defs map[string]map[string]interface{} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(rendered), &result))
+	require.Equal(t, ".Chart.AppVersion", result.Defs["helm-values.tag"]["x-default-from"])
+	require.Equal(t, "", result.Defs["helm-values.tag"]["default"])
+}
+
+func TestRenderAnnotations(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(`# The node selector.
+#
+# nodeSelector:
+#   kubernetes.io/os: linux
+#
+# +docs:deprecated=Use affinity instead.
+nodeSelector: {}
+# The name of the release
+# +docs:read-only
+releaseName: ""
+# The tolerations
+# +docs:example=[{"key": "dedicated", "operator": "Exists"}]
+tolerations: []
+`), t.TempDir(), false)
+	require.NoError(t, err)
+
+	rendered, err := Render(document)
+	require.NoError(t, err)
+
+	var result struct {
+		Defs map[string]map[string]interface{} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(rendered), &result))
+	require.Equal(t, true, result.Defs["helm-values.nodeSelector"]["deprecated"])
+	require.NotContains(t, result.Defs["helm-values.nodeSelector"], "examples")
+	require.NotContains(t, result.Defs["helm-values.tolerations"], "examples")
+	require.NotContains(t, result.Defs["helm-values.nodeSelector"], "deprecationMessage")
+	require.NotContains(t, result.Defs["helm-values.nodeSelector"], "readOnly")
+	require.Equal(t, true, result.Defs["helm-values.releaseName"]["readOnly"])
+	require.NotContains(t, result.Defs["helm-values.releaseName"], "deprecated")
+
+	// The examples of the tags are only added if enabled, examples in the
+	// descriptions are only used by editors
+	rendered, err = RenderWithOptions(document, Options{Examples: true})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(rendered), &result))
+	require.NotContains(t, result.Defs["helm-values.nodeSelector"], "examples")
+	require.Equal(t, []interface{}{[]interface{}{map[string]interface{}{"key": "dedicated", "operator": "Exists"}}}, result.Defs["helm-values.tolerations"]["examples"])
+}
+
+func TestRenderDescription(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader(`# The number of replicas. Use eg. 3 for high availability.
+# More replicas use more resources.
+#
+# Replicas are spread over the nodes.
+# For example:
+# replicaCount: 3
+replicaCount: 1
+`), t.TempDir(), false)
+	require.NoError(t, err)
+
+	descriptions := map[string]string{}
+	for _, length := range []string{DescriptionFull, DescriptionParagraph, DescriptionSentence} {
+		rendered, err := RenderWithOptions(document, Options{Editor: true, Description: length})
+		require.NoError(t, err)
+
+		var result struct {
+			Defs map[string]map[string]interface{} `json:"$defs"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(rendered), &result))
+
+		descriptions[length] = result.Defs["helm-values.replicaCount"]["description"].(string)
+		if length != DescriptionFull {
+			require.Equal(t, descriptions[length], result.Defs["helm-values.replicaCount"]["markdownDescription"])
+		}
+	}
+
+	require.Contains(t, descriptions[DescriptionFull], "replicaCount: 3")
+	require.Equal(t, "The number of replicas. Use eg. 3 for high availability.\nMore replicas use more resources.", descriptions[DescriptionParagraph])
+	require.Equal(t, "The number of replicas.", descriptions[DescriptionSentence])
+
+	_, err = RenderWithOptions(document, Options{Description: "short"})
+	require.EqualError(t, err, `unknown description length "short", use full, paragraph or sentence`)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logPrefixExp matches the timestamp the standard logger adds to messages.
+var logPrefixExp = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(\.\d+)? `)
+
+// Summary is a machine-readable summary of a single run, it is written as
+// JSON so build systems and bots can report the results without parsing the
+// output of the command.
+type Summary struct {
+	Command    string        `json:"command"`
+	Success    bool          `json:"success"`
+	ExitCode   int           `json:"exitCode"`
+	Files      []FileWritten `json:"files"`
+	Warnings   []string      `json:"warnings"`
+	LintIssues int           `json:"lintIssues"`
+	Duration   float64       `json:"durationSeconds"`
+
+	mu    sync.Mutex
+	start time.Time
+}
+
+// FileWritten describes a file that was written during the run.
+type FileWritten struct {
+	Path         string `json:"path"`
+	BytesChanged int    `json:"bytesChanged"`
+}
+
+// New returns a summary for the command, the duration is measured from now.
+func New(command string) *Summary {
+	return &Summary{
+		Command:  command,
+		Files:    []FileWritten{},
+		Warnings: []string{},
+		start:    time.Now(),
+	}
+}
+
+// FileWritten records that the file at path was changed from before to
+// after.
+func (s *Summary) FileWritten(path string, before, after []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Files = append(s.Files, FileWritten{Path: path, BytesChanged: BytesChanged(before, after)})
+}
+
+// Warning records a warning.
+func (s *Summary) Warning(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Warnings = append(s.Warnings, message)
+}
+
+// WarningWriter returns a writer that records every line written to it as a
+// warning, and passes it through to w. This can be used as the output of the
+// standard logger.
+func (s *Summary) WarningWriter(w io.Writer) io.Writer {
+	return warningWriter{summary: s, out: w}
+}
+
+// LintIssuesFound adds to the number of lint issues.
+func (s *Summary) LintIssuesFound(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.LintIssues += n
+}
+
+// Finish records the exit code and the duration of the run.
+func (s *Summary) Finish(exitCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Success = exitCode == 0
+	s.ExitCode = exitCode
+	s.Duration = time.Since(s.start).Seconds()
+}
+
+// WriteFile writes the summary as JSON to path.
+func (s *Summary) WriteFile(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// BytesChanged returns the size of the region that differs between before
+// and after, ignoring their common prefix and suffix.
+func BytesChanged(before, after []byte) int {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix && before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+
+	return max(len(before), len(after)) - prefix - suffix
+}
+
+type warningWriter struct {
+	summary *Summary
+	out     io.Writer
+}
+
+func (w warningWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line = strings.TrimSpace(logPrefixExp.ReplaceAllString(line, "")); line != "" {
+			w.summary.Warning(line)
+		}
+	}
+
+	return w.out.Write(p)
+}
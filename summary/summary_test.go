@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesChanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		expected int
+	}{
+		{name: "Equal", before: "abc", after: "abc", expected: 0},
+		{name: "Created", before: "", after: "abc", expected: 3},
+		{name: "Replaced", before: "tag: v1\n", after: "tag: v22\n", expected: 2},
+		{name: "Removed", before: "a\nb\nc\n", after: "a\nc\n", expected: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, BytesChanged([]byte(test.before), []byte(test.after)))
+		})
+	}
+}
+
+func TestWarningWriter(t *testing.T) {
+	s := New("render")
+
+	var out bytes.Buffer
+	logger := log.New(s.WarningWriter(&out), "", log.LstdFlags)
+	logger.Printf("override for %q does not match any property\n", "a.b")
+
+	require.Equal(t, []string{`override for "a.b" does not match any property`}, s.Warnings)
+	require.Contains(t, out.String(), "does not match any property")
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package telemetry helps charts report which documented values users
+// override, so maintainers can prioritize documentation and deprecations
+// based on real usage. Values are reported using stable IDs instead of their
+// paths, the IDs do not change when a value is renamed.
+package telemetry
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/cert-manager/helm-tool/paths"
+)
+
+// Mapping maps the paths of the documented values of a chart to their
+// stable IDs.
+type Mapping map[string]string
+
+// ID returns the stable ID of a property: its ID from the sidecar file of
+// property IDs if it was set using parser.Document.SetIDs, or else the same
+// ID computed from the path the property was first documented under (see
+// parser.NewPropertyID).
+func ID(property parser.Property) string {
+	if property.ID != "" {
+		return property.ID
+	}
+
+	return parser.NewPropertyID(property)
+}
+
+// NewMapping returns the mapping of the paths of the properties of the
+// document to their stable IDs.
+func NewMapping(document *parser.Document) Mapping {
+	mapping := Mapping{}
+	for _, section := range document.Sections {
+		for _, property := range section.Properties {
+			mapping[property.Path.String()] = ID(property)
+		}
+	}
+
+	return mapping
+}
+
+// LoadMapping reads a mapping written as JSON, eg. by the telemetry-ids
+// command.
+func LoadMapping(filename string) (Mapping, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapping Mapping
+	if err := json.Unmarshal(contents, &mapping); err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+// OverriddenIDs returns the sorted IDs of the documented values that are set
+// in the values of a user, eg. the values of a release. Setting a value to
+// its default also counts as overriding it, as the user chose the value.
+func (m Mapping) OverriddenIDs(values map[string]any) ([]string, error) {
+	var ids []string
+	for path, id := range m {
+		parsed, err := paths.Parse(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := parser.LookupValue(values, parsed); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
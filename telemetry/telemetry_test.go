@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/cert-manager/helm-tool/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverriddenIDs(t *testing.T) {
+	before, err := parser.Parse(strings.NewReader(`image:
+  # The image tag
+  tag: v1
+# Replicas
+replicas: 1
+`), t.TempDir(), false)
+	require.NoError(t, err)
+
+	after, err := parser.Parse(strings.NewReader(`image:
+  # The image tag
+  # +docs:alias=image.tag
+  version: v1
+# Replicas
+replicas: 1
+# Extra args
+# +docs:property
+args: []
+`), t.TempDir(), false)
+	require.NoError(t, err)
+
+	// Renamed values keep their ID
+	beforeMapping, afterMapping := NewMapping(before), NewMapping(after)
+	require.Equal(t, beforeMapping["image.tag"], afterMapping["image.version"])
+	require.Equal(t, beforeMapping["replicas"], afterMapping["replicas"])
+	require.Len(t, afterMapping["args"], 12)
+
+	ids, err := afterMapping.OverriddenIDs(map[string]any{
+		"image": map[string]any{"version": "v2"},
+		"args":  []any{"--v=2"},
+		"other": true,
+	})
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{afterMapping["image.version"], afterMapping["args"]}, ids)
+	require.True(t, sort.StringsAreSorted(ids))
+}
+
+func TestID(t *testing.T) {
+	document, err := parser.Parse(strings.NewReader("# Replicas\nreplicas: 1\n"), t.TempDir(), false)
+	require.NoError(t, err)
+
+	// The IDs are the IDs of the sidecar file of property IDs
+	property := document.Sections[0].Properties[0]
+	require.Equal(t, parser.NewPropertyID(property), ID(property))
+
+	document.SetIDs(&parser.PropertyIDs{Properties: map[string]parser.PropertyID{"replicas": {ID: "0123456789ab"}}}, "v1.0.0")
+	require.Equal(t, "0123456789ab", ID(document.Sections[0].Properties[0]))
+}
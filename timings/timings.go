@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timings
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Timings records the duration and memory use of the phases of a run (eg.
+// parse, render and inject), so performance regressions can be spotted.
+//
+// All methods can be called on a nil *Timings, in which case nothing is
+// recorded. This allows the phases to be measured unconditionally.
+type Timings struct {
+	mu       sync.Mutex
+	phases   []Phase
+	peakHeap uint64
+	start    time.Time
+}
+
+// Phase is a single measured phase, phases with the same name are added up.
+type Phase struct {
+	Name     string
+	Duration time.Duration
+	// Allocated is the number of bytes allocated during the phase.
+	Allocated uint64
+}
+
+// New returns timings that measure the total duration from now.
+func New() *Timings {
+	return &Timings{start: time.Now()}
+}
+
+// Start starts measuring the phase, the returned function ends it.
+func (t *Timings) Start(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	return func() {
+		duration := time.Since(start)
+
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.peakHeap = max(t.peakHeap, before.HeapAlloc, after.HeapAlloc)
+		for i := range t.phases {
+			if t.phases[i].Name == name {
+				t.phases[i].Duration += duration
+				t.phases[i].Allocated += after.TotalAlloc - before.TotalAlloc
+				return
+			}
+		}
+
+		t.phases = append(t.phases, Phase{
+			Name:      name,
+			Duration:  duration,
+			Allocated: after.TotalAlloc - before.TotalAlloc,
+		})
+	}
+}
+
+// Phases returns the measured phases in the order they were first started.
+func (t *Timings) Phases() []Phase {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Phase(nil), t.phases...)
+}
+
+// Write writes the duration and allocations of every phase, the total
+// duration and the peak heap size measured at the start and end of the
+// phases. The name identifies the chart the timings are for.
+func (t *Timings) Write(w io.Writer, name string) error {
+	if t == nil {
+		return nil
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := []string{fmt.Sprintf("timings for %s:", name)}
+	for _, phase := range t.phases {
+		lines = append(lines, fmt.Sprintf("  %-10s %10s  %10s allocated", phase.Name, phase.Duration.Round(time.Microsecond), FormatBytes(phase.Allocated)))
+	}
+	lines = append(lines,
+		fmt.Sprintf("  %-10s %10s", "total", time.Since(t.start).Round(time.Microsecond)),
+		fmt.Sprintf("  peak heap %s, obtained from the OS %s", FormatBytes(max(t.peakHeap, stats.HeapAlloc)), FormatBytes(stats.Sys)),
+	)
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FormatBytes formats a number of bytes using binary units, eg. "1.5MiB".
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+
+	value, suffix := float64(n)/unit, "KiB"
+	for _, next := range []string{"MiB", "GiB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+
+	return fmt.Sprintf("%.1f%s", value, suffix)
+}
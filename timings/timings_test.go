@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timings
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimings(t *testing.T) {
+	timings := New()
+
+	timings.Start("parse")()
+	stop := timings.Start("render")
+	_ = make([]byte, 1<<20)
+	stop()
+	timings.Start("parse")()
+
+	phases := timings.Phases()
+	require.Len(t, phases, 2)
+	require.Equal(t, "parse", phases[0].Name)
+	require.Equal(t, "render", phases[1].Name)
+
+	var sb strings.Builder
+	require.NoError(t, timings.Write(&sb, "values.yaml"))
+	require.True(t, strings.HasPrefix(sb.String(), "timings for values.yaml:\n  parse"), sb.String())
+	require.Contains(t, sb.String(), "  total")
+	require.Contains(t, sb.String(), "  peak heap")
+}
+
+func TestNilTimings(t *testing.T) {
+	var timings *Timings
+
+	timings.Start("parse")()
+	require.Nil(t, timings.Phases())
+
+	var sb strings.Builder
+	require.NoError(t, timings.Write(&sb, "values.yaml"))
+	require.Empty(t, sb.String())
+}
+
+func TestFormatBytes(t *testing.T) {
+	require.Equal(t, "512B", FormatBytes(512))
+	require.Equal(t, "1.5KiB", FormatBytes(1536))
+	require.Equal(t, "2.0MiB", FormatBytes(2<<20))
+	require.Equal(t, "3.0GiB", FormatBytes(3<<30))
+}